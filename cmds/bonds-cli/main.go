@@ -1,11 +1,11 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
 	"strings"
 	"time"
 
@@ -13,6 +13,8 @@ import (
 	"github.com/konimarti/fixedincome"
 	"github.com/konimarti/fixedincome/pkg/instrument/bond"
 	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/refdata"
 	"github.com/konimarti/fixedincome/pkg/term"
 )
 
@@ -27,29 +29,99 @@ var (
 	fileFlag       = flag.String("f", "term.json", "json file containing the parameters for term structure")
 	option         = strings.Join([]string{"day count convention for accured interest, available: ", strings.Join(daycount.Implemented(), ", ")}, "")
 	daycountname   = flag.String("daycount", "30E360", option)
+	govtFlag       = flag.String("govt", "", "json file containing the government curve for the G-spread (default: term structure of -f)")
+	swapFlag       = flag.String("swap", "", "json file containing the swap curve for the I-spread and asset-swap spreads (default: term structure of -f)")
+	floatingFlag   = flag.Bool("floating", false, "floating-rate bond with the coupon as the current rate; reports the discount margin")
+	margin         = flag.Float64("margin", 0.0, "quoted margin in basepoints over the index of a floating-rate bond")
+	bondsFlag      = flag.String("bonds", "", "bond reference data file (json, CSV or SIX ref.csv) to look up the bond given by -isin")
+	isinFlag       = flag.String("isin", "", "ISIN of the bond in the reference data file (replaces -maturity, -coupon, -n, -redemption and -daycount)")
+	outputFlag     = flag.String("output", "table", output.Usage)
 )
 
+// lookup returns the bond with the ISIN from the reference data file
+func lookup(name, isin string) (refdata.Bond, error) {
+	bonds, err := refdata.Load(name)
+	if err != nil {
+		return refdata.Bond{}, err
+	}
+	for _, b := range bonds {
+		if b.ISIN == isin {
+			return b, nil
+		}
+	}
+	return refdata.Bond{}, fmt.Errorf("bond %s not found in %s", isin, name)
+}
+
+// discountMargin calculates the discount margin of a floating-rate bond
+// paying the coupon as the current rate for the quoted clean price
+func discountMargin(schedule maturity.Schedule, clean float64, ts term.Structure) (float64, error) {
+	floater := bond.Floating{
+		Schedule:   schedule,
+		Rate:       *coupon,
+		Redemption: *redemption,
+	}
+	return fixedincome.DiscountMargin(clean+floater.Accrued(), &floater, *margin, ts)
+}
+
+// readCurve reads the term structure from a json file
+func readCurve(name string) (term.Structure, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return term.Parse(data)
+}
+
+// valuation contains the prices, yields and spreads of the bond; the spreads
+// are zero for floating-rate bonds and the margins for fixed-coupon bonds
+type valuation struct {
+	ISIN       string  `json:"isin"`
+	Issuer     string  `json:"issuer"`
+	Settlement string  `json:"settlement"`
+	Maturity   string  `json:"maturity"`
+	Years      float64 `json:"years"`
+	Duration   float64 `json:"duration"`
+	Coupon     float64 `json:"coupon" format:"%.2f"`
+	Frequency  int     `json:"frequency"`
+	Basis      string  `json:"basis"`
+	Days       int     `json:"days"`
+	Spread     float64 `json:"spread" format:"%.2f"`
+	Dirty      float64 `json:"dirty"`
+	Accrued    float64 `json:"accrued"`
+	Clean      float64 `json:"clean"`
+	// Quoted is true if the yields are calculated for the quoted price
+	// instead of the clean price
+	Quoted         bool    `json:"quoted"`
+	Quote          float64 `json:"quote"`
+	Invoice        float64 `json:"invoice"`
+	Yield          float64 `json:"yield"`
+	YieldToWorst   float64 `json:"yieldtoworst"`
+	Implied        float64 `json:"implied" format:"%.1f"`
+	Floating       bool    `json:"floating"`
+	Margin         float64 `json:"margin" format:"%.1f"`
+	DiscountMargin float64 `json:"discountmargin" format:"%.1f"`
+	GSpread        float64 `json:"gspread" format:"%.1f"`
+	ISpread        float64 `json:"ispread" format:"%.1f"`
+	ZSpread        float64 `json:"zspread" format:"%.1f"`
+	ParPar         float64 `json:"asw" format:"%.1f"`
+	MarketValue    float64 `json:"aswmarket" format:"%.1f"`
+}
+
 func main() {
 	flag.Parse()
 
-	// read term structure parameters and create NSS model
-	termData, err := ioutil.ReadFile(*fileFlag)
+	format, err := output.Parse(*outputFlag)
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
 	}
 
-	ts, err := term.Parse(termData)
+	// read term structure parameters
+	ts, err := readCurve(*fileFlag)
 	if err != nil {
-		log.Println(err)
-		log.Println("no file given for term structure parameters. Use template for e.g. Nelson-Siegel-Svensson:")
-		data, err := json.MarshalIndent(term.NelsonSiegelSvensson{}, " ", "")
-		if err != nil {
-			panic(err)
-		}
-		fmt.Println(string(data))
-		return
+		data, _ := term.MarshalIndent(&term.NelsonSiegelSvensson{}, " ", "")
+		log.Fatalf("%v\nUse template for e.g. Nelson-Siegel-Svensson:\n%s", err, string(data))
 	}
-	fmt.Println("Term model read from", *fileFlag)
+	log.Println("Term model read from", *fileFlag)
 
 	// parse quote and maturity dates
 	quoteDate, err := time.Parse("2006-01-02", *settlementFlag)
@@ -73,57 +145,112 @@ func main() {
 		Redemption: *redemption,
 	}
 
+	// bond from reference data
+	var ref refdata.Bond
+	if *isinFlag != "" {
+		if ref, err = lookup(*bondsFlag, *isinFlag); err != nil {
+			log.Fatal(err)
+		}
+		straight, err := ref.Straight(quoteDate)
+		if err != nil {
+			log.Fatal(err)
+		}
+		bond = *straight
+		maturityDate = bond.Maturity
+		*coupon = bond.Coupon
+		*frequency = bond.Compounding()
+		*daycountname = bond.Basis
+		if *price == 0.0 {
+			*price = ref.Price
+		}
+		log.Printf("Bond %s (%s) read from %s\n", ref.ISIN, ref.Issuer, *bondsFlag)
+	}
+
 	// set spread
 	ts.SetSpread(*spread)
 
 	// price the bond
-	dirty := bond.PresentValue(ts)
-	clean := dirty - bond.Accrued()
-
-	fmt.Println("")
-	fmt.Printf("Settlement Date  : %s\n", quoteDate.Format("2006-01-02"))
-	fmt.Printf("Maturity Date    : %s\n", maturityDate.Format("2006-01-02"))
-	fmt.Println("")
-	fmt.Printf("Years to Maturity: %.4f years\n", bond.Last())
-	fmt.Printf("Modified duration: %.4f\n", bond.Duration(ts))
-	fmt.Println("")
-	fmt.Printf("Coupon           : %.2f\n", *coupon)
-	fmt.Printf("Frequency        : %d\n", *frequency)
-	fmt.Printf("Day Convention   : %s\n", *daycountname)
-	if days, err := daycount.Days(quoteDate, maturityDate, *daycountname); err == nil {
-		fmt.Printf("Days             : %d\n", int(days))
-	}
-	fmt.Println("")
-	fmt.Printf("Spread           : %.2f\n", *spread)
-
-	fmt.Println("")
-	fmt.Printf("    Dirty Price       %10.4f\n", dirty)
-	fmt.Printf("[-] Accrued Interest  %10.4f\n", bond.Accrued())
-	fmt.Println("----------------------------------")
-	fmt.Printf("[=] Clean Price       %10.4f\n", clean)
-	fmt.Println("==================================")
-	fmt.Println("")
-
-	bondPrice := clean
-	if *price > 0.0 {
-		bondPrice = *price
-		fmt.Println("Yields for the quoted price:")
-	} else {
-		fmt.Println("Yields for the calculated clean price:")
+	v := valuation{
+		ISIN:       ref.ISIN,
+		Issuer:     ref.Issuer,
+		Settlement: quoteDate.Format("2006-01-02"),
+		Maturity:   maturityDate.Format("2006-01-02"),
+		Years:      bond.Last(),
+		Duration:   bond.Duration(ts),
+		Coupon:     *coupon,
+		Frequency:  *frequency,
+		Basis:      *daycountname,
+		Spread:     *spread,
+		Dirty:      bond.PresentValue(ts),
+		Accrued:    bond.Accrued(),
+		Floating:   *floatingFlag,
 	}
-
-	fmt.Printf("  Quoted Price        %10.4f\n", bondPrice)
-	fmt.Printf("  Invoice Price       %10.4f\n", bondPrice+bond.Accrued())
-	irr, err := fixedincome.Irr(bondPrice+bond.Accrued(), &bond)
+	v.Clean = v.Dirty - v.Accrued
+	days, err := daycount.Days(quoteDate, maturityDate, *daycountname)
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Printf("  Yield-to-Maturity   %10.4f %%\n", irr)
+	v.Days = int(days)
 
-	spread, err := fixedincome.Spread(bondPrice+bond.Accrued(), &bond, ts)
-	if err != nil {
+	// yields for the quoted or the calculated clean price
+	v.Quote = v.Clean
+	if *price > 0.0 {
+		v.Quote = *price
+		v.Quoted = true
+	}
+	v.Invoice = v.Quote + v.Accrued
+	if v.Yield, err = fixedincome.Irr(v.Invoice, &bond); err != nil {
+		log.Fatal(err)
+	}
+	v.YieldToWorst = v.Yield
+	if len(ref.Calls) > 0 {
+		calls, err := ref.ToCalls(quoteDate)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, call := range calls {
+			if y, err := fixedincome.Irr(v.Invoice, call); err == nil && y < v.YieldToWorst {
+				v.YieldToWorst = y
+			}
+		}
+	}
+
+	if v.Implied, err = fixedincome.Spread(v.Invoice, &bond, ts); err != nil {
 		log.Fatal(err)
 	}
-	fmt.Printf("  Implied spread      %10.1f bps\n", spread)
 
+	// spread analytics on the unshifted curves
+	ts.SetSpread(0.0)
+	govt, swapCurve := ts, ts
+	if *govtFlag != "" {
+		if govt, err = readCurve(*govtFlag); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *swapFlag != "" {
+		if swapCurve, err = readCurve(*swapFlag); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *floatingFlag {
+		v.Margin = *margin
+		if v.DiscountMargin, err = discountMargin(bond.Schedule, v.Quote, swapCurve); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		analysis, err := fixedincome.Spreads(v.Invoice, &bond, govt, swapCurve)
+		if err != nil {
+			log.Fatal(err)
+		}
+		v.GSpread = analysis.GSpread
+		v.ISpread = analysis.ISpread
+		v.ZSpread = analysis.ZSpread
+		v.ParPar = analysis.ParPar
+		v.MarketValue = analysis.MarketValue
+	}
+
+	if err := output.Write(os.Stdout, format, v); err != nil {
+		log.Fatal(err)
+	}
 }
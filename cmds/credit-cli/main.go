@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/credit"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const DateFmt = "2006-01-02"
+
+var (
+	file           = flag.String("file", "", fmt.Sprintf("CSV file with maturity date (format: %s), coupon, clean price and issuer (default: stdin)", DateFmt))
+	settlementFlag = flag.String("settlement", time.Now().Format(DateFmt), "valuation date / settlement date")
+	fileFlag       = flag.String("f", "term.json", "json file containing the parameters for the risk-free term structure")
+	recovery       = flag.Float64("recovery", 40.0, "recovery rate in percent")
+	marketFlag     = flag.Bool("market", false, "use recovery of market value instead of recovery of par")
+	outputFlag     = flag.String("output", "table", output.Usage)
+	horizonFlag    = flag.String("horizons", "1,2,3,5,7,10", "comma separated horizons in years for the default probabilities")
+)
+
+// probability is the cumulative default probability in percent of the issuer
+// until the horizon in years
+type probability struct {
+	Issuer      string  `json:"issuer"`
+	Bonds       int     `json:"bonds"`
+	Horizon     float64 `json:"horizon" format:"%.1f"`
+	Probability float64 `json:"probability" format:"%.2f"`
+}
+
+func main() {
+	flag.Parse()
+
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// read risk-free term structure
+	termData, err := ioutil.ReadFile(*fileFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ts, err := term.Parse(termData)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlement, err := time.Parse(DateFmt, *settlementFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	horizons := []float64{}
+	for _, h := range strings.Split(*horizonFlag, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(h), 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+		horizons = append(horizons, value)
+	}
+
+	// read bonds
+	var in io.Reader = os.Stdin
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bonds := make(map[string][]bond.Straight)
+	prices := make(map[string][]float64)
+	for i, line := range records {
+		if len(line) < 4 {
+			log.Fatalf("line %d: expected maturity, coupon, price and issuer", i+1)
+		}
+		maturityDate, err := time.Parse(DateFmt, line[0])
+		if err != nil {
+			log.Fatalf("line %d: %v", i+1, err)
+		}
+		coupon, err := strconv.ParseFloat(line[1], 64)
+		if err != nil {
+			log.Fatalf("line %d: %v", i+1, err)
+		}
+		price, err := strconv.ParseFloat(line[2], 64)
+		if err != nil {
+			log.Fatalf("line %d: %v", i+1, err)
+		}
+		if !maturityDate.After(settlement) {
+			continue
+		}
+		b := bond.Straight{
+			Schedule: maturity.Schedule{
+				Settlement: settlement,
+				Maturity:   maturityDate,
+				Frequency:  1,
+				Basis:      "30E360",
+			},
+			Coupon:     coupon,
+			Redemption: 100.0,
+		}
+		issuer := strings.TrimSpace(line[3])
+		bonds[issuer] = append(bonds[issuer], b)
+		prices[issuer] = append(prices[issuer], price+b.Accrued())
+	}
+
+	convention := credit.RecoveryOfPar
+	if *marketFlag {
+		convention = credit.RecoveryOfMarket
+	}
+
+	// bootstrap hazard curve per issuer
+	curves := make(map[string]*credit.HazardCurve)
+	issuers := []string{}
+	for issuer := range bonds {
+		h, err := credit.BootstrapBonds(bonds[issuer], prices[issuer], *recovery, convention, ts)
+		if err != nil {
+			log.Fatalf("%s: %v", issuer, err)
+		}
+		curves[issuer] = h
+		issuers = append(issuers, issuer)
+	}
+	sort.Strings(issuers)
+
+	// cumulative default probabilities
+	report := credit.Report(curves, horizons)
+	probabilities := []probability{}
+	for _, issuer := range issuers {
+		for i, pd := range report[issuer] {
+			probabilities = append(probabilities, probability{
+				Issuer:      issuer,
+				Bonds:       len(bonds[issuer]),
+				Horizon:     horizons[i],
+				Probability: pd,
+			})
+		}
+	}
+	if err := output.Write(os.Stdout, format, probabilities); err != nil {
+		log.Fatal(err)
+	}
+}
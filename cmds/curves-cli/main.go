@@ -0,0 +1,171 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/history"
+	"github.com/konimarti/fixedincome/pkg/importer"
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const DateFmt = "2006-01-02"
+
+var (
+	dirFlag    = flag.String("dir", "curves", "directory of the curve store")
+	nameFlag   = flag.String("name", "CHF", "name of the curve")
+	importFlag = flag.String("import", "", "CSV file with published curve parameters to import into the store")
+	formatFlag = flag.String("format", "snb", "format of the imported file: snb, ecb, treasury or bundesbank")
+	dateFlag   = flag.String("date", "", "print the latest term structure on or before the date (use -output json for the term structure files of the other apps)")
+	tenor      = flag.Float64("tenor", 0.0, "print the time series of the spot rate for the tenor in years")
+	fromFlag   = flag.String("from", "1900-01-01", "start date of the time series")
+	toFlag     = flag.String("to", time.Now().Format(DateFmt), "end date of the time series")
+	outputFlag = flag.String("output", "table", output.Usage)
+	convert    = flag.String("convert", "", "glob pattern of json files without type tag (written by older versions, e.g. 'curves/*/*.json' or term.json) to rewrite with their type tag")
+)
+
+// rate is the spot rate in percent of the tenor at the date
+type rate struct {
+	Date string  `json:"date"`
+	Rate float64 `json:"rate" format:"%.6f"`
+}
+
+// curve describes the curves of a name in the store
+type curve struct {
+	Name   string `json:"name"`
+	Curves int    `json:"curves"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// convertFiles rewrites the term structures of the json files matching the
+// glob pattern with their type tag; tagged files are left unchanged
+func convertFiles(pattern string) error {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no files found for %s", pattern)
+	}
+	converted := 0
+	for _, name := range files {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if _, err := term.Parse(data); err == nil {
+			continue
+		}
+		ts, err := term.ParseLegacy(data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		if data, err = term.MarshalIndent(ts, "", "  "); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		if err := ioutil.WriteFile(name, append(data, '\n'), 0644); err != nil {
+			return err
+		}
+		converted += 1
+	}
+	log.Printf("Converted %d of %d files\n", converted, len(files))
+	return nil
+}
+
+func main() {
+	flag.Parse()
+
+	// convert files of older versions before opening the store
+	if *convert != "" {
+		if err := convertFiles(*convert); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *dateFlag != "" && *tenor > 0.0 {
+		log.Fatal("either print the term structure as of -date or the time series of -tenor")
+	}
+
+	store, err := history.Open(*dirFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// import published parameters
+	if *importFlag != "" {
+		curves, err := importer.Load(*formatFlag, *importFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := store.Import(*nameFlag, curves); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Imported %d curves into %s/%s\n", len(curves), *dirFlag, *nameFlag)
+	}
+
+	// print curve as of date
+	if *dateFlag != "" {
+		date, err := time.Parse(DateFmt, *dateFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		found, ts, ok := store.Latest(*nameFlag, date)
+		if !ok {
+			log.Fatalf("no curve %s on or before %s", *nameFlag, *dateFlag)
+		}
+		log.Printf("curve %s as of %s", *nameFlag, found.Format(DateFmt))
+		// json with the type tag of the term structure for the other apps
+		if format == output.JSON {
+			data, err := term.MarshalIndent(ts, "", "  ")
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(string(data))
+		} else if err := output.Write(os.Stdout, format, ts); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// print time series of a tenor
+	if *tenor > 0.0 {
+		from, err := time.Parse(DateFmt, *fromFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		to, err := time.Parse(DateFmt, *toFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dates, rates := store.Series(*nameFlag, *tenor, from, to)
+		series := []rate{}
+		for i, date := range dates {
+			series = append(series, rate{date.Format(DateFmt), rates[i]})
+		}
+		if err := output.Write(os.Stdout, format, series); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *importFlag == "" && *dateFlag == "" && *tenor <= 0.0 {
+		curves := []curve{}
+		for _, name := range store.Names() {
+			dates := store.Dates(name)
+			curves = append(curves, curve{name, len(dates), dates[0].Format(DateFmt), dates[len(dates)-1].Format(DateFmt)})
+		}
+		if err := output.Write(os.Stdout, format, curves); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFiles are the config files read from the working directory if no
+// config file is given
+var ConfigFiles = []string{"fixedincome.yaml", "fixedincome.yml", "fixedincome.json"}
+
+// config contains the defaults of the flags; the keys of the YAML or json
+// config file are the names of the flags
+type config struct {
+	Curve  string `json:"curve" yaml:"curve"`
+	Date   string `json:"date" yaml:"date"`
+	Output string `json:"output" yaml:"output"`
+
+	Price    priceConfig    `json:"price" yaml:"price"`
+	Fit      fitConfig      `json:"fit" yaml:"fit"`
+	Forward  forwardConfig  `json:"forward" yaml:"forward"`
+	SwapRate swapRateConfig `json:"swaprate" yaml:"swaprate"`
+	Option   optionConfig   `json:"option" yaml:"option"`
+	Simulate simulateConfig `json:"simulate" yaml:"simulate"`
+	Risk     riskConfig     `json:"risk" yaml:"risk"`
+	Serve    serveConfig    `json:"serve" yaml:"serve"`
+}
+
+// defaults returns the config with the default values of the flags
+func defaults() *config {
+	return &config{
+		Curve:  "term.json",
+		Date:   time.Now().Format(DateFmt),
+		Output: "table",
+		Fit: fitConfig{
+			Model:  "nss",
+			Errors: "yield",
+			Loss:   "squared",
+			Method: "neldermead",
+			Knots:  "count",
+			Starts: 1,
+		},
+		Forward: forwardConfig{
+			M:       1.0,
+			Horizon: 10.0,
+		},
+		SwapRate: swapRateConfig{
+			Maturities: floats{2, 3, 4, 5, 6, 7, 8, 9, 10},
+			Frequency:  2,
+		},
+		Option: optionConfig{
+			Type: "call",
+			S:    110.0,
+			K:    100.0,
+			T:    2.0,
+			Vola: 0.3,
+			Rate: 2.0,
+		},
+		Simulate: simulateConfig{
+			Model:      "holee",
+			Sigma:      0.01,
+			Maturities: floats{1, 2, 5, 10},
+			Steps:      100,
+			Nsim:       10000,
+		},
+		Risk: riskConfig{
+			Positions:  "positions.csv",
+			History:    "history/*.json",
+			Name:       "CHF",
+			From:       "1900-01-01",
+			To:         time.Now().Format(DateFmt),
+			Method:     "all",
+			Confidence: floats{0.99, 0.975},
+			Horizon:    10,
+			Nsim:       10000,
+			Model:      "vasicek",
+			Sigma:      0.01,
+		},
+		Serve: serveConfig{
+			Addr: "localhost:8080",
+		},
+	}
+}
+
+// loadConfig reads the config file over the defaults. Without a name the
+// first of the ConfigFiles in the working directory is read, if any.
+func loadConfig(name string) (*config, error) {
+	c := defaults()
+	if name == "" {
+		for _, file := range ConfigFiles {
+			if _, err := os.Stat(file); err == nil {
+				name = file
+				break
+			}
+		}
+		if name == "" {
+			return c, nil
+		}
+	}
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(filepath.Ext(name)) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(c)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(c); err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %v", name, err)
+	}
+	return c, nil
+}
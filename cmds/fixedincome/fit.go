@@ -0,0 +1,185 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/konimarti/fixedincome/pkg/fit"
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+type fitConfig struct {
+	Bonds    string  `json:"bonds" yaml:"bonds"`
+	Model    string  `json:"model" yaml:"model"`
+	Errors   string  `json:"errors" yaml:"errors"`
+	Weighted bool    `json:"weighted" yaml:"weighted"`
+	Loss     string  `json:"loss" yaml:"loss"`
+	Outliers float64 `json:"outliers" yaml:"outliers"`
+	Starts   int     `json:"starts" yaml:"starts"`
+	Method   string  `json:"method" yaml:"method"`
+	OnRate   float64 `json:"onrate" yaml:"onrate"`
+	Knots    string  `json:"knots" yaml:"knots"`
+	Nodes    int     `json:"nodes" yaml:"nodes"`
+	Save     string  `json:"save" yaml:"save"`
+}
+
+// errorTypes maps the errors flag to the errors of the fit package
+var errorTypes = map[string]int{
+	"yield": fit.YieldErrors,
+	"price": fit.PriceErrors,
+}
+
+// strategies maps the knots flag to the strategies of the term package
+var strategies = map[string]int{
+	"equal": term.EqualSpacing,
+	"count": term.EqualCount,
+	"log":   term.LogSpacing,
+}
+
+func fitFlags(fs *flag.FlagSet, c *config) {
+	fs.StringVar(&c.Fit.Bonds, "bonds", c.Fit.Bonds, fmt.Sprintf("bond reference data file (json, CSV or SIX ref.csv) with the quoted prices; default: CSV on stdin with maturity date (format: %s), coupon and clean price", DateFmt))
+	fs.StringVar(&c.Fit.Model, "model", c.Fit.Model, "model: ns, nss, cubic, monotone, hermite, smoothing, flatspread, linearspread or nsspread (spreads over the curve)")
+	fs.StringVar(&c.Fit.Errors, "errors", c.Fit.Errors, "errors to minimize: yield or price")
+	fs.BoolVar(&c.Fit.Weighted, "weighted", c.Fit.Weighted, "weight the price errors with the inverse squared durations")
+	fs.StringVar(&c.Fit.Loss, "loss", c.Fit.Loss, "loss function of the errors: squared, huber or tukey")
+	fs.Float64Var(&c.Fit.Outliers, "outliers", c.Fit.Outliers, "exclude bonds with errors above this many robust standard deviations (0.0 deactivates the exclusion)")
+	fs.IntVar(&c.Fit.Starts, "starts", c.Fit.Starts, "number of starting values per decay parameter of the Nelson-Siegel(-Svensson) fit")
+	fs.StringVar(&c.Fit.Method, "method", c.Fit.Method, "optimization method: neldermead or lm (Levenberg-Marquardt, requires price errors)")
+	fs.Float64Var(&c.Fit.OnRate, "onrate", c.Fit.OnRate, "overnight rate in % (0.0 deactivates the constraint)")
+	fs.StringVar(&c.Fit.Knots, "knots", c.Fit.Knots, "knot placement of the splines: equal, count or log")
+	fs.IntVar(&c.Fit.Nodes, "nodes", c.Fit.Nodes, "number of interior knots of the spline (0 uses half the number of bonds)")
+	fs.StringVar(&c.Fit.Save, "save", c.Fit.Save, "json file to save the fitted term structure to (e.g. for -curve)")
+}
+
+// fitCurve fits the model to the quoted bond prices; the curve is the base of
+// spread models and the starting point of Nelson-Siegel-Svensson fits
+func fitCurve(c *config) error {
+	settlement, err := c.settlement()
+	if err != nil {
+		return err
+	}
+	list, err := readBonds(c.Fit.Bonds)
+	if err != nil {
+		return err
+	}
+	bonds := []fit.Bond{}
+	for _, b := range list {
+		if b.Price <= 0.0 {
+			continue
+		}
+		straight, err := b.Straight(settlement)
+		if err != nil {
+			return err
+		}
+		if !straight.Maturity.After(settlement) {
+			continue
+		}
+		bonds = append(bonds, fit.Bond{ID: b.ID(), Issuer: b.Issuer, Straight: straight, Price: b.Price})
+	}
+
+	model, ok := fit.Models[c.Fit.Model]
+	if !ok {
+		return fmt.Errorf("unknown model %s", c.Fit.Model)
+	}
+	options := fit.Options{
+		DurationWeighted: c.Fit.Weighted,
+		OvernightRate:    c.Fit.OnRate,
+		Outliers:         c.Fit.Outliers,
+		Starts:           c.Fit.Starts,
+		Nodes:            c.Fit.Nodes,
+	}
+	if options.Errors, ok = errorTypes[c.Fit.Errors]; !ok {
+		return fmt.Errorf("unknown errors %s", c.Fit.Errors)
+	}
+	if options.Loss, ok = fit.Losses[c.Fit.Loss]; !ok {
+		return fmt.Errorf("unknown loss %s", c.Fit.Loss)
+	}
+	if options.Method, ok = fit.Methods[c.Fit.Method]; !ok {
+		return fmt.Errorf("unknown method %s", c.Fit.Method)
+	}
+	if options.Knots, ok = strategies[c.Fit.Knots]; !ok {
+		return fmt.Errorf("unknown knot placement %s", c.Fit.Knots)
+	}
+
+	// curve (optional)
+	if ts, err := c.curve(); os.IsNotExist(err) {
+		log.Println(err)
+	} else if err != nil {
+		return err
+	} else {
+		options.Base = ts
+		if nss, ok := ts.(*term.NelsonSiegelSvensson); ok && model == fit.NelsonSiegelSvensson {
+			options.Start = []float64{nss.B0, nss.B1, nss.B2, nss.B3, nss.T1, nss.T2}
+		}
+	}
+
+	result, err := fit.New(model, options).Fit(bonds)
+	if err != nil {
+		return err
+	}
+	if c.Fit.Save != "" {
+		data, err := term.MarshalIndent(result.Structure, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(c.Fit.Save, data, 0644); err != nil {
+			return err
+		}
+		log.Printf("term structure saved to %s", c.Fit.Save)
+	}
+	return c.write(report(result))
+}
+
+// summary contains the fit statistics of the model
+type summary struct {
+	Model       string  `json:"model"`
+	Bonds       int     `json:"bonds"`
+	PriceRMSE   float64 `json:"pricermse" format:"%0.4g"`
+	YieldRMSE   float64 `json:"yieldrmse" format:"%0.4g"`
+	Scale       float64 `json:"scale" format:"%0.4g"`
+	Condition   float64 `json:"condition" format:"%0.4g"`
+	Evaluations int     `json:"evaluations"`
+	Outliers    int     `json:"outliers"`
+}
+
+// parameter is a fitted parameter of the model
+type parameter struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value" format:"%0.6g"`
+}
+
+// residual contains the quoted and fitted prices and yields of a bond
+type residual struct {
+	ID          string  `json:"id"`
+	Maturity    float64 `json:"maturity"`
+	Price       float64 `json:"price"`
+	Fitted      float64 `json:"fitted"`
+	Yield       float64 `json:"yield"`
+	FittedYield float64 `json:"fittedyield"`
+	YieldError  float64 `json:"yielderror" format:"%.2f"`
+	Excluded    bool    `json:"excluded"`
+}
+
+// report returns the statistics, parameters and residuals of the fit
+func report(result *fit.Result) output.Sections {
+	parameters := []parameter{}
+	for i, name := range result.Names {
+		parameters = append(parameters, parameter{name, result.Parameters[i]})
+	}
+	residuals := []residual{}
+	for _, r := range result.Residuals {
+		residuals = append(residuals, residual{r.ID, r.Maturity, r.Price, r.Fitted, r.Yield, r.FittedYield, r.YieldError, r.Excluded})
+	}
+	return output.Sections{
+		{Name: "results", Records: summary{
+			fit.ModelName(result.Model), len(result.Residuals), result.PriceRMSE, result.YieldRMSE,
+			result.Scale, result.Condition, result.Evaluations, len(result.Outliers),
+		}},
+		{Name: "parameters", Records: parameters},
+		{Name: "residuals", Records: residuals},
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+
+	"github.com/konimarti/fixedincome/pkg/output"
+)
+
+type forwardConfig struct {
+	// T is the start and M the term of the forward rate in years
+	T       float64 `json:"t" yaml:"t"`
+	M       float64 `json:"m" yaml:"m"`
+	Horizon float64 `json:"horizon" yaml:"horizon"`
+}
+
+func forwardFlags(fs *flag.FlagSet, c *config) {
+	fs.Float64Var(&c.Forward.T, "t", c.Forward.T, "start time for forward rate in decimal years (0.0 reports the curve only)")
+	fs.Float64Var(&c.Forward.M, "m", c.Forward.M, "term maturity of forward rate in decimal years")
+	fs.Float64Var(&c.Forward.Horizon, "horizon", c.Forward.Horizon, "last maturity of the monthly curve in years")
+}
+
+// forwardRate contains the discount factors, spot rates and the forward rate
+// in percent between the start and the end
+type forwardRate struct {
+	Start   float64 `json:"start" format:"%.2f"`
+	End     float64 `json:"end" format:"%.2f"`
+	ZStart  float64 `json:"zstart"`
+	ZEnd    float64 `json:"zend"`
+	F       float64 `json:"f"`
+	RStart  float64 `json:"rstart"`
+	REnd    float64 `json:"rend"`
+	Forward float64 `json:"forward"`
+}
+
+// point is a point on the spot and forward curve
+type point struct {
+	T       float64 `json:"t"`
+	Spot    float64 `json:"spot"`
+	Forward float64 `json:"forward"`
+	Z       float64 `json:"z"`
+	F       float64 `json:"f"`
+}
+
+func forward(c *config) error {
+	ts, err := c.curve()
+	if err != nil {
+		return err
+	}
+	m := c.Forward.M
+	if math.Abs(m) < 1e-16 {
+		return fmt.Errorf("term maturity too small")
+	}
+
+	sections := output.Sections{}
+	t1 := c.Forward.T
+	if math.Abs(t1) > 1e-16 {
+		f := ts.Z(t1+m) / ts.Z(t1)
+		sections = append(sections, output.Section{Name: "forward", Records: forwardRate{
+			Start:   t1,
+			End:     t1 + m,
+			ZStart:  ts.Z(t1),
+			ZEnd:    ts.Z(t1 + m),
+			F:       f,
+			RStart:  ts.Rate(t1),
+			REnd:    ts.Rate(t1 + m),
+			Forward: -math.Log(f) / m * 100.0,
+		}})
+	}
+
+	curve := []point{}
+	for i := 1; float64(i) <= 12.0*c.Forward.Horizon+1e-9; i++ {
+		t := float64(i) / 12.0
+		z := ts.Z(t)
+		f := ts.Z(t+m) / z
+		curve = append(curve, point{t, -math.Log(z) / t * 100.0, -math.Log(f) / m * 100.0, z, f})
+	}
+	sections = append(sections, output.Section{Name: "curve", Records: curve})
+	return c.write(sections)
+}
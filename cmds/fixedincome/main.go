@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const DateFmt = "2006-01-02"
+
+// command is a subcommand with its flags bound to the config
+type command struct {
+	name  string
+	help  string
+	flags func(fs *flag.FlagSet, c *config)
+	run   func(c *config) error
+}
+
+var commands = []command{
+	{"price", "prices a file of bonds against the curve", priceFlags, price},
+	{"fit", "fits a term structure to bond prices", fitFlags, fitCurve},
+	{"forward", "reports the spot and forward rates of the curve", forwardFlags, forward},
+	{"swaprate", "reports the swap rates of the curve", swapRateFlags, swapRate},
+	{"option", "prices a European option and its 'Greeks'", optionFlags, optionPrice},
+	{"simulate", "prices zero bonds by Monte Carlo simulation of a short-rate model", simulateFlags, simulate},
+	{"risk", "reports the value-at-risk and expected shortfall of a portfolio", riskFlags, risk},
+	{"serve", "serves the curves and the pricing of bonds, swaps and options as json over HTTP", serveFlags, serve},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: fixedincome [global flags] <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.help)
+	}
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Global flags (-curve, -date and -output are also accepted after the command):")
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Use 'fixedincome <command> -h' for the flags of a command.")
+}
+
+// globalFlags defines the flags shared by all commands
+func globalFlags(fs *flag.FlagSet, c *config) {
+	fs.StringVar(&c.Curve, "curve", c.Curve, "json file containing the parameters for the term structure")
+	fs.StringVar(&c.Date, "date", c.Date, fmt.Sprintf("valuation date / settlement date (format: %s)", DateFmt))
+	fs.StringVar(&c.Output, "output", c.Output, output.Usage)
+}
+
+func main() {
+	// global flags before the command override the config file
+	global := defaults()
+	configFlag := flag.String("config", "", fmt.Sprintf("YAML or json config file with the defaults of the flags (default: %s in the working directory)", strings.Join(ConfigFiles, ", ")))
+	globalFlags(flag.CommandLine, global)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	c, err := loadConfig(*configFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "curve":
+			c.Curve = global.Curve
+		case "date":
+			c.Date = global.Date
+		case "output":
+			c.Output = global.Output
+		}
+	})
+
+	name := flag.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		fs := flag.NewFlagSet(name, flag.ExitOnError)
+		globalFlags(fs, c)
+		cmd.flags(fs, c)
+		fs.Parse(flag.Args()[1:])
+		if err := cmd.run(c); err != nil {
+			log.Fatalf("%s: %v", name, err)
+		}
+		return
+	}
+	log.Printf("unknown command %s", name)
+	usage()
+	os.Exit(2)
+}
+
+// curve reads the term structure from the curve file
+func (c *config) curve() (term.Structure, error) {
+	data, err := ioutil.ReadFile(c.Curve)
+	if err != nil {
+		return nil, err
+	}
+	return term.Parse(data)
+}
+
+// settlement returns the valuation date
+func (c *config) settlement() (time.Time, error) {
+	return time.Parse(DateFmt, c.Date)
+}
+
+// write writes the records to stdout in the output format
+func (c *config) write(records interface{}) error {
+	format, err := output.Parse(c.Output)
+	if err != nil {
+		return err
+	}
+	return output.Write(os.Stdout, format, records)
+}
+
+// floats are comma separated numbers given by a flag
+type floats []float64
+
+func (f *floats) String() string {
+	values := []string{}
+	for _, value := range *f {
+		values = append(values, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+	return strings.Join(values, ",")
+}
+
+func (f *floats) Set(s string) error {
+	values := floats{}
+	for _, field := range strings.Split(s, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+	*f = values
+	return nil
+}
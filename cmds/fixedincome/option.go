@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/option"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+type optionConfig struct {
+	Type string  `json:"type" yaml:"type"`
+	S    float64 `json:"s" yaml:"s"`
+	K    float64 `json:"k" yaml:"k"`
+	T    float64 `json:"t" yaml:"t"`
+	Q    float64 `json:"q" yaml:"q"`
+	Vola float64 `json:"vola" yaml:"vola"`
+	// Rate is the flat discount rate in percent (continuously compounded)
+	Rate float64 `json:"r" yaml:"r"`
+}
+
+// optionTypes maps the type flag to the option types
+var optionTypes = map[string]int{
+	"call": option.Call,
+	"put":  option.Put,
+}
+
+func optionFlags(fs *flag.FlagSet, c *config) {
+	fs.StringVar(&c.Option.Type, "type", c.Option.Type, "type is either 'call' or 'put'")
+	fs.Float64Var(&c.Option.S, "s", c.Option.S, "current stock price")
+	fs.Float64Var(&c.Option.K, "k", c.Option.K, "strike price")
+	fs.Float64Var(&c.Option.T, "t", c.Option.T, "time to expiration date in years")
+	fs.Float64Var(&c.Option.Q, "q", c.Option.Q, "dividend yield in percent")
+	fs.Float64Var(&c.Option.Vola, "vola", c.Option.Vola, "volatility")
+	fs.Float64Var(&c.Option.Rate, "r", c.Option.Rate, "continuously compounded discount rate in percent assuming flat yield curve")
+}
+
+// optionValuation contains the price and the 'Greeks' of the option
+type optionValuation struct {
+	Type       string  `json:"type"`
+	Stock      float64 `json:"stock"`
+	Strike     float64 `json:"strike"`
+	Maturity   float64 `json:"maturity"`
+	Dividend   float64 `json:"dividend"`
+	Volatility float64 `json:"volatility"`
+	Rate       float64 `json:"rate"`
+	Price      float64 `json:"price"`
+	Delta      float64 `json:"delta"`
+	Gamma      float64 `json:"gamma"`
+	Rho        float64 `json:"rho"`
+	Vega       float64 `json:"vega"`
+}
+
+func optionPrice(c *config) error {
+	o := c.Option
+	optionType, ok := optionTypes[strings.ToLower(o.Type)]
+	if !ok {
+		return fmt.Errorf("unknown option type %s", o.Type)
+	}
+	eu := option.European{Type: optionType, S: o.S, K: o.K, T: o.T, Q: o.Q, Vola: o.Vola}
+	ts := &term.Flat{R: o.Rate}
+	return c.write(optionValuation{
+		Type:       strings.ToLower(o.Type),
+		Stock:      o.S,
+		Strike:     o.K,
+		Maturity:   o.T,
+		Dividend:   o.Q,
+		Volatility: o.Vola,
+		Rate:       o.Rate,
+		Price:      eu.PresentValue(ts),
+		Delta:      eu.Delta(ts),
+		Gamma:      eu.Gamma(ts),
+		Rho:        eu.Rho(ts),
+		Vega:       eu.Vega(ts),
+	})
+}
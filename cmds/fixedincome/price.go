@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/konimarti/fixedincome/pkg/pricing"
+	"github.com/konimarti/fixedincome/pkg/refdata"
+)
+
+type priceConfig struct {
+	Bonds   string             `json:"bonds" yaml:"bonds"`
+	Spread  float64            `json:"spread" yaml:"spread"`
+	Spreads map[string]float64 `json:"spreads" yaml:"spreads"`
+}
+
+func priceFlags(fs *flag.FlagSet, c *config) {
+	fs.StringVar(&c.Price.Bonds, "bonds", c.Price.Bonds, fmt.Sprintf("bond reference data file (json, CSV or SIX ref.csv); default: CSV on stdin with maturity date (format: %s), coupon, clean price and issuer", DateFmt))
+	fs.Float64Var(&c.Price.Spread, "spread", c.Price.Spread, "static spread in bps over the term structure for all bonds")
+	fs.Var((*pricing.Spreads)(&c.Price.Spreads), "spreads", "static spreads in bps by ISIN or issuer, e.g. GKB=35,CH0224396983=20 (replaces -spread)")
+}
+
+// readBonds reads the bonds from the reference data file or, if not given,
+// as comma separated values with the maturity date, coupon, quoted clean
+// price and optionally the issuer from stdin (annual coupons, 30E/360)
+func readBonds(name string) ([]refdata.Bond, error) {
+	if name != "" {
+		return refdata.Load(name)
+	}
+	return refdata.ReadQuotes(os.Stdin)
+}
+
+// price values the bonds; bonds which cannot be priced are reported and
+// skipped before returning the error
+func price(c *config) error {
+	ts, err := c.curve()
+	if err != nil {
+		return err
+	}
+	settlement, err := c.settlement()
+	if err != nil {
+		return err
+	}
+	bonds, err := readBonds(c.Price.Bonds)
+	if err != nil {
+		return err
+	}
+
+	valuations := []pricing.Valuation{}
+	failed := 0
+	for _, b := range bonds {
+		v, err := pricing.Price(b, settlement, ts, pricing.Spreads(c.Price.Spreads).Of(b, c.Price.Spread))
+		if err != nil {
+			log.Printf("%s: %v", b.ID(), err)
+			failed += 1
+			continue
+		}
+		valuations = append(valuations, v)
+	}
+	if err := c.write(valuations); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d bonds not priced", failed, len(bonds))
+	}
+	return nil
+}
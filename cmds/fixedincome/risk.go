@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/konimarti/fixedincome/pkg/mc/model/holee"
+	"github.com/konimarti/fixedincome/pkg/mc/model/vasicek"
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/portfolio"
+	pkgrisk "github.com/konimarti/fixedincome/pkg/risk"
+)
+
+type riskConfig struct {
+	Positions  string  `json:"positions" yaml:"positions"`
+	History    string  `json:"history" yaml:"history"`
+	Store      string  `json:"store" yaml:"store"`
+	Name       string  `json:"name" yaml:"name"`
+	From       string  `json:"from" yaml:"from"`
+	To         string  `json:"to" yaml:"to"`
+	Method     string  `json:"method" yaml:"method"`
+	Confidence floats  `json:"confidence" yaml:"confidence"`
+	Horizon    int     `json:"horizon" yaml:"horizon"`
+	Nsim       int     `json:"nsim" yaml:"nsim"`
+	Model      string  `json:"model" yaml:"model"`
+	Sigma      float64 `json:"sigma" yaml:"sigma"`
+}
+
+func riskFlags(fs *flag.FlagSet, c *config) {
+	fs.StringVar(&c.Risk.Positions, "positions", c.Risk.Positions, "CSV or json file (extension .json) containing the positions")
+	fs.StringVar(&c.Risk.History, "history", c.Risk.History, "glob pattern of the json files with the historical term structures (sorted by file name, the last one is the current term structure)")
+	fs.StringVar(&c.Risk.Store, "store", c.Risk.Store, "directory of the curve store to read the history from instead of json files")
+	fs.StringVar(&c.Risk.Name, "name", c.Risk.Name, "name of the curve in the store")
+	fs.StringVar(&c.Risk.From, "from", c.Risk.From, "start date of the history in the store")
+	fs.StringVar(&c.Risk.To, "to", c.Risk.To, "end date of the history in the store")
+	fs.StringVar(&c.Risk.Method, "method", c.Risk.Method, "method: historical, parametric, montecarlo or all")
+	fs.Var(&c.Risk.Confidence, "confidence", "comma separated confidence levels")
+	fs.IntVar(&c.Risk.Horizon, "horizon", c.Risk.Horizon, "horizon in trading days")
+	fs.IntVar(&c.Risk.Nsim, "nsim", c.Risk.Nsim, "number of Monte Carlo simulations")
+	fs.StringVar(&c.Risk.Model, "model", c.Risk.Model, "short-rate model for Monte Carlo: vasicek or holee")
+	fs.Float64Var(&c.Risk.Sigma, "sigma", c.Risk.Sigma, "volatility of the short rate (e.g. 0.01 for 100bp per year)")
+}
+
+// portfolioValue contains the portfolio value and the number of term
+// structures in the history
+type portfolioValue struct {
+	Settlement string  `json:"settlement"`
+	Value      float64 `json:"value" format:"%.2f"`
+	Curves     int     `json:"curves"`
+}
+
+// measure contains the value-at-risk and expected shortfall of a method
+type measure struct {
+	Method     string  `json:"method"`
+	Confidence float64 `json:"confidence"`
+	Horizon    int     `json:"horizon"`
+	VaR        float64 `json:"var" format:"%.2f"`
+	ES         float64 `json:"es" format:"%.2f"`
+}
+
+// risk reports the value-at-risk with the last term structure of the history
+// as the current term structure
+func risk(c *config) error {
+	r := c.Risk
+	settlement, err := c.settlement()
+	if err != nil {
+		return err
+	}
+	history, err := pkgrisk.ReadHistory(r.History, r.Store, r.Name, r.From, r.To)
+	if err != nil {
+		return err
+	}
+	ts := history[len(history)-1]
+	p, err := portfolio.Load(r.Positions, settlement)
+	if err != nil {
+		return err
+	}
+	value, err := p.Value(ts)
+	if err != nil {
+		return err
+	}
+
+	methods := []string{r.Method}
+	if r.Method == "all" {
+		methods = []string{"historical", "parametric", "montecarlo"}
+	}
+	measures := []measure{}
+	for _, method := range methods {
+		var results []pkgrisk.Result
+		switch method {
+		case "historical":
+			results, err = pkgrisk.Historical(p, history, r.Horizon, r.Confidence)
+		case "parametric":
+			var cov [][]float64
+			var exposures []float64
+			if cov, err = pkgrisk.Covariance(history, pkgrisk.Tenors); err == nil {
+				if exposures, err = p.KeyRateExposures(ts, pkgrisk.Tenors); err == nil {
+					results, err = pkgrisk.DeltaNormal(exposures, cov, r.Horizon, r.Confidence)
+				}
+			}
+		case "montecarlo":
+			var sim pkgrisk.Simulator
+			switch r.Model {
+			case "vasicek":
+				var v *vasicek.Vasicek
+				v, err = vasicek.New(ts, r.Sigma, 10.0, 120, nil)
+				sim = pkgrisk.Vasicek{Model: v}
+			case "holee":
+				var hl *holee.HoLee
+				hl, err = holee.New(ts, r.Sigma, 1.0, 12, nil)
+				sim = pkgrisk.HoLee{Model: hl}
+			default:
+				return fmt.Errorf("unknown model %s", r.Model)
+			}
+			if err == nil {
+				results, err = pkgrisk.MonteCarlo(p, ts, sim, r.Horizon, r.Nsim, r.Confidence)
+			}
+		default:
+			return fmt.Errorf("unknown method %s", method)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", method, err)
+		}
+		for _, result := range results {
+			measures = append(measures, measure{method, result.Confidence, result.Horizon, result.VaR, result.ES})
+		}
+	}
+
+	return c.write(output.Sections{
+		{Name: "portfolio", Records: portfolioValue{settlement.Format(DateFmt), value, len(history)}},
+		{Name: "risk", Records: measures},
+	})
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/konimarti/fixedincome/pkg/server"
+)
+
+type serveConfig struct {
+	Addr string `json:"addr" yaml:"addr"`
+	// Curves is the glob pattern of the json files loaded at start; the
+	// curves are named by the file names without extension
+	Curves string `json:"curves" yaml:"curves"`
+}
+
+func serveFlags(fs *flag.FlagSet, c *config) {
+	fs.StringVar(&c.Serve.Addr, "addr", c.Serve.Addr, "address to listen on")
+	fs.StringVar(&c.Serve.Curves, "curves", c.Serve.Curves, "glob pattern of the json files with the curves loaded at start (named by the file name)")
+}
+
+// serve runs the HTTP/json pricing service
+func serve(c *config) error {
+	curves := server.NewCurves()
+	if c.Serve.Curves != "" {
+		files, err := filepath.Glob(c.Serve.Curves)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			if _, err := curves.Put(name, data); err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+		}
+		log.Printf("loaded curves %s", strings.Join(curves.Names(), ", "))
+	}
+	log.Printf("listening on %s", c.Serve.Addr)
+	return http.ListenAndServe(c.Serve.Addr, server.New(curves))
+}
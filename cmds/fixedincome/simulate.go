@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/konimarti/fixedincome/pkg/mc"
+	"github.com/konimarti/fixedincome/pkg/mc/model/holee"
+	"github.com/konimarti/fixedincome/pkg/mc/model/vasicek"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+type simulateConfig struct {
+	Model      string  `json:"model" yaml:"model"`
+	Sigma      float64 `json:"sigma" yaml:"sigma"`
+	Maturities floats  `json:"maturities" yaml:"maturities"`
+	// Steps is the number of time steps per year
+	Steps int `json:"steps" yaml:"steps"`
+	Nsim  int `json:"nsim" yaml:"nsim"`
+	// Seed of the random numbers (0 seeds with the time)
+	Seed int64 `json:"seed" yaml:"seed"`
+}
+
+func simulateFlags(fs *flag.FlagSet, c *config) {
+	fs.StringVar(&c.Simulate.Model, "model", c.Simulate.Model, "short-rate model: holee or vasicek")
+	fs.Float64Var(&c.Simulate.Sigma, "sigma", c.Simulate.Sigma, "volatility of the short rate (e.g. 0.01 for 100bp per year)")
+	fs.Var(&c.Simulate.Maturities, "maturities", "comma separated maturities of the zero bonds in years")
+	fs.IntVar(&c.Simulate.Steps, "steps", c.Simulate.Steps, "number of time steps per year")
+	fs.IntVar(&c.Simulate.Nsim, "nsim", c.Simulate.Nsim, "number of Monte Carlo simulations")
+	fs.Int64Var(&c.Simulate.Seed, "seed", c.Simulate.Seed, "seed of the random numbers (0 seeds with the time)")
+}
+
+// zeroBond contains the Monte Carlo estimate of the zero bond price in percent
+// with the 95% confidence interval and the price from the term structure
+type zeroBond struct {
+	Maturity  float64 `json:"maturity" format:"%.2f"`
+	Estimate  float64 `json:"estimate"`
+	StdError  float64 `json:"stderror"`
+	Lower     float64 `json:"lower"`
+	Upper     float64 `json:"upper"`
+	Reference float64 `json:"reference"`
+}
+
+// model returns the short-rate model calibrated to the term structure which
+// prices the zero bond with maturity t
+func (s simulateConfig) model(ts term.Structure, t float64) (mc.Model, error) {
+	n := int(math.Ceil(t * float64(s.Steps)))
+	if n < 2 {
+		return nil, fmt.Errorf("maturity %v: too few time steps", t)
+	}
+	dt := t / float64(n)
+	payoff := func(rates []float64) float64 {
+		rate := 0.0
+		for i := 0; i < (n - 1); i += 1 {
+			rate += rates[i] * dt
+		}
+		return math.Exp(-rate) * 100.0
+	}
+	switch s.Model {
+	case "holee":
+		hl, err := holee.New(ts, s.Sigma, t, n, payoff)
+		if err == nil && s.Seed != 0 {
+			hl.Rng = rand.New(rand.NewSource(s.Seed))
+		}
+		return hl, err
+	case "vasicek":
+		v, err := vasicek.New(ts, s.Sigma, t, n, payoff)
+		if err == nil && s.Seed != 0 {
+			v.Rng = rand.New(rand.NewSource(s.Seed))
+		}
+		return v, err
+	}
+	return nil, fmt.Errorf("unknown model %s", s.Model)
+}
+
+func simulate(c *config) error {
+	ts, err := c.curve()
+	if err != nil {
+		return err
+	}
+	bonds := []zeroBond{}
+	for _, t := range c.Simulate.Maturities {
+		model, err := c.Simulate.model(ts, t)
+		if err != nil {
+			return err
+		}
+		engine := mc.New(model, c.Simulate.Nsim)
+		if err := engine.Run(); err != nil {
+			return err
+		}
+		b := zeroBond{Maturity: t, Reference: ts.Z(t) * 100.0}
+		if b.Estimate, err = engine.Estimate(); err != nil {
+			return err
+		}
+		if math.IsNaN(b.Estimate) || math.IsInf(b.Estimate, 0) {
+			return fmt.Errorf("maturity %v: %s model did not converge", t, c.Simulate.Model)
+		}
+		if b.StdError, err = engine.StdError(); err != nil {
+			return err
+		}
+		if b.Lower, b.Upper, err = engine.CI(); err != nil {
+			return err
+		}
+		bonds = append(bonds, b)
+	}
+	return c.write(bonds)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+)
+
+type swapRateConfig struct {
+	Spread     float64 `json:"spread" yaml:"spread"`
+	Maturities floats  `json:"maturities" yaml:"maturities"`
+	Frequency  int     `json:"frequency" yaml:"frequency"`
+}
+
+func swapRateFlags(fs *flag.FlagSet, c *config) {
+	fs.Float64Var(&c.SwapRate.Spread, "spread", c.SwapRate.Spread, "spread in bps for yield curve")
+	fs.Var(&c.SwapRate.Maturities, "maturities", "comma separated maturities of the swaps in years")
+	fs.IntVar(&c.SwapRate.Frequency, "frequency", c.SwapRate.Frequency, "payments per year of the fixed leg")
+}
+
+// rate is the swap rate in percent for the maturity in years
+type rate struct {
+	Maturity float64 `json:"maturity" format:"%.1f"`
+	Rate     float64 `json:"rate" format:"%.2f"`
+}
+
+func swapRate(c *config) error {
+	ts, err := c.curve()
+	if err != nil {
+		return err
+	}
+	if c.SwapRate.Frequency <= 0 {
+		return fmt.Errorf("invalid frequency %d", c.SwapRate.Frequency)
+	}
+	ts.SetSpread(c.SwapRate.Spread)
+
+	rates := []rate{}
+	for _, t := range c.SwapRate.Maturities {
+		n := int(math.Round(t * float64(c.SwapRate.Frequency)))
+		if n < 1 {
+			return fmt.Errorf("maturity %v shorter than the payment period", t)
+		}
+		m := []float64{}
+		for i := 1; i <= n; i++ {
+			m = append(m, float64(i)/float64(c.SwapRate.Frequency))
+		}
+		value, err := swap.InterestRate(m, c.SwapRate.Frequency, ts)
+		if err != nil {
+			return fmt.Errorf("maturity %v: %v", t, err)
+		}
+		rates = append(rates, rate{t, value})
+	}
+	return c.write(rates)
+}
@@ -9,78 +9,112 @@ import (
 	"math"
 	"os"
 
+	"github.com/konimarti/fixedincome/pkg/output"
 	"github.com/konimarti/fixedincome/pkg/term"
 )
 
 var (
-	fileFlag = flag.String("f", "term.json", "json file containing the parameters for term structure")
-	maturity = flag.Float64("m", 1.0, "term maturity of forward rate in decimal years")
-	t        = flag.Float64("t", 0.0, "start time for forward rate in decimal years")
+	fileFlag   = flag.String("f", "term.json", "json file containing the parameters for term structure")
+	maturity   = flag.Float64("m", 1.0, "term maturity of forward rate in decimal years")
+	t          = flag.Float64("t", 0.0, "start time for forward rate in decimal years (0.0 reports the curve only)")
+	outputFlag = flag.String("output", "table", output.Usage)
 )
 
+// forward contains the discount factors, spot rates and the forward rate in
+// percent between the start and the end
+type forward struct {
+	Start   float64 `json:"start" format:"%.2f"`
+	End     float64 `json:"end" format:"%.2f"`
+	ZStart  float64 `json:"zstart"`
+	ZEnd    float64 `json:"zend"`
+	F       float64 `json:"f"`
+	RStart  float64 `json:"rstart"`
+	REnd    float64 `json:"rend"`
+	Forward float64 `json:"forward"`
+}
+
+// point is a point on the spot and forward curve
+type point struct {
+	T       float64 `json:"t"`
+	Spot    float64 `json:"spot"`
+	Forward float64 `json:"forward"`
+	Z       float64 `json:"z"`
+	F       float64 `json:"f"`
+}
+
 func main() {
 	// read input files
 	flag.Parse()
 
-	// read smetarting term structure
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// read starting term structure
 	termData, err := ioutil.ReadFile(*fileFlag)
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
 	}
 
 	ts, err := term.Parse(termData)
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
 	}
 
 	// term maturity
 	m := *maturity
 	if math.Abs(m) < 1e-16 {
-		panic("Term maturity too small")
+		log.Fatal("term maturity too small")
 	}
 
 	// calculate
+	sections := output.Sections{}
 	t1 := *t
 	if math.Abs(t1) > 1e-16 {
-
-		fmt.Println("Discount Factors and Forward Rate")
-		fmt.Println("")
-		fmt.Printf("Z(0,%4.2f)\t\t%3.4f\n", t1, ts.Z(t1))
 		Ffac := ts.Z(t1+m) / ts.Z(t1)
-		fmt.Printf("F(0,%4.2f,%4.2f)\t\t%3.4f\n", t1, t1+m, Ffac)
-		fmt.Printf("Z(0,%4.2f)\t\t%3.4f\n", t1+m, ts.Z(t1+m))
-		fmt.Println("------------------------------")
-		fmt.Printf("r(0,%4.2f)\t\t%3.4f%%\n", t1, ts.Rate(t1))
-		fmt.Printf("f(0,%4.2f,%4.2f)\t\t%3.4f%%\n", t1, t1+m, -math.Log(Ffac)/m*100.0)
-		fmt.Printf("r(0,%4.2f)\t\t%3.4f%%\n", t1+m, ts.Rate(t1+m))
-		fmt.Println("------------------------------")
-
-	}
-
-	output := [][]string{
-		[]string{"x", "SpotRate", "FwdRate", "Z", "F"},
+		sections = append(sections, output.Section{Name: "forward", Records: forward{
+			Start:   t1,
+			End:     t1 + m,
+			ZStart:  ts.Z(t1),
+			ZEnd:    ts.Z(t1 + m),
+			F:       Ffac,
+			RStart:  ts.Rate(t1),
+			REnd:    ts.Rate(t1 + m),
+			Forward: -math.Log(Ffac) / m * 100.0,
+		}})
 	}
 
+	curve := []point{}
 	for i := 1; i <= 10*12; i++ {
 		t := float64(i) * 1.0 / 12.0
 
 		z := ts.Z(t)
 		f := ts.Z(t+m) / z
 
-		spot := -math.Log(z) / t * 100.0
-		fwd := -math.Log(f) / m * 100.0
-
-		output = append(output, []string{
-			fmt.Sprintf("%v", t),
-			fmt.Sprintf("%v", spot),
-			fmt.Sprintf("%v", fwd),
-			fmt.Sprintf("%v", z),
-			fmt.Sprintf("%v", f),
+		curve = append(curve, point{
+			T:       t,
+			Spot:    -math.Log(z) / t * 100.0,
+			Forward: -math.Log(f) / m * 100.0,
+			Z:       z,
+			F:       f,
 		})
-
 	}
+	sections = append(sections, output.Section{Name: "curve", Records: curve})
 
-	// write to file
+	// write to file for analysis.R
+	records := [][]string{
+		[]string{"x", "SpotRate", "FwdRate", "Z", "F"},
+	}
+	for _, p := range curve {
+		records = append(records, []string{
+			fmt.Sprintf("%v", p.T),
+			fmt.Sprintf("%v", p.Spot),
+			fmt.Sprintf("%v", p.Forward),
+			fmt.Sprintf("%v", p.Z),
+			fmt.Sprintf("%v", p.F),
+		})
+	}
 	name := "forward.csv"
 	fout, err := os.Create(name)
 	if err != nil {
@@ -88,6 +122,11 @@ func main() {
 	}
 	defer fout.Close()
 	w := csv.NewWriter(fout)
-	w.WriteAll(output) // calls Flush internally
+	if err := w.WriteAll(records); err != nil { // calls Flush internally
+		log.Fatal(err)
+	}
 
+	if err := output.Write(os.Stdout, format, sections); err != nil {
+		log.Fatal(err)
+	}
 }
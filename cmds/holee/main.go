@@ -12,6 +12,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/konimarti/fixedincome/pkg/output"
 	"github.com/konimarti/fixedincome/pkg/term"
 )
 
@@ -20,21 +21,55 @@ var (
 	nInput        = flag.Int("n", 1000, "number of time steps")
 	maturityInput = flag.Float64("m", 10.0, "maturity in years for simulation")
 	sigmaInput    = flag.Float64("s", 0.02, "standard deviation of interest rates")
+	outputFlag    = flag.String("output", "table", output.Usage)
 )
 
+// parameters of the numerical grid
+type parameters struct {
+	T     float64 `json:"t"`
+	N     int     `json:"n"`
+	Dt    float64 `json:"dt" format:"%g"`
+	Sigma float64 `json:"sigma"`
+}
+
+// point contains the zero bond prices and spot rates in percent of the term
+// structure and of the Ho-Lee model
+type point struct {
+	T      float64 `json:"t" format:"%.2f"`
+	Z      float64 `json:"z" format:"%.6f"`
+	ZHoLee float64 `json:"zholee" format:"%.6f"`
+	R      float64 `json:"r" format:"%.6f"`
+	RHoLee float64 `json:"rholee" format:"%.6f"`
+}
+
+// simulation contains the Monte Carlo estimate of the zero bond price with
+// the 95% confidence interval and the price from the term structure
+type simulation struct {
+	T         float64 `json:"t"`
+	Estimate  float64 `json:"estimate"`
+	Lower     float64 `json:"lower"`
+	Upper     float64 `json:"upper"`
+	Reference float64 `json:"reference"`
+}
+
 func main() {
 	flag.Parse()
 
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// read term structure
 	nssData, err := ioutil.ReadFile(*fileFlag)
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
 	}
 
 	var ts term.NelsonSiegelSvensson
 	err = json.Unmarshal(nssData, &ts)
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
 
 	// define parameters
@@ -43,12 +78,9 @@ func main() {
 	sigma := *sigmaInput
 	dt := T / float64(n)
 
-	// print parameters for info
-	fmt.Println("Parameters")
-	fmt.Println("T    :", T)
-	fmt.Println("n    :", n)
-	fmt.Println("dt   :", dt)
-	fmt.Println("Sigma:", sigma)
+	sections := output.Sections{
+		{Name: "parameters", Records: parameters{T: T, N: n, Dt: dt, Sigma: sigma}},
+	}
 
 	// define numerical grid
 	r := make([]float64, n+2)
@@ -89,13 +121,20 @@ func main() {
 		z[i] = ts.Z(t)
 	}
 
-	// print out current rates vs. Ho-Lee rates
-	fmt.Println("Time\tZ_term\tZ_HoLee")
+	// current rates vs. Ho-Lee rates
+	curve := []point{}
 	for t := 0.5; t <= T; t += 0.5 {
 		i := int((t / dt)) - 1
 		z := math.Exp(-rHoLee[i] * t)
-		fmt.Printf("%6.2f\t%8.6f\t%8.6f\t%8.6f\t%8.6f\n", t, ts.Z(t)*100.0, z*100.0, ts.Rate(t), 100.0*rHoLee[i])
+		curve = append(curve, point{
+			T:      t,
+			Z:      ts.Z(t) * 100.0,
+			ZHoLee: z * 100.0,
+			R:      ts.Rate(t),
+			RHoLee: 100.0 * rHoLee[i],
+		})
 	}
+	sections = append(sections, output.Section{Name: "curve", Records: curve})
 
 	// print out parameters for analysis in R
 	fout, err := os.Create("result.csv")
@@ -104,9 +143,9 @@ func main() {
 	}
 	defer fout.Close()
 	w := csv.NewWriter(fout)
-	output := [][]string{}
+	records := [][]string{}
 	for i := 0; i < n; i += 1 {
-		output = append(output, []string{
+		records = append(records, []string{
 			fmt.Sprintf("%v", float64(i+1)*dt),
 			fmt.Sprintf("%v", r[i]*100.0),
 			fmt.Sprintf("%v", f[i]*100.0),
@@ -116,7 +155,9 @@ func main() {
 			fmt.Sprintf("%v", zHoLee[i]*100.0),
 		})
 	}
-	w.WriteAll(output) // calls Flush internally
+	if err := w.WriteAll(records); err != nil { // calls Flush internally
+		log.Fatal(err)
+	}
 
 	// Monte Carlo pricing with the continuous-time Ho-Lee interest rate model:
 	// Zero Bond
@@ -155,8 +196,15 @@ func main() {
 	}
 	stderror = math.Round(1e4*math.Sqrt(stderror/float64(nsim))/math.Sqrt(float64(nsim))) / 1e4
 
-	fmt.Println("Monte Carlo Z(", t, ")=", estimate)
-	fmt.Println("[", math.Round(1e4*(estimate-1.96*stderror))/1e4, ",", math.Round(1e4*(estimate+1.96*stderror))/1e4, "]")
-	fmt.Println("Reference   Z(", t, ")=", ts.Z(t)*100.0)
+	sections = append(sections, output.Section{Name: "montecarlo", Records: simulation{
+		T:         t,
+		Estimate:  estimate,
+		Lower:     math.Round(1e4*(estimate-1.96*stderror)) / 1e4,
+		Upper:     math.Round(1e4*(estimate+1.96*stderror)) / 1e4,
+		Reference: ts.Z(t) * 100.0,
+	}})
 
+	if err := output.Write(os.Stdout, format, sections); err != nil {
+		log.Fatal(err)
+	}
 }
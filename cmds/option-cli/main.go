@@ -2,10 +2,12 @@ package main
 
 import (
 	"flag"
-	"fmt"
+	"log"
+	"os"
 	"strings"
 
 	"github.com/konimarti/fixedincome/pkg/instrument/option"
+	"github.com/konimarti/fixedincome/pkg/output"
 	"github.com/konimarti/fixedincome/pkg/term"
 )
 
@@ -17,36 +19,73 @@ var (
 	divyield     = flag.Float64("q", 0.0, "dividend yield in percent (default: 0.0)")
 	vola         = flag.Float64("vola", 0.3, "volatility (default: 0.3)")
 	discountRate = flag.Float64("r", 2.0, "continuously compoundd discount rate in percent assuming flat yield curve (default: 2.0)")
+	outputFlag   = flag.String("output", "table", output.Usage)
 )
 
+// valuation contains the price and the 'Greeks' of the option
+type valuation struct {
+	Type       string  `json:"type"`
+	Stock      float64 `json:"stock"`
+	Strike     float64 `json:"strike"`
+	Maturity   float64 `json:"maturity"`
+	Dividend   float64 `json:"dividend"`
+	Volatility float64 `json:"volatility"`
+	Rate       float64 `json:"rate"`
+	Price      float64 `json:"price"`
+	Delta      float64 `json:"delta"`
+	Gamma      float64 `json:"gamma"`
+	Rho        float64 `json:"rho"`
+	Vega       float64 `json:"vega"`
+}
+
 func main() {
 	flag.Parse()
 
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// parse option type
-	optionType := option.Call
-	if strings.ToUpper(*optionT) == "PUT" {
+	var optionType int
+	switch strings.ToLower(*optionT) {
+	case "call":
+		optionType = option.Call
+	case "put":
 		optionType = option.Put
+	default:
+		log.Fatalf("unknown option type %s", *optionT)
 	}
 
 	// create European option
 	euOption := option.European{
-		optionType, *stockPrice, *strikePrice, *maturity, *divyield, *vola,
+		Type: optionType,
+		S:    *stockPrice,
+		K:    *strikePrice,
+		T:    *maturity,
+		Q:    *divyield,
+		Vola: *vola,
 	}
 
 	// create flat yield curve
-	term := term.Flat{*discountRate, 0.0}
+	term := term.Flat{R: *discountRate}
 
-	// print option price and the 'Greeks'
-	if euOption.Type == option.Call {
-		fmt.Println("Call Option")
-	} else {
-		fmt.Println("Put Option")
+	// option price and the 'Greeks'
+	v := valuation{
+		Type:       strings.ToLower(*optionT),
+		Stock:      *stockPrice,
+		Strike:     *strikePrice,
+		Maturity:   *maturity,
+		Dividend:   *divyield,
+		Volatility: *vola,
+		Rate:       *discountRate,
+		Price:      euOption.PresentValue(&term),
+		Delta:      euOption.Delta(&term),
+		Gamma:      euOption.Gamma(&term),
+		Rho:        euOption.Rho(&term),
+		Vega:       euOption.Vega(&term),
+	}
+	if err := output.Write(os.Stdout, format, v); err != nil {
+		log.Fatal(err)
 	}
-	fmt.Printf("Price: %9.4f\n", euOption.PresentValue(&term))
-	fmt.Println("\nThe 'Greeks'")
-	fmt.Printf("Delta: %9.4f\n", euOption.Delta(&term))
-	fmt.Printf("Gamma: %9.4f\n", euOption.Gamma(&term))
-	fmt.Printf("Rho  : %9.4f\n", euOption.Rho(&term))
-	fmt.Printf("Vega : %9.4f\n", euOption.Vega(&term))
-
 }
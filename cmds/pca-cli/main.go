@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/pca"
+	"github.com/konimarti/fixedincome/pkg/risk"
+)
+
+const DateFmt = "2006-01-02"
+
+var (
+	storeFlag      = flag.String("store", "", "directory of the curve store to read the history from instead of json files")
+	nameFlag       = flag.String("name", "CHF", "name of the curve in the store")
+	fromFlag       = flag.String("from", "1900-01-01", "start date of the history in the store")
+	toFlag         = flag.String("to", time.Now().Format(DateFmt), "end date of the history in the store")
+	historyFlag    = flag.String("history", "history/*.json", "glob pattern of the json files with the historical term structures (sorted by file name)")
+	tenorFlag      = flag.String("tenors", "", "comma separated tenors in years (default: 0.25,0.5,1,2,3,5,7,10,15,20,30)")
+	componentsFlag = flag.Int("k", 3, "number of principal components to report")
+	outputFlag     = flag.String("output", "table", output.Usage)
+	shockFlag      = flag.String("shock", "", "comma separated standard deviations per component for a shock scenario, e.g. 2,-1")
+)
+
+func parseFloats(s string) ([]float64, error) {
+	values := []float64{}
+	if strings.TrimSpace(s) == "" {
+		return values, nil
+	}
+	for _, field := range strings.Split(s, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// component contains the standard deviation in bps and the explained
+// variance in percent of a principal component
+type component struct {
+	Component string  `json:"component"`
+	Std       float64 `json:"std" format:"%.2f"`
+	Explained float64 `json:"explained" format:"%.2f"`
+}
+
+// loading is the loading of the principal component for the tenor in years
+type loading struct {
+	Component string  `json:"component"`
+	Tenor     float64 `json:"tenor" format:"%.2f"`
+	Loading   float64 `json:"loading"`
+}
+
+// shock is the change in bps of the rate for the tenor in years
+type shock struct {
+	Tenor  float64 `json:"tenor" format:"%.2f"`
+	Change float64 `json:"change" format:"%.2f"`
+}
+
+func main() {
+	flag.Parse()
+
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tenors, err := parseFloats(*tenorFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	shocks, err := parseFloats(*shockFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	history, err := risk.ReadHistory(*historyFlag, *storeFlag, *nameFlag, *fromFlag, *toFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	a, err := pca.Analyze(history, tenors)
+	if err != nil {
+		log.Fatal(err)
+	}
+	k := *componentsFlag
+	if k > len(a.Variances) {
+		k = len(a.Variances)
+	}
+
+	log.Printf("Principal components of daily changes (%d term structures)\n", len(history))
+
+	names := []string{"Level", "Slope", "Curvature"}
+	components := []component{}
+	loadings := []loading{}
+	explained := a.ExplainedVariance()
+	for i := 0; i < k; i += 1 {
+		name := fmt.Sprintf("PC%d", i+1)
+		if i < len(names) {
+			name = names[i]
+		}
+		components = append(components, component{name, math.Sqrt(a.Variances[i]), explained[i]})
+		for j, tenor := range a.Tenors {
+			loadings = append(loadings, loading{fmt.Sprintf("PC%d", i+1), tenor, a.Loadings[i][j]})
+		}
+	}
+	sections := output.Sections{
+		{Name: "components", Records: components},
+		{Name: "loadings", Records: loadings},
+	}
+
+	// shock scenario (change in bps)
+	if len(shocks) > 0 {
+		changes, err := a.Shock(shocks)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scenario := []shock{}
+		for j, tenor := range a.Tenors {
+			scenario = append(scenario, shock{tenor, changes[j]})
+		}
+		sections = append(sections, output.Section{Name: "shock", Records: scenario})
+	}
+
+	if err := output.Write(os.Stdout, format, sections); err != nil {
+		log.Fatal(err)
+	}
+}
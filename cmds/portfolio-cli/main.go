@@ -0,0 +1,210 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/portfolio"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const DateFmt = "2006-01-02"
+
+var (
+	positionsFlag  = flag.String("positions", "positions.csv", "CSV or json file (extension .json) containing the positions")
+	settlementFlag = flag.String("settlement", time.Now().Format(DateFmt), "valuation date / settlement date")
+	fileFlag       = flag.String("f", "term.json", "json file containing the parameters for the term structure of the base currency")
+	baseFlag       = flag.String("base", "", "base currency of the report")
+	fxFlag         = flag.String("fx", "", "exchange rates into the base currency, e.g. EUR=1.08,USD=0.91")
+	curvesFlag     = flag.String("curves", "", "json files of the term structures of the other currencies than the base currency, e.g. EUR=eur.json,USD=usd.json")
+	outputFlag     = flag.String("output", "table", output.Usage)
+	keyFlag        = flag.String("keyrates", "", "comma separated key rate maturities in years (default: 0.25,0.5,1,2,3,5,7,10,15,20,30)")
+)
+
+func parseFloats(s string) ([]float64, error) {
+	values := []float64{}
+	if strings.TrimSpace(s) == "" {
+		return values, nil
+	}
+	for _, field := range strings.Split(s, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// readCurve reads the term structure from the json file
+func readCurve(name string) (term.Structure, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return term.Parse(data)
+}
+
+// parseCurves reads the term structures by currency, e.g. EUR=eur.json
+func parseCurves(s string) (map[string]term.Structure, error) {
+	curves := make(map[string]term.Structure)
+	if strings.TrimSpace(s) == "" {
+		return curves, nil
+	}
+	for _, field := range strings.Split(s, ",") {
+		pair := strings.SplitN(field, "=", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("invalid term structure %s", field)
+		}
+		ts, err := readCurve(strings.TrimSpace(pair[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", pair[0], err)
+		}
+		curves[strings.TrimSpace(pair[0])] = ts
+	}
+	return curves, nil
+}
+
+func parseFx(s string) (map[string]float64, error) {
+	fx := make(map[string]float64)
+	if strings.TrimSpace(s) == "" {
+		return fx, nil
+	}
+	for _, field := range strings.Split(s, ",") {
+		pair := strings.SplitN(field, "=", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("invalid exchange rate %s", field)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(pair[1]), 64)
+		if err != nil {
+			return nil, err
+		}
+		fx[strings.TrimSpace(pair[0])] = value
+	}
+	return fx, nil
+}
+
+// book contains the valuation and risk figures of a book or of the total
+// portfolio
+type book struct {
+	Book         string  `json:"book"`
+	PresentValue float64 `json:"presentvalue" format:"%.2f"`
+	PVBP         float64 `json:"pvbp" format:"%.2f"`
+	Duration     float64 `json:"duration"`
+	Convexity    float64 `json:"convexity"`
+}
+
+func newBook(name string, s portfolio.Summary) book {
+	return book{name, s.PresentValue, s.PVBP, s.Duration, s.Convexity}
+}
+
+// exposure is the change in value for +1bp of the key rate
+type exposure struct {
+	KeyRate  float64 `json:"keyrate" format:"%.2f"`
+	Exposure float64 `json:"exposure" format:"%.2f"`
+}
+
+// position is the contribution of a position to the portfolio
+type position struct {
+	ID           string  `json:"id"`
+	Book         string  `json:"book"`
+	Currency     string  `json:"currency"`
+	PresentValue float64 `json:"presentvalue" format:"%.2f"`
+	PVBP         float64 `json:"pvbp" format:"%.2f"`
+	Weight       float64 `json:"weight" format:"%.2f"`
+	Duration     float64 `json:"duration"`
+}
+
+func main() {
+	flag.Parse()
+
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ts, err := readCurve(*fileFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlement, err := time.Parse(DateFmt, *settlementFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	keys, err := parseFloats(*keyFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(keys) == 0 {
+		keys = portfolio.KeyRates
+	}
+
+	p, err := portfolio.Load(*positionsFlag, settlement)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fx, err := parseFx(*fxFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := p.SetFx(*baseFlag, fx); err != nil {
+		log.Fatal(err)
+	}
+	if p.Curves, err = parseCurves(*curvesFlag); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Portfolio valuation as of %s (%d positions)\n", settlement.Format(DateFmt), len(p.Positions))
+
+	// books and total
+	summaries := []book{}
+	books := p.Books()
+	for _, name := range portfolio.Keys(books) {
+		s, err := books[name].Summary(ts)
+		if err != nil {
+			log.Fatalf("book %s: %v", name, err)
+		}
+		summaries = append(summaries, newBook(name, s))
+	}
+	total, err := p.Summary(ts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	summaries = append(summaries, newBook("Total", total))
+
+	// key rate exposures (change in value for +1bp)
+	values, err := p.KeyRateExposures(ts, keys)
+	if err != nil {
+		log.Fatal(err)
+	}
+	exposures := []exposure{}
+	for i, value := range values {
+		exposures = append(exposures, exposure{keys[i], value})
+	}
+
+	contributions, err := p.Contributions(ts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	positions := []position{}
+	for _, c := range contributions {
+		positions = append(positions, position{c.ID, c.Book, c.Currency, c.PresentValue, c.PVBP, c.Weight, c.Duration})
+	}
+
+	sections := output.Sections{
+		{Name: "books", Records: summaries},
+		{Name: "keyrates", Records: exposures},
+		{Name: "positions", Records: positions},
+	}
+	if err := output.Write(os.Stdout, format, sections); err != nil {
+		log.Fatal(err)
+	}
+}
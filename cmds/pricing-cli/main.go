@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/pricing"
+	"github.com/konimarti/fixedincome/pkg/refdata"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const DateFmt = "2006-01-02"
+
+var (
+	bondsFlag      = flag.String("bonds", "", fmt.Sprintf("bond reference data file (json, CSV or SIX ref.csv); default: CSV on stdin with maturity date (format: %s), coupon, clean price and issuer", DateFmt))
+	settlementFlag = flag.String("settlement", time.Now().Format(DateFmt), "valuation date / settlement date")
+	fileFlag       = flag.String("f", "term.json", "json file containing the parameters for the term structure")
+	spread         = flag.Float64("spread", 0.0, "static spread in bps over the term structure for all bonds")
+	spreadsFlag    = flag.String("spreads", "", "static spreads in bps by ISIN or issuer, e.g. GKB=35,CH0224396983=20 (replaces -spread)")
+	outputFlag     = flag.String("output", "table", output.Usage)
+)
+
+func main() {
+	flag.Parse()
+
+	termData, err := ioutil.ReadFile(*fileFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ts, err := term.Parse(termData)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlement, err := time.Parse(DateFmt, *settlementFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	spreads, err := pricing.ParseSpreads(*spreadsFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var bonds []refdata.Bond
+	if *bondsFlag != "" {
+		bonds, err = refdata.Load(*bondsFlag)
+	} else {
+		bonds, err = refdata.ReadQuotes(os.Stdin)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// price bonds; bonds which cannot be priced are reported and skipped
+	valuations := []pricing.Valuation{}
+	failed := 0
+	for _, b := range bonds {
+		v, err := pricing.Price(b, settlement, ts, spreads.Of(b, *spread))
+		if err != nil {
+			log.Printf("%s: %v", b.ID(), err)
+			failed += 1
+			continue
+		}
+		valuations = append(valuations, v)
+	}
+
+	if err := output.Write(os.Stdout, format, valuations); err != nil {
+		log.Fatal(err)
+	}
+	if failed > 0 {
+		log.Fatalf("%d of %d bonds not priced", failed, len(bonds))
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/fit"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const DateFmt = "2006-01-02"
+
+var (
+	file           = flag.String("file", "", fmt.Sprintf("CSV file with maturity date (format: %s), coupon, clean price and issuer (default: stdin)", DateFmt))
+	settlementFlag = flag.String("settlement", time.Now().Format(DateFmt), "valuation date / settlement date")
+	fileFlag       = flag.String("f", "term.json", "json file containing the parameters for the base term structure (government or swap curve)")
+	shapeFlag      = flag.String("shape", "linear", "shape of the issuer spread curves: flat, linear or ns (Nelson-Siegel)")
+	outputFlag     = flag.String("output", "table", output.Usage)
+	horizonFlag    = flag.String("horizons", "1,2,3,5,7,10", "comma separated maturities in years for the spreads")
+)
+
+// shapes maps the shape flag to the spread models of the fit package
+var shapes = map[string]int{
+	"flat":   fit.FlatSpread,
+	"linear": fit.LinearSpread,
+	"ns":     fit.NelsonSiegelSpread,
+}
+
+// spread is the spread in bps of the issuer for the horizon in years with
+// the number of bonds and the yield RMSE in bps of the fit
+type spread struct {
+	Issuer  string  `json:"issuer"`
+	Bonds   int     `json:"bonds"`
+	RMSE    float64 `json:"rmse" format:"%.2f"`
+	Horizon float64 `json:"horizon" format:"%.1f"`
+	Spread  float64 `json:"spread" format:"%.1f"`
+}
+
+func main() {
+	flag.Parse()
+
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// read base term structure
+	termData, err := ioutil.ReadFile(*fileFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	base, err := term.Parse(termData)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	settlement, err := time.Parse(DateFmt, *settlementFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	model, ok := shapes[*shapeFlag]
+	if !ok {
+		log.Fatalf("unknown shape %s", *shapeFlag)
+	}
+
+	horizons := []float64{}
+	for _, h := range strings.Split(*horizonFlag, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(h), 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+		horizons = append(horizons, value)
+	}
+
+	// read bonds
+	var in io.Reader = os.Stdin
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bonds := []fit.Bond{}
+	for i, line := range records {
+		if len(line) < 4 {
+			log.Fatalf("line %d: expected maturity, coupon, price and issuer", i+1)
+		}
+		maturityDate, err := time.Parse(DateFmt, line[0])
+		if err != nil {
+			log.Fatalf("line %d: %v", i+1, err)
+		}
+		coupon, err := strconv.ParseFloat(line[1], 64)
+		if err != nil {
+			log.Fatalf("line %d: %v", i+1, err)
+		}
+		price, err := strconv.ParseFloat(line[2], 64)
+		if err != nil {
+			log.Fatalf("line %d: %v", i+1, err)
+		}
+		if !maturityDate.After(settlement) {
+			continue
+		}
+		bonds = append(bonds, fit.Bond{
+			ID:     fmt.Sprintf("%s %v", line[0], coupon),
+			Issuer: strings.TrimSpace(line[3]),
+			Straight: &bond.Straight{
+				Schedule: maturity.Schedule{
+					Settlement: settlement,
+					Maturity:   maturityDate,
+					Frequency:  1,
+					Basis:      "30E360",
+				},
+				Coupon:     coupon,
+				Redemption: 100.0,
+			},
+			Price: price,
+		})
+	}
+
+	// fit spread curve per issuer
+	options := fit.Options{
+		Base:             base,
+		Errors:           fit.PriceErrors,
+		DurationWeighted: true,
+		Method:           fit.LevenbergMarquardt,
+	}
+	results, err := fit.FitIssuers(model, options, bonds)
+	if err != nil {
+		log.Fatal(err)
+	}
+	issuers := []string{}
+	for issuer := range results {
+		issuers = append(issuers, issuer)
+	}
+	sort.Strings(issuers)
+
+	// spreads in bps
+	spreads := []spread{}
+	for _, issuer := range issuers {
+		result := results[issuer]
+		ts := result.Structure.(*term.IssuerSpread)
+		for _, h := range horizons {
+			spreads = append(spreads, spread{
+				Issuer:  issuer,
+				Bonds:   len(result.Residuals),
+				RMSE:    result.YieldRMSE,
+				Horizon: h,
+				Spread:  ts.Credit(h),
+			})
+		}
+	}
+	if err := output.Write(os.Stdout, format, spreads); err != nil {
+		log.Fatal(err)
+	}
+}
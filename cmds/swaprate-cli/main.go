@@ -1,56 +1,65 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
-	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
 
 	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+	"github.com/konimarti/fixedincome/pkg/output"
 	"github.com/konimarti/fixedincome/pkg/term"
 )
 
 var (
-	spread   = flag.Float64("s", 10.0, "spread in bps for yield curve")
-	fileFlag = flag.String("f", "term.json", "json file containing the parameters for the Nelson-Siegel-Svensson term structure")
+	spread     = flag.Float64("s", 10.0, "spread in bps for yield curve")
+	fileFlag   = flag.String("f", "term.json", "json file containing the parameters for the Nelson-Siegel-Svensson term structure")
+	outputFlag = flag.String("output", "table", output.Usage)
 )
 
+// swapRate is the swap rate in percent for the maturity in years
+type swapRate struct {
+	Maturity float64 `json:"maturity" format:"%.1f"`
+	Rate     float64 `json:"rate" format:"%.2f"`
+}
+
 func main() {
 	flag.Parse()
 
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// read term structure parameters
 	termData, err := ioutil.ReadFile(*fileFlag)
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
 	}
 
 	ts, err := term.Parse(termData)
 	if err != nil {
-		log.Println(err)
-		log.Println("Use the following template for the Nelson-Siegel-Svensson yield curve:")
-		data, err := json.MarshalIndent(term.NelsonSiegelSvensson{}, " ", "")
-		if err != nil {
-			panic(err)
-		}
-		fmt.Println(string(data))
-		return
+		data, _ := term.MarshalIndent(&term.NelsonSiegelSvensson{}, " ", "")
+		log.Fatalf("%v\nUse the following template for the Nelson-Siegel-Svensson yield curve:\n%s", err, string(data))
 	}
 
 	// add spread to term structure
 	ts.SetSpread(*spread)
 
 	// calculate swap rate for maturities t
-	fmt.Println("Maturity\tSwap Rate")
+	rates := []swapRate{}
 	for t := 2.0; t <= 10.0; t += 1.0 {
 		m := []float64{}
 		for k := 0.5; k <= t; k += 0.5 {
 			m = append(m, k)
 		}
-		swaprate, err := swap.InterestRate(m, 2, ts)
+		rate, err := swap.InterestRate(m, 2, ts)
 		if err != nil {
-			fmt.Println(err)
+			log.Fatalf("maturity %v: %v", t, err)
 		}
-		fmt.Printf("%2.1f\t\t%6.2f\n", t, swaprate)
+		rates = append(rates, swapRate{Maturity: t, Rate: rate})
+	}
+	if err := output.Write(os.Stdout, format, rates); err != nil {
+		log.Fatal(err)
 	}
 }
@@ -2,306 +2,188 @@ package main
 
 import (
 	"encoding/csv"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"math"
 	"os"
-	"sort"
 	"strconv"
 	"time"
 
-	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/fit"
 	"github.com/konimarti/fixedincome/pkg/instrument/bond"
 	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/output"
 	"github.com/konimarti/fixedincome/pkg/rate"
+	"github.com/konimarti/fixedincome/pkg/refdata"
 	"github.com/konimarti/fixedincome/pkg/term"
-	"gonum.org/v1/gonum/optimize"
 )
 
 const DateFmt = "2006-01-02"
 
-var (
-	bonds  []bond.Straight
-	prices []float64 // "dirty" prices
-	yields []float64 // "dirty" prices
-)
-
 var (
 	file       = flag.String("file", "bonddata.csv", fmt.Sprintf("CSV file for bond data with the following fields: maturity date (format: %s), coupon, price", DateFmt))
+	bondsFlag  = flag.String("bonds", "", "bond reference data file (json, CSV or SIX ref.csv) with the quoted prices (replaces -file)")
 	settlement = flag.String("date", time.Now().Format(DateFmt), fmt.Sprintf("date of the bond prices (format: %s)", DateFmt))
 	onRate     = flag.Float64("onrate", 0.0, "Overnight rate (e.g. Swiss Average Rate Overnight) in % (deactivate it by setting it to 0.0)")
 	fileFlag   = flag.String("f", "term.json", "json file containing the parameters for term structure")
+	splineFlag = flag.String("spline", "cubic", "spline method: cubic (discount factors), monotone (monotone convex), hermite (log-discount factors) or smoothing (B-spline forward curve with roughness penalty)")
+	knotsFlag  = flag.String("knots", "count", "knot placement: equal (spacing), count (equal number of maturities per segment) or log (spacing)")
+	nodes      = flag.Int("nodes", 0, "number of interior knots of the spline (0 uses half the number of bonds)")
+	lambda     = flag.Float64("lambda", 0.0, "roughness penalty of the smoothing spline (0.0 uses the variable roughness penalty of Waggoner)")
+	errorsFlag = flag.String("errors", "yield", "errors to minimize: yield or price")
+	weighted   = flag.Bool("weighted", false, "weight the price errors with the inverse squared durations")
+	lossFlag   = flag.String("loss", "squared", "loss function of the errors: squared, huber or tukey")
+	outliers   = flag.Float64("outliers", 0.0, "exclude bonds with errors above this many robust standard deviations (0.0 deactivates the exclusion)")
+	starts     = flag.Int("starts", 1, "number of starting values per decay parameter of the Nelson-Siegel-Svensson fit")
+	outputFlag = flag.String("output", "table", output.Usage)
+	methodFlag = flag.String("method", "neldermead", "optimization method: neldermead or lm (Levenberg-Marquardt with analytic gradients, requires price errors)")
 )
 
+// strategies maps the knot placement flag to the strategies of the term package
+var strategies = map[string]int{
+	"equal": term.EqualSpacing,
+	"count": term.EqualCount,
+	"log":   term.LogSpacing,
+}
+
+// errorTypes maps the errors flag to the errors of the fit package
+var errorTypes = map[string]int{
+	"yield": fit.YieldErrors,
+	"price": fit.PriceErrors,
+}
+
 func main() {
 	// read input files
 	flag.Parse()
 
-	// read bond data from CSV file
-	filePath := *file
-	f, err := os.Open(filePath)
+	format, err := output.Parse(*outputFlag)
 	if err != nil {
-		log.Fatal("Unable to read input file "+filePath, err)
-	}
-	defer f.Close()
-	csvReader := csv.NewReader(f)
-	records, err := csvReader.ReadAll()
-	if err != nil {
-		log.Fatal("Unable to parse file as CSV for "+filePath, err)
+		log.Fatal(err)
 	}
 
 	lastTradingDay, err := time.Parse(DateFmt, *settlement)
-
-	// read starting term structure
-	termData, err := ioutil.ReadFile(*fileFlag)
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
 	}
 
-	ts, err := term.Parse(termData)
+	var bonds []fit.Bond
+	if *bondsFlag != "" {
+		bonds, err = loadBonds(*bondsFlag, lastTradingDay)
+	} else {
+		bonds, err = readBonds(*file, lastTradingDay)
+	}
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
 	}
 
-	// convert annual O/N rate to a continuously compounded rate
-	onCC := rate.Continuous(*onRate, 360)
-
-	// if an overnight rate is given, use a constraint in the optimization
-	// procedure
-	conOpt := math.Abs(onCC) > 1e-7
-	if conOpt {
-		log.Println("using O/N constraint")
+	errorType, ok := errorTypes[*errorsFlag]
+	if !ok {
+		log.Fatalf("unknown errors %s", *errorsFlag)
 	}
-
-	// initial term structure parameters for the optimization procedure
-	x := []float64{-0.421199, -0.32659, 5.02375, -4.15252, 4.7229, 3.36644}
-
-	if nss, ok := ts.(*term.NelsonSiegelSvensson); ok {
-		log.Println("using model from file")
-		x[0], x[1], x[2], x[3], x[4], x[5] = nss.B0, nss.B1, nss.B2, nss.B3, nss.T1, nss.T2
+	loss, ok := fit.Losses[*lossFlag]
+	if !ok {
+		log.Fatalf("unknown loss %s", *lossFlag)
 	}
-
-	if conOpt {
-		fmt.Println("")
-		fmt.Printf("O/N rate:                         %3.4f %%\n", *onRate)
-		fmt.Printf("continuously compounded O/N rate: %3.4f %%\n", onCC)
-		fmt.Printf("implied starting O/N rate:        %3.4f %%\n", x[0]+x[1])
-		fmt.Println("")
+	method, ok := fit.Methods[*methodFlag]
+	if !ok {
+		log.Fatalf("unknown method %s", *methodFlag)
+	}
+	options := fit.Options{
+		Errors:           errorType,
+		DurationWeighted: *weighted,
+		OvernightRate:    *onRate,
+		Loss:             loss,
+		Outliers:         *outliers,
+		Starts:           *starts,
+		Method:           method,
 	}
 
-	// read in the bonds
-	for _, line := range records[0:] {
-		maturityDay, err := time.Parse(DateFmt, line[0])
-		if err != nil {
-			panic(err)
-		}
-		coupon, err := strconv.ParseFloat(line[1], 64)
-		if err != nil {
-			panic(err)
-		}
-		price, err := strconv.ParseFloat(line[2], 64)
-		if err != nil {
-			panic(err)
-		}
+	// read starting term structure (optional)
+	termStart := term.Structure(nil)
+	if termData, err := ioutil.ReadFile(*fileFlag); os.IsNotExist(err) {
+		log.Println(err)
+	} else if err != nil {
+		log.Fatal(err)
+	} else if ts, err := term.Parse(termData); err != nil {
+		log.Fatal(err)
+	} else if nss, ok := ts.(*term.NelsonSiegelSvensson); ok {
+		log.Println("using model from file")
+		options.Start = []float64{nss.B0, nss.B1, nss.B2, nss.B3, nss.T1, nss.T2}
+		termStart = nss
+	}
 
-		bnd := bond.Straight{
-			Schedule: maturity.Schedule{
-				Settlement: lastTradingDay,
-				Maturity:   maturityDay,
-				Frequency:  1,
-				Basis:      "30E360",
-			},
-			Coupon:     coupon,
-			Redemption: 100.0,
-		}
-		bonds = append(bonds, bnd)
-		// reminder: prices are "dirty"
-		dirty := price + bnd.Accrued()
-		prices = append(prices, dirty)
-		yield, err := fixedincome.Irr(dirty, &bnd)
-		if err != nil {
-			log.Println(err)
-			yield = math.NaN()
+	if *onRate != 0.0 {
+		log.Println("using O/N constraint")
+		log.Printf("O/N rate:                         %3.4f %%\n", *onRate)
+		log.Printf("continuously compounded O/N rate: %3.4f %%\n", rate.Continuous(*onRate, 360))
+		if termStart != nil {
+			log.Printf("implied starting O/N rate:        %3.4f %%\n", termStart.Rate(1.0/360.0))
 		}
-		yields = append(yields, yield)
-
 	}
 
 	// *******************************************************************
 	// optimized NSS
 	// *******************************************************************
-	fun := func(xf []float64) float64 {
-
-		ts := &term.NelsonSiegelSvensson{
-			xf[0], xf[1], xf[2], xf[3], xf[4], xf[5],
-			0.0,
-		}
+	log.Printf("minimize %s loss of %s errors..", *lossFlag, *errorsFlag)
 
-		penalty := 1.0e3
-		scale := float64(len(bonds))
-		sst := 0.0
-
-		// add contraints penalty
-		if conOpt {
-			sst += scale * math.Pow(onCC-(xf[0]+xf[1]), 2.0)
-			if math.Abs(xf[0])+math.Abs(xf[1]) > 10 {
-				sst += penalty
-			}
-		}
-
-		for i, bond := range bonds {
-			// minimize least-squares of yields
-			if math.IsNaN(yields[i]) {
-				continue
-			}
-
-			value := bond.PresentValue(ts)
-			est, err := fixedincome.Irr(value, &bond)
-			if err != nil {
-				log.Printf("yield for bond [%d] not "+
-					"converged\n", i)
-				sst += penalty
-				continue
-			}
-
-			sst += math.Pow(yields[i]-est, 2.0)
-		}
-		return sst
-	}
-
-	termStart := term.NelsonSiegelSvensson{
-		x[0], x[1], x[2], x[3], x[4], x[5],
-		0.0,
-	}
-
-	// solve optimization problem
-	p := optimize.Problem{
-		Func: fun,
-	}
-
-	log.Println("minimize squared error of yields..")
-
-	result, err := optimize.Minimize(p, x, nil, nil)
+	resultNss, err := fit.New(fit.NelsonSiegelSvensson, options).Fit(bonds)
 	if err != nil {
 		log.Fatal(err)
 	}
-	if err = result.Status.Err(); err != nil {
-		log.Fatal(err)
-	}
-	printResult(result)
 
 	log.Println("..done")
 
-	// store optmizated parameters
-	termNss := term.NelsonSiegelSvensson{
-		result.X[0],
-		result.X[1],
-		result.X[2],
-		result.X[3],
-		result.X[4],
-		result.X[5],
-		0.0,
+	termNss := resultNss.Structure
+	if termStart == nil {
+		termStart = termNss
+	}
+	if err := printTermToFile(termNss, "nss_opt.json"); err != nil {
+		log.Fatal(err)
 	}
-
-	fmt.Println("Nelson-Siegel-Svensson term structure:")
-	printTerm(&termNss)
-	printTermToFile(&termNss, "nss_opt.json")
 
 	// *******************************************************************
 	// optimized Spline
 	// *******************************************************************
-
-	// collect all maturities
-	temp := []float64{}
-	xtmap := make(map[float64]bool)
-	for _, bond := range bonds {
-		for _, t := range bond.Schedule.M() {
-			xtmap[t] = true
-		}
-	}
-
-	// sort maturities
-	delete(xtmap, 0.0)
-	for key, _ := range xtmap {
-		temp = append(temp, key)
+	model, ok := fit.Models[*splineFlag]
+	if !ok {
+		log.Fatalf("unknown spline method %s", *splineFlag)
 	}
-	sort.Float64s(temp)
-
-	xt := append([]float64{temp[0]}, determine_weights(temp, len(temp)/int(math.Sqrt(float64(len(bonds)))))...)
-	xt = append(xt, temp[len(temp)-1])
-
-	// define optimization function for the (cubic) splines
-	funSpline := func(y []float64) float64 {
-		ts := term.NewSpline(xt, y, 0.0)
-		sst := 0.0
-		penalty := 1.0e3
-		for i, bond := range bonds {
-			// minimize least-squares of yields
-			if math.IsNaN(yields[i]) {
-				continue
-			}
-
-			value := bond.PresentValue(ts)
-			est, err := fixedincome.Irr(value, &bond)
-			if err != nil {
-				log.Printf("yield for bond [%d] not "+
-					"converged\n", i)
-				sst += penalty
-				continue
-			}
-
-			sst += math.Pow(yields[i]-est, 2.0)
-		}
-		return sst
+	strategy, ok := strategies[*knotsFlag]
+	if !ok {
+		log.Fatalf("unknown knot placement %s", *knotsFlag)
 	}
-
-	// solve optimization problem
-	p = optimize.Problem{
-		Func: funSpline,
+	options.Start = nil
+	options.Knots = strategy
+	options.Nodes = *nodes
+	if *lambda > 0.0 {
+		options.Lambda = func(float64) float64 { return *lambda }
 	}
 
-	// use flat estimates
-	y := make([]float64, len(xt))
-	for i, x := range xt {
-		y[i] = termNss.Z(x)
-	}
-
-	result, err = optimize.Minimize(p, y, nil, nil)
+	resultSpline, err := fit.New(model, options).Fit(bonds)
 	if err != nil {
 		log.Fatal(err)
 	}
-	if err = result.Status.Err(); err != nil {
+
+	termSpline := resultSpline.Structure
+	if err := printTermToFile(termSpline, "spline_opt.json"); err != nil {
 		log.Fatal(err)
 	}
-	printResult(result)
-
-	// print out price comparison
-	termSpline := term.NewSpline(xt, result.X, 0.0)
-	fmt.Println("Cubic spline term structure")
-	fmt.Println("Maturities      :", xt)
-	fmt.Println("Discount Factors:", result.X)
-	// printTerm(termSpline)
-	printTermToFile(termSpline, "spline_opt.json")
 
 	// *******************************************************************
 	// print output
 	// *******************************************************************
-	output := [][]string{}
+	records := [][]string{}
 	for i, bond := range bonds {
-		quoteNss := bond.PresentValue(&termNss) - bond.Accrued()
-		quoteSpline := bond.PresentValue(termSpline) - bond.Accrued()
 		t := bond.Last()
-		output = append(output, []string{
+		records = append(records, []string{
 			fmt.Sprintf("%v", t),
-			fmt.Sprintf("%v", prices[i]),
+			fmt.Sprintf("%v", bond.Price+bond.Accrued()),
 			fmt.Sprintf("%v", termStart.Rate(t)),
 			fmt.Sprintf("%v", termNss.Rate(t)),
 			fmt.Sprintf("%v", termSpline.Rate(t)),
-			fmt.Sprintf("%v", quoteNss),
-			fmt.Sprintf("%v", quoteSpline),
+			fmt.Sprintf("%v", resultNss.Residuals[i].Fitted),
+			fmt.Sprintf("%v", resultSpline.Residuals[i].Fitted),
 			fmt.Sprintf("%v", termNss.Z(t)),
 			fmt.Sprintf("%v", termSpline.Z(t)),
 		})
@@ -315,49 +197,168 @@ func main() {
 	}
 	defer fout.Close()
 	w := csv.NewWriter(fout)
-	w.WriteAll(output) // calls Flush internally
-
-}
+	if err := w.WriteAll(records); err != nil { // calls Flush internally
+		log.Fatal(err)
+	}
 
-func printResult(result *optimize.Result) {
-	fmt.Println("Optimization results:")
-	fmt.Printf("Status: %v\n", result.Status)
-	fmt.Printf("X     : %0.4g\n", result.X)
-	fmt.Printf("F     : %0.4g\n", result.F)
-	fmt.Printf("Stats.FuncEvaluations: %d\n\n", result.Stats.FuncEvaluations)
+	if err := output.Write(os.Stdout, format, report(resultNss, resultSpline)); err != nil {
+		log.Fatal(err)
+	}
 }
 
-func printTerm(ts term.Structure) {
-	text, err := json.MarshalIndent(ts, " ", "")
+// readBonds reads the bonds from the CSV file with the maturity date, coupon
+// and quoted (clean) price
+func readBonds(name string, settlement time.Time) ([]fit.Bond, error) {
+	f, err := os.Open(name)
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, fmt.Errorf("unable to read input file %s: %v", name, err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse file as CSV for %s: %v", name, err)
+	}
+
+	bonds := []fit.Bond{}
+	for i, line := range records {
+		maturityDay, err := time.Parse(DateFmt, line[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		coupon, err := strconv.ParseFloat(line[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		price, err := strconv.ParseFloat(line[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+		bonds = append(bonds, fit.Bond{
+			ID: fmt.Sprintf("%s %v", line[0], coupon),
+			Straight: &bond.Straight{
+				Schedule: maturity.Schedule{
+					Settlement: settlement,
+					Maturity:   maturityDay,
+					Frequency:  1,
+					Basis:      "30E360",
+				},
+				Coupon:     coupon,
+				Redemption: 100.0,
+			},
+			Price: price,
+		})
 	}
-	fmt.Println(string(text))
+	return bonds, nil
 }
 
-func printTermToFile(ts term.Structure, name string) error {
-	data, err := json.MarshalIndent(ts, " ", "")
+// loadBonds reads the bonds with quoted prices and a maturity after the
+// settlement date from the reference data file
+func loadBonds(name string, settlement time.Time) ([]fit.Bond, error) {
+	list, err := refdata.Load(name)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return os.WriteFile(name, data, 0644)
+	bonds := []fit.Bond{}
+	for _, b := range list {
+		if b.Price <= 0.0 {
+			continue
+		}
+		straight, err := b.Straight(settlement)
+		if err != nil {
+			return nil, err
+		}
+		if !straight.Maturity.After(settlement) {
+			continue
+		}
+		bonds = append(bonds, fit.Bond{
+			ID:       b.ID(),
+			Issuer:   b.Issuer,
+			Straight: straight,
+			Price:    b.Price,
+		})
+	}
+	return bonds, nil
+}
+
+// summary contains the fit statistics of a model
+type summary struct {
+	Model       string  `json:"model"`
+	PriceRMSE   float64 `json:"pricermse" format:"%0.4g"`
+	YieldRMSE   float64 `json:"yieldrmse" format:"%0.4g"`
+	Scale       float64 `json:"scale" format:"%0.4g"`
+	Condition   float64 `json:"condition" format:"%0.4g"`
+	Evaluations int     `json:"evaluations"`
+	Outliers    int     `json:"outliers"`
+}
+
+// parameter is a fitted parameter of a model
+type parameter struct {
+	Model string  `json:"model"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value" format:"%0.6g"`
 }
 
-// determine_weights returns n weights that will split t in (almost) equal
-// chunkgs
-func determine_weights(t []float64, n int) []float64 {
-	// initialize with equal weights
-	w := make([]float64, n)
+// residual contains the quoted and fitted prices and yields of a bond
+type residual struct {
+	Model       string  `json:"model"`
+	ID          string  `json:"id"`
+	Maturity    float64 `json:"maturity"`
+	Price       float64 `json:"price"`
+	Fitted      float64 `json:"fitted"`
+	Yield       float64 `json:"yield"`
+	FittedYield float64 `json:"fittedyield"`
+	YieldError  float64 `json:"yielderror" format:"%.2f"`
+	Excluded    bool    `json:"excluded"`
+}
 
-	step := len(t) / (n + 1)
+// outlier is a bond excluded from the fit of a model
+type outlier struct {
+	Model     string  `json:"model"`
+	ID        string  `json:"id"`
+	Maturity  float64 `json:"maturity" format:"%.2f"`
+	Error     float64 `json:"error" format:"%0.4g"`
+	Score     float64 `json:"score" format:"%.2f"`
+	Iteration int     `json:"iteration"`
+}
 
-	for i := 0; i < n; i++ {
-		idx := (i + 1) * step
-		if idx-1 > 0 && idx < len(t) {
-			w[i] = (t[idx] + t[idx-1]) * 0.5
+// report returns the statistics, parameters, residuals and outliers of the
+// fitted models
+func report(results ...*fit.Result) output.Sections {
+	summaries := []summary{}
+	parameters := []parameter{}
+	residuals := []residual{}
+	outliers := []outlier{}
+	for _, result := range results {
+		model := fit.ModelName(result.Model)
+		summaries = append(summaries, summary{
+			model, result.PriceRMSE, result.YieldRMSE, result.Scale, result.Condition,
+			result.Evaluations, len(result.Outliers),
+		})
+		for i, name := range result.Names {
+			parameters = append(parameters, parameter{model, name, result.Parameters[i]})
+		}
+		for _, r := range result.Residuals {
+			residuals = append(residuals, residual{
+				model, r.ID, r.Maturity, r.Price, r.Fitted, r.Yield, r.FittedYield, r.YieldError, r.Excluded,
+			})
+		}
+		for _, o := range result.Outliers {
+			outliers = append(outliers, outlier{model, o.ID, o.Maturity, o.Error, o.Score, o.Iteration})
 		}
 	}
+	return output.Sections{
+		{Name: "results", Records: summaries},
+		{Name: "parameters", Records: parameters},
+		{Name: "residuals", Records: residuals},
+		{Name: "outliers", Records: outliers},
+	}
+}
 
-	return w
+func printTermToFile(ts term.Structure, name string) error {
+	data, err := term.MarshalIndent(ts, " ", "")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0644)
 }
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/mc/model/holee"
+	"github.com/konimarti/fixedincome/pkg/mc/model/vasicek"
+	"github.com/konimarti/fixedincome/pkg/output"
+	"github.com/konimarti/fixedincome/pkg/portfolio"
+	"github.com/konimarti/fixedincome/pkg/risk"
+)
+
+const DateFmt = "2006-01-02"
+
+var (
+	positionsFlag  = flag.String("positions", "positions.csv", "CSV or json file (extension .json) containing the positions")
+	settlementFlag = flag.String("settlement", time.Now().Format(DateFmt), "valuation date / settlement date")
+	storeFlag      = flag.String("store", "", "directory of the curve store to read the history from instead of json files")
+	nameFlag       = flag.String("name", "CHF", "name of the curve in the store")
+	fromFlag       = flag.String("from", "1900-01-01", "start date of the history in the store")
+	toFlag         = flag.String("to", time.Now().Format(DateFmt), "end date of the history in the store")
+	historyFlag    = flag.String("history", "history/*.json", "glob pattern of the json files with the historical term structures (sorted by file name, the last one is the current term structure)")
+	methodFlag     = flag.String("method", "all", "method: historical, parametric, montecarlo or all")
+	levelFlag      = flag.String("confidence", "0.99,0.975", "comma separated confidence levels")
+	horizon        = flag.Int("horizon", 10, "horizon in trading days")
+	nsim           = flag.Int("nsim", 10000, "number of Monte Carlo simulations")
+	modelFlag      = flag.String("model", "vasicek", "short-rate model for Monte Carlo: vasicek or holee")
+	sigma          = flag.Float64("sigma", 0.01, "volatility of the short rate (e.g. 0.01 for 100bp per year)")
+	outputFlag     = flag.String("output", "table", output.Usage)
+)
+
+// summary contains the portfolio value and the number of term structures in
+// the history
+type summary struct {
+	Settlement string  `json:"settlement"`
+	Value      float64 `json:"value" format:"%.2f"`
+	Curves     int     `json:"curves"`
+}
+
+// measure contains the value-at-risk and expected shortfall of a method
+type measure struct {
+	Method     string  `json:"method"`
+	Confidence float64 `json:"confidence"`
+	Horizon    int     `json:"horizon"`
+	VaR        float64 `json:"var" format:"%.2f"`
+	ES         float64 `json:"es" format:"%.2f"`
+}
+
+func main() {
+	flag.Parse()
+
+	format, err := output.Parse(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	levels := []float64{}
+	for _, field := range strings.Split(*levelFlag, ",") {
+		level, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+		levels = append(levels, level)
+	}
+
+	// read history of term structures
+	history, err := risk.ReadHistory(*historyFlag, *storeFlag, *nameFlag, *fromFlag, *toFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ts := history[len(history)-1]
+
+	settlement, err := time.Parse(DateFmt, *settlementFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	p, err := portfolio.Load(*positionsFlag, settlement)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pv, err := p.Value(ts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	value := summary{
+		Settlement: settlement.Format(DateFmt),
+		Value:      pv,
+		Curves:     len(history),
+	}
+
+	measures := []measure{}
+	run := func(method string) {
+		var (
+			results []risk.Result
+			err     error
+		)
+		switch method {
+		case "historical":
+			results, err = risk.Historical(p, history, *horizon, levels)
+		case "parametric":
+			var cov [][]float64
+			var exposures []float64
+			cov, err = risk.Covariance(history, risk.Tenors)
+			if err == nil {
+				if exposures, err = p.KeyRateExposures(ts, risk.Tenors); err == nil {
+					results, err = risk.DeltaNormal(exposures, cov, *horizon, levels)
+				}
+			}
+		case "montecarlo":
+			var sim risk.Simulator
+			switch *modelFlag {
+			case "vasicek":
+				var v *vasicek.Vasicek
+				v, err = vasicek.New(ts, *sigma, 10.0, 120, nil)
+				sim = risk.Vasicek{Model: v}
+			case "holee":
+				var hl *holee.HoLee
+				hl, err = holee.New(ts, *sigma, 1.0, 12, nil)
+				sim = risk.HoLee{Model: hl}
+			default:
+				log.Fatalf("unknown model %s", *modelFlag)
+			}
+			if err == nil {
+				results, err = risk.MonteCarlo(p, ts, sim, *horizon, *nsim, levels)
+			}
+		default:
+			log.Fatalf("unknown method %s", method)
+		}
+		if err != nil {
+			log.Fatalf("%s: %v", method, err)
+		}
+		for _, r := range results {
+			measures = append(measures, measure{method, r.Confidence, r.Horizon, r.VaR, r.ES})
+		}
+	}
+
+	if *methodFlag == "all" {
+		for _, method := range []string{"historical", "parametric", "montecarlo"} {
+			run(method)
+		}
+	} else {
+		run(*methodFlag)
+	}
+
+	sections := output.Sections{
+		{Name: "portfolio", Records: value},
+		{Name: "risk", Records: measures},
+	}
+	if err := output.Write(os.Stdout, format, sections); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -7,4 +7,5 @@ require (
 	github.com/khezen/rootfinding v1.0.1
 	github.com/konimarti/daycount v0.0.3-0.20211210225146-e3e1587af758
 	gonum.org/v1/gonum v0.9.3
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,143 @@
+package credit
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/khezen/rootfinding"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const (
+	// RecoveryOfPar pays the recovery rate times the redemption value at default
+	RecoveryOfPar int = iota
+	// RecoveryOfMarket pays the recovery rate times the pre-default market value
+	RecoveryOfMarket
+)
+
+var (
+	// Precision of the root finding for bootstrapping hazard curves
+	Precision = 8
+	// StepsPerYear is the number of steps per year to integrate over the
+	// default times
+	StepsPerYear = 12
+)
+
+// RiskyBond represents a straight bond of an issuer that may default
+type RiskyBond struct {
+	bond.Straight
+	// Curve is the default risk of the issuer
+	Curve Curve
+	// Recovery is the recovery rate in percent
+	Recovery float64
+	// Convention is either RecoveryOfPar or RecoveryOfMarket
+	Convention int
+}
+
+// PresentValue returns the "dirty" price of the risky bond
+func (b *RiskyBond) PresentValue(ts term.Structure) float64 {
+	if b.Convention == RecoveryOfMarket {
+		return b.recoveryOfMarket(ts)
+	}
+	return b.recoveryOfPar(ts)
+}
+
+// recoveryOfMarket discounts the cash flows with the default-adjusted term
+// structure, i.e. at the loss rate (1-R) * lambda on top of the risk-free rate
+func (b *RiskyBond) recoveryOfMarket(ts term.Structure) float64 {
+	loss := 1.0 - b.Recovery/100.0
+	z := func(t float64) float64 {
+		return ts.Z(t) * math.Pow(b.Curve.Survival(t), loss)
+	}
+
+	pv := 0.0
+	effCoupon := b.EffectiveCoupon(b.Coupon)
+	for _, m := range b.M() {
+		pv += effCoupon * z(m)
+	}
+	pv += b.Redemption * z(b.Last())
+	return pv
+}
+
+// recoveryOfPar discounts the promised cash flows weighted by the survival
+// probabilities plus the recovery of the redemption value at default
+func (b *RiskyBond) recoveryOfPar(ts term.Structure) float64 {
+	pv := 0.0
+	effCoupon := b.EffectiveCoupon(b.Coupon)
+	for _, m := range b.M() {
+		pv += effCoupon * ts.Z(m) * b.Curve.Survival(m)
+	}
+	last := b.Last()
+	pv += b.Redemption * ts.Z(last) * b.Curve.Survival(last)
+
+	// recovery at default
+	pv += b.Recovery / 100.0 * b.Redemption * ProtectionValue(b.Curve, ts, 0.0, last)
+	return pv
+}
+
+// ProtectionValue returns the value of receiving 1 at the time of default
+// between t1 and t2
+func ProtectionValue(c Curve, ts term.Structure, t1, t2 float64) float64 {
+	if t2 <= t1 {
+		return 0.0
+	}
+	n := int(math.Ceil((t2 - t1) * float64(StepsPerYear)))
+	dt := (t2 - t1) / float64(n)
+	value := 0.0
+	s0 := c.Survival(t1)
+	for i := 1; i <= n; i += 1 {
+		t := t1 + float64(i)*dt
+		s1 := c.Survival(t)
+		value += ts.Z(t-0.5*dt) * (s0 - s1)
+		s0 = s1
+	}
+	return value
+}
+
+// BootstrapBonds bootstraps the hazard curve of an issuer from the "dirty"
+// prices of its bonds; the pillar times are the maturities of the bonds
+func BootstrapBonds(bonds []bond.Straight, prices []float64, recovery float64, convention int, ts term.Structure) (*HazardCurve, error) {
+	if len(bonds) != len(prices) {
+		return nil, fmt.Errorf("number of bonds and prices do not match")
+	}
+
+	// sort bonds by maturity
+	idx := make([]int, len(bonds))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool {
+		return bonds[idx[i]].Last() < bonds[idx[j]].Last()
+	})
+
+	h := &HazardCurve{}
+	for _, i := range idx {
+		t := bonds[i].Last()
+		if n := len(h.Times); n > 0 && t-h.Times[n-1] < 1e-6 {
+			// skip bonds with the same maturity
+			continue
+		}
+		h.Times = append(h.Times, t)
+		h.Intensities = append(h.Intensities, 0.0)
+		k := len(h.Intensities) - 1
+
+		risky := RiskyBond{
+			Straight:   bonds[i],
+			Curve:      h,
+			Recovery:   recovery,
+			Convention: convention,
+		}
+		f := func(lambda float64) float64 {
+			h.Intensities[k] = lambda
+			return risky.PresentValue(ts) - prices[i]
+		}
+		root, err := rootfinding.Brent(f, -5.0, 100.0, Precision)
+		if err != nil {
+			return nil, fmt.Errorf("bootstrapping bond %d failed: %v", i, err)
+		}
+		h.Intensities[k] = root
+	}
+	return h, nil
+}
@@ -0,0 +1,81 @@
+package credit_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/credit"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func newBond(years int, coupon float64) bond.Straight {
+	date := time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+	return bond.Straight{
+		Schedule: maturity.Schedule{
+			Settlement: date,
+			Maturity:   date.AddDate(years, 0, 0),
+			Frequency:  1,
+			Basis:      "30E360",
+		},
+		Coupon:     coupon,
+		Redemption: 100.0,
+	}
+}
+
+func TestRiskyBond(t *testing.T) {
+	ts := term.Flat{R: 0.5}
+	h, _ := credit.NewHazardCurve([]float64{10.0}, []float64{2.0})
+
+	// recovery of market value equals discounting with spread (1-R)*lambda
+	risky := credit.RiskyBond{
+		Straight:   newBond(5, 1.0),
+		Curve:      h,
+		Recovery:   40.0,
+		Convention: credit.RecoveryOfMarket,
+	}
+	spreadTerm := term.Flat{R: 0.5, Spread: 0.6 * 2.0 * 100.0}
+	expected := risky.Straight.PresentValue(&spreadTerm)
+	if got := risky.PresentValue(&ts); math.Abs(got-expected) > 1e-9 {
+		t.Errorf("wrong value with recovery of market value; got: %v, expected: %v", got, expected)
+	}
+
+	// zero recovery of par equals discounting with spread lambda
+	risky.Convention = credit.RecoveryOfPar
+	risky.Recovery = 0.0
+	spreadTerm.Spread = 200.0
+	expected = risky.Straight.PresentValue(&spreadTerm)
+	if got := risky.PresentValue(&ts); math.Abs(got-expected) > 1e-9 {
+		t.Errorf("wrong value with zero recovery; got: %v, expected: %v", got, expected)
+	}
+
+	// recovery increases the value
+	risky.Recovery = 40.0
+	if got := risky.PresentValue(&ts); got <= expected {
+		t.Errorf("recovery of par should increase value; got: %v, without recovery: %v", got, expected)
+	}
+}
+
+func TestBootstrapBonds(t *testing.T) {
+	ts := term.Flat{R: 0.5}
+	h, _ := credit.NewHazardCurve([]float64{2.0, 5.0, 10.0}, []float64{0.5, 1.0, 1.5})
+
+	bonds := []bond.Straight{newBond(5, 1.0), newBond(2, 0.5), newBond(10, 1.5)}
+	prices := make([]float64, len(bonds))
+	for i, b := range bonds {
+		risky := credit.RiskyBond{Straight: b, Curve: h, Recovery: 40.0}
+		prices[i] = risky.PresentValue(&ts)
+	}
+
+	fitted, err := credit.BootstrapBonds(bonds, prices, 40.0, credit.RecoveryOfPar, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, lambda := range h.Intensities {
+		if math.Abs(fitted.Intensities[i]-lambda) > 1e-5 {
+			t.Errorf("wrong intensity nr %d; got: %v, expected: %v", i, fitted.Intensities[i], lambda)
+		}
+	}
+}
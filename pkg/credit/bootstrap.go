@@ -0,0 +1,69 @@
+package credit
+
+import (
+	"fmt"
+
+	"github.com/khezen/rootfinding"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// ParSpread returns the running spread in bps of a credit default swap with
+// maturity t (in years) and the given premium frequency per year that sets the
+// premium leg (including accrued premium at default) equal to the protection leg
+func ParSpread(c Curve, ts term.Structure, t float64, frequency int, recovery float64) float64 {
+	annuity := RiskyAnnuity(c, ts, t, frequency)
+	if annuity == 0.0 {
+		return 0.0
+	}
+	return (1.0 - recovery/100.0) * ProtectionValue(c, ts, 0.0, t) / annuity * 10000.0
+}
+
+// RiskyAnnuity returns the value of paying a premium of 1 per year up to
+// maturity t or default including the accrued premium at default, which is
+// approximated by half a period
+func RiskyAnnuity(c Curve, ts term.Structure, t float64, frequency int) float64 {
+	if frequency <= 0 {
+		frequency = 4
+	}
+	dt := 1.0 / float64(frequency)
+	annuity := 0.0
+	for end := t; end > 1e-9; end -= dt {
+		start := end - dt
+		if start < 0.0 {
+			start = 0.0
+		}
+		tau := end - start
+		annuity += tau * ts.Z(end) * c.Survival(end)
+		annuity += 0.5 * tau * ts.Z(0.5*(start+end)) * (c.Survival(start) - c.Survival(end))
+	}
+	return annuity
+}
+
+// BootstrapCDS bootstraps a hazard curve from the par spreads (in bps) of
+// credit default swaps with the given maturities (in years), premium
+// frequency and recovery rate (in percent)
+func BootstrapCDS(maturities, spreads []float64, frequency int, recovery float64, ts term.Structure) (*HazardCurve, error) {
+	if len(maturities) != len(spreads) {
+		return nil, fmt.Errorf("number of maturities and spreads do not match")
+	}
+	h := &HazardCurve{}
+	for i, t := range maturities {
+		if n := len(h.Times); n > 0 && t <= h.Times[n-1] {
+			return nil, fmt.Errorf("maturities are not increasing")
+		}
+		h.Times = append(h.Times, t)
+		h.Intensities = append(h.Intensities, 0.0)
+		k := len(h.Intensities) - 1
+
+		f := func(lambda float64) float64 {
+			h.Intensities[k] = lambda
+			return ParSpread(h, ts, t, frequency, recovery) - spreads[i]
+		}
+		root, err := rootfinding.Brent(f, 0.0, 100.0, Precision)
+		if err != nil {
+			return nil, fmt.Errorf("bootstrapping maturity %v failed: %v", t, err)
+		}
+		h.Intensities[k] = root
+	}
+	return h, nil
+}
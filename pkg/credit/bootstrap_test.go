@@ -0,0 +1,36 @@
+package credit_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/credit"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestBootstrapCDS(t *testing.T) {
+	ts := term.Flat{R: 1.0}
+
+	// credit triangle: spread = (1 - R) * lambda
+	h, _ := credit.NewHazardCurve([]float64{5.0}, []float64{2.0})
+	spread := credit.ParSpread(h, &ts, 5.0, 4, 40.0)
+	if math.Abs(spread-120.0) > 1.0 {
+		t.Errorf("wrong par spread; got: %v, expected: %v", spread, 120.0)
+	}
+
+	maturities := []float64{1.0, 3.0, 5.0, 7.0, 10.0}
+	spreads := []float64{50.0, 75.0, 100.0, 110.0, 120.0}
+
+	curve, err := credit.BootstrapCDS(maturities, spreads, 4, 40.0, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range maturities {
+		if got := credit.ParSpread(curve, &ts, m, 4, 40.0); math.Abs(got-spreads[i]) > 1e-4 {
+			t.Errorf("wrong repriced spread for maturity %v; got: %v, expected: %v", m, got, spreads[i])
+		}
+	}
+	if !(curve.DefaultProbability(10.0) > curve.DefaultProbability(5.0)) {
+		t.Errorf("default probabilities are not increasing")
+	}
+}
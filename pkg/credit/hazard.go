@@ -0,0 +1,105 @@
+package credit
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Curve is the interface for a term structure of default risk
+type Curve interface {
+	// Survival returns the probability of no default up to time t
+	Survival(t float64) float64
+}
+
+// HazardCurve represents a term structure of piecewise constant default
+// intensities (hazard rates). The intensity Intensities[i] (in percent)
+// applies between Times[i-1] and Times[i]; the last intensity is extrapolated.
+type HazardCurve struct {
+	Times       []float64 `json:"times"`
+	Intensities []float64 `json:"intensities"`
+}
+
+// NewHazardCurve returns a hazard curve for the given pillar times and intensities in percent
+func NewHazardCurve(times, intensities []float64) (*HazardCurve, error) {
+	if len(times) != len(intensities) {
+		return nil, fmt.Errorf("number of times and intensities do not match")
+	}
+	if !sort.Float64sAreSorted(times) {
+		return nil, fmt.Errorf("times are not sorted")
+	}
+	h := &HazardCurve{
+		Times:       make([]float64, len(times)),
+		Intensities: make([]float64, len(intensities)),
+	}
+	copy(h.Times, times)
+	copy(h.Intensities, intensities)
+	return h, nil
+}
+
+// Intensity returns the default intensity in percent at time t
+func (h *HazardCurve) Intensity(t float64) float64 {
+	n := len(h.Times)
+	if n == 0 {
+		return 0.0
+	}
+	for i, ti := range h.Times {
+		if t < ti {
+			return h.Intensities[i]
+		}
+	}
+	return h.Intensities[n-1]
+}
+
+// integral returns the cumulative hazard int_0^t lambda(s) ds (as a decimal)
+func (h *HazardCurve) integral(t float64) float64 {
+	sum, last := 0.0, 0.0
+	for i, ti := range h.Times {
+		if t <= ti {
+			return sum + h.Intensities[i]/100.0*(t-last)
+		}
+		sum += h.Intensities[i] / 100.0 * (ti - last)
+		last = ti
+	}
+	if n := len(h.Intensities); n > 0 {
+		sum += h.Intensities[n-1] / 100.0 * (t - last)
+	}
+	return sum
+}
+
+// Survival returns the probability of no default up to time t
+func (h *HazardCurve) Survival(t float64) float64 {
+	if t <= 0.0 {
+		return 1.0
+	}
+	return math.Exp(-h.integral(t))
+}
+
+// DefaultProbability returns the cumulative probability of default up to time t
+func (h *HazardCurve) DefaultProbability(t float64) float64 {
+	return 1.0 - h.Survival(t)
+}
+
+// ForwardDefaultProbability returns the probability of default between t1
+// and t2 conditional on survival up to t1
+func (h *HazardCurve) ForwardDefaultProbability(t1, t2 float64) float64 {
+	s1 := h.Survival(t1)
+	if s1 == 0.0 {
+		return 1.0
+	}
+	return 1.0 - h.Survival(t2)/s1
+}
+
+// Report returns the cumulative default probabilities in percent of each
+// issuer's hazard curve at the given horizons in years
+func Report(curves map[string]*HazardCurve, horizons []float64) map[string][]float64 {
+	report := make(map[string][]float64, len(curves))
+	for issuer, h := range curves {
+		pd := make([]float64, len(horizons))
+		for i, t := range horizons {
+			pd[i] = h.DefaultProbability(t) * 100.0
+		}
+		report[issuer] = pd
+	}
+	return report
+}
@@ -0,0 +1,49 @@
+package credit_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/credit"
+)
+
+func TestHazardCurve(t *testing.T) {
+	h, err := credit.NewHazardCurve([]float64{1.0, 3.0}, []float64{1.0, 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		T         float64
+		Intensity float64
+		Survival  float64
+	}{
+		{0.0, 1.0, 1.0},
+		{0.5, 1.0, math.Exp(-0.005)},
+		{1.0, 2.0, math.Exp(-0.01)},
+		{2.0, 2.0, math.Exp(-0.03)},
+		{5.0, 2.0, math.Exp(-0.09)},
+	}
+
+	for i, test := range testData {
+		if got := h.Intensity(test.T); math.Abs(got-test.Intensity) > 1e-12 {
+			t.Errorf("test nr %d: wrong intensity; got: %v, expected: %v", i, got, test.Intensity)
+		}
+		if got := h.Survival(test.T); math.Abs(got-test.Survival) > 1e-12 {
+			t.Errorf("test nr %d: wrong survival probability; got: %v, expected: %v", i, got, test.Survival)
+		}
+	}
+
+	if got, expected := h.ForwardDefaultProbability(1.0, 2.0), 1.0-math.Exp(-0.02); math.Abs(got-expected) > 1e-12 {
+		t.Errorf("wrong forward default probability; got: %v, expected: %v", got, expected)
+	}
+
+	report := credit.Report(map[string]*credit.HazardCurve{"Issuer": h}, []float64{1.0, 5.0})
+	if pd := report["Issuer"]; len(pd) != 2 || math.Abs(pd[1]-(1.0-math.Exp(-0.09))*100.0) > 1e-9 {
+		t.Errorf("wrong report; got: %v", pd)
+	}
+
+	if _, err := credit.NewHazardCurve([]float64{3.0, 1.0}, []float64{1.0, 2.0}); err == nil {
+		t.Errorf("expected error for unsorted times")
+	}
+}
@@ -0,0 +1,65 @@
+package credit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+)
+
+// riskyBondJSON is the json representation of a risky bond
+type riskyBondJSON struct {
+	Bond       bond.Straight `json:"bond"`
+	Curve      *HazardCurve  `json:"curve"`
+	Recovery   float64       `json:"recovery"`
+	Convention int           `json:"convention"`
+}
+
+// MarshalJSON implements the json.Marshaler interface; the default risk has
+// to be a hazard curve
+func (b RiskyBond) MarshalJSON() ([]byte, error) {
+	curve, err := ToHazardCurve(b.Curve)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(riskyBondJSON{b.Straight, curve, b.Recovery, b.Convention})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (b *RiskyBond) UnmarshalJSON(data []byte) error {
+	var s riskyBondJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	curve, err := FromHazardCurve(s.Curve)
+	if err != nil {
+		return err
+	}
+	*b = RiskyBond{Straight: s.Bond, Curve: curve, Recovery: s.Recovery, Convention: s.Convention}
+	return nil
+}
+
+// ToHazardCurve returns the curve as hazard curve for marshalling to json
+// (nil for a nil curve)
+func ToHazardCurve(c Curve) (*HazardCurve, error) {
+	switch curve := c.(type) {
+	case nil:
+		return nil, nil
+	case *HazardCurve:
+		return curve, nil
+	}
+	return nil, fmt.Errorf("cannot marshal default risk %T", c)
+}
+
+// FromHazardCurve returns the unmarshalled hazard curve as default risk; it
+// returns an error if the curve is missing or invalid
+func FromHazardCurve(h *HazardCurve) (Curve, error) {
+	if h == nil {
+		return nil, fmt.Errorf("default risk curve missing")
+	}
+	curve, err := NewHazardCurve(h.Times, h.Intensities)
+	if err != nil {
+		return nil, fmt.Errorf("default risk curve: %v", err)
+	}
+	return curve, nil
+}
@@ -0,0 +1,52 @@
+package credit_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/credit"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// survival is a default risk that is not a hazard curve
+type survival float64
+
+func (s survival) Survival(t float64) float64 {
+	return math.Exp(-float64(s) * t)
+}
+
+func TestRiskyBondJSON(t *testing.T) {
+	curve, err := credit.NewHazardCurve([]float64{1.0, 5.0}, []float64{1.0, 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := credit.RiskyBond{Straight: newBond(5, 1.0), Curve: curve, Recovery: 40.0}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed credit.RiskyBond
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	ts := &term.Flat{R: 1.0}
+	if pv, expected := parsed.PresentValue(ts), b.PresentValue(ts); math.Abs(pv-expected) > 1e-12 {
+		t.Errorf("got %v, expected %v from %s", pv, expected, data)
+	}
+
+	b.Curve = survival(0.02)
+	if _, err := json.Marshal(b); err == nil {
+		t.Errorf("expected error for default risk %T", b.Curve)
+	}
+
+	for _, data := range []string{
+		`{"bond": {}, "recovery": 40}`,
+		`{"bond": {}, "curve": null}`,
+		`{"bond": {}, "curve": {"times": [1, 5], "intensities": [1]}}`,
+	} {
+		if err := json.Unmarshal([]byte(data), &parsed); err == nil {
+			t.Errorf("%s: expected error", data)
+		}
+	}
+}
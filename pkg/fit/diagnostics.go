@@ -0,0 +1,149 @@
+package fit
+
+import (
+	"math"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Residual is the pricing error of a bond for the fitted term structure
+type Residual struct {
+	ID       string
+	Maturity float64
+	// Price and Fitted are the quoted and the fitted "clean" prices
+	Price  float64
+	Fitted float64
+	// PriceError is the fitted minus the quoted price
+	PriceError float64
+	// Yield and FittedYield are the yields of the quoted and fitted prices in
+	// percent
+	Yield       float64
+	FittedYield float64
+	// YieldError is the fitted minus the quoted yield in bps
+	YieldError float64
+	// Excluded is true if the bond has been excluded from the fit as an
+	// outlier
+	Excluded bool
+}
+
+// Result is the fitted term structure with the diagnostics of the fit
+type Result struct {
+	Structure  term.Structure
+	Model      int
+	Names      []string
+	Parameters []float64
+	Residuals  []Residual
+	// Outliers are the bonds excluded from the fit in the order of exclusion
+	Outliers []Outlier
+	// PriceRMSE is the root mean squared price error of the included bonds
+	PriceRMSE float64
+	// YieldRMSE is the root mean squared yield error in bps of the included
+	// bonds
+	YieldRMSE float64
+	// Scale is the scale of the errors of the robust loss function
+	Scale float64
+	// Condition is the condition number of the Jacobian of the errors with
+	// respect to the parameters (large numbers indicate poorly identified
+	// parameters)
+	Condition float64
+	// Evaluations is the number of evaluations of the objective function
+	Evaluations int
+}
+
+// result calculates the residuals and diagnostics for the fitted structure
+func (f *Fitter) result(p *prepared, excluded []bool, outliers []Outlier, fit *fitted) *Result {
+	ts := fit.structure
+	r := Result{
+		Structure:   ts,
+		Model:       f.Model,
+		Names:       fit.model.names,
+		Parameters:  fit.parameters,
+		Outliers:    outliers,
+		Scale:       fit.scale,
+		Evaluations: fit.evaluations,
+	}
+
+	var sumPrice, sumYield float64
+	var countPrice, countYield int
+	for i, b := range p.bonds {
+		value := p.value(i, ts)
+		res := Residual{
+			ID:         b.ID,
+			Maturity:   p.maturity(i),
+			Price:      b.Price,
+			Fitted:     value - b.Accrued(),
+			PriceError: value - p.dirty[i],
+			Yield:      p.yields[i],
+			Excluded:   excluded[i],
+		}
+		res.FittedYield = math.NaN()
+		res.YieldError = math.NaN()
+		if est, err := p.yield(i, value); err == nil {
+			res.FittedYield = est
+			if !math.IsNaN(res.Yield) {
+				res.YieldError = (est - res.Yield) * 100.0
+			}
+		}
+		if !res.Excluded {
+			sumPrice += res.PriceError * res.PriceError
+			countPrice += 1
+			if !math.IsNaN(res.YieldError) {
+				sumYield += res.YieldError * res.YieldError
+				countYield += 1
+			}
+		}
+		r.Residuals = append(r.Residuals, res)
+	}
+	if countPrice > 0 {
+		r.PriceRMSE = math.Sqrt(sumPrice / float64(countPrice))
+	}
+	if countYield > 0 {
+		r.YieldRMSE = math.Sqrt(sumYield / float64(countYield))
+	}
+	r.Condition = f.condition(p, excluded, fit.model, fit.parameters)
+	return &r
+}
+
+// condition returns the condition number of the Jacobian of the errors of
+// the included bonds with respect to the parameters by central differences
+func (f *Fitter) condition(p *prepared, excluded []bool, m *model, x []float64) float64 {
+	errors := func(x []float64) []float64 {
+		ts, err := m.structure(x)
+		if err != nil {
+			return nil
+		}
+		e := []float64{}
+		for i := range p.bonds {
+			if !excluded[i] {
+				v, _ := f.residual(p, i, ts)
+				e = append(e, v)
+			}
+		}
+		return e
+	}
+
+	n := 0
+	for _, ex := range excluded {
+		if !ex {
+			n += 1
+		}
+	}
+	jac := mat.NewDense(n, len(x), nil)
+	y := make([]float64, len(x))
+	for k := range x {
+		h := 1e-6 * math.Max(1.0, math.Abs(x[k]))
+		copy(y, x)
+		y[k] = x[k] + h
+		up := errors(y)
+		y[k] = x[k] - h
+		down := errors(y)
+		if up == nil || down == nil {
+			return math.Inf(1)
+		}
+		for i := range up {
+			jac.Set(i, k, (up[i]-down[i])/(2.0*h))
+		}
+	}
+	return mat.Cond(jac, 2)
+}
@@ -0,0 +1,553 @@
+package fit
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/khezen/rootfinding"
+	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/rate"
+	"github.com/konimarti/fixedincome/pkg/term"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Model families
+const (
+	NelsonSiegel int = iota
+	NelsonSiegelSvensson
+	CubicSpline
+	MonotoneConvex
+	Hermite
+	SmoothingSpline
+	FlatSpread
+	LinearSpread
+	NelsonSiegelSpread
+)
+
+// Models maps the names of the model families to the models
+var Models = map[string]int{
+	"ns":           NelsonSiegel,
+	"nss":          NelsonSiegelSvensson,
+	"cubic":        CubicSpline,
+	"monotone":     MonotoneConvex,
+	"hermite":      Hermite,
+	"smoothing":    SmoothingSpline,
+	"flatspread":   FlatSpread,
+	"linearspread": LinearSpread,
+	"nsspread":     NelsonSiegelSpread,
+}
+
+// Errors to be minimized
+const (
+	YieldErrors int = iota
+	PriceErrors
+)
+
+// Penalty is added to the objective function for each bond whose yield cannot
+// be determined
+var Penalty = 1.0e3
+
+// Bond is a straight bond with its quoted price
+type Bond struct {
+	ID string
+	// Issuer groups the bonds for the issuer spread curves
+	Issuer string
+	*bond.Straight
+	// Price is the quoted "clean" price
+	Price float64
+}
+
+// Options of the fit
+type Options struct {
+	// Errors selects the squared yield errors (default) or price errors to be
+	// minimized
+	Errors int
+	// DurationWeighted weights the squared price errors with the inverse
+	// squared durations (approximately the squared yield errors)
+	DurationWeighted bool
+	// OvernightRate is the annual overnight rate in percent (money market basis
+	// 360) which anchors the short end of the curve (0.0 deactivates the
+	// constraint)
+	OvernightRate float64
+	// Start are the initial parameters (the default parameters if nil)
+	Start []float64
+	// Lower and Upper are the bounds of the parameters (unbounded if nil)
+	Lower, Upper []float64
+	// Knots is the knot placement strategy of the spline models (see term.Knots)
+	Knots int
+	// Nodes is the number of interior knots of the spline models which are
+	// placed between the maturities of the bonds (defaults to half the number
+	// of bonds)
+	Nodes int
+	// Lambda is the roughness penalty of the smoothing spline (defaults to
+	// term.VRP)
+	Lambda func(float64) float64
+	// Loss is the loss function of the errors (Squared, Huber or Tukey; the
+	// smoothing spline always uses squared errors)
+	Loss int
+	// Scale is the scale of the errors for the robust loss functions (in
+	// percent for yield errors); if zero, it is estimated from the residuals
+	// of a least-squares fit
+	Scale float64
+	// Outliers is the threshold in robust standard deviations above which
+	// bonds are iteratively excluded from the fit (0.0 deactivates the
+	// exclusion)
+	Outliers float64
+	// MaxOutliers is the maximum number of excluded bonds (defaults to a
+	// quarter of the bonds)
+	MaxOutliers int
+	// Starts is the number of starting values per decay parameter of the
+	// Nelson-Siegel models; the fit with the lowest objective is used
+	Starts int
+	// Base is the base term structure of the issuer spread models
+	Base term.Structure
+	// Method is the optimization method (NelderMead or LevenbergMarquardt;
+	// the latter requires price errors and uses analytic gradients where
+	// available)
+	Method int
+}
+
+// Fitter fits a term structure of a model family to bond prices
+type Fitter struct {
+	Model int
+	Options
+}
+
+// New returns a new fitter for the model family with the given options
+func New(model int, options Options) *Fitter {
+	return &Fitter{
+		Model:   model,
+		Options: options,
+	}
+}
+
+// prepared holds the dirty prices, yields and weights of the bonds
+type prepared struct {
+	bonds   []Bond
+	dirty   []float64
+	yields  []float64
+	weights []float64
+	// maturities of all cash flows in increasing order
+	maturities []float64
+	// times and amounts of the cash flows of the bonds
+	times, amounts [][]float64
+}
+
+func prepare(bonds []Bond) (*prepared, error) {
+	if len(bonds) == 0 {
+		return nil, fmt.Errorf("no bonds given")
+	}
+	p := prepared{bonds: bonds}
+	for i, b := range bonds {
+		if b.Straight == nil {
+			return nil, fmt.Errorf("bond %d (%s) is not defined", i, b.ID)
+		}
+		times, amounts := cashflows(b.Straight)
+		p.times = append(p.times, times)
+		p.amounts = append(p.amounts, amounts)
+
+		dirty := b.Price + b.Accrued()
+		yield, err := p.yield(i, dirty)
+		if err != nil {
+			yield = math.NaN()
+		}
+		weight := 1.0 / math.Pow(p.maturity(i), 2.0)
+		if !math.IsNaN(yield) {
+			weight = 1.0 / math.Pow(p.duration(i, &term.Flat{R: yield}), 2.0)
+		}
+		p.dirty = append(p.dirty, dirty)
+		p.yields = append(p.yields, yield)
+		p.weights = append(p.weights, weight)
+	}
+	p.maturities = maturities(p.times)
+	if len(p.maturities) == 0 {
+		return nil, fmt.Errorf("bonds have no outstanding cash flows")
+	}
+	return &p, nil
+}
+
+// maturities returns the distinct positive times of the cash flows in
+// increasing order
+func maturities(times [][]float64) []float64 {
+	dates := make(map[float64]bool)
+	for _, list := range times {
+		for _, m := range list {
+			if m > 0.0 {
+				dates[m] = true
+			}
+		}
+	}
+	result := []float64{}
+	for m := range dates {
+		result = append(result, m)
+	}
+	sort.Float64s(result)
+	return result
+}
+
+// subset returns the prepared bonds which are not excluded
+func (p *prepared) subset(excluded []bool) *prepared {
+	q := prepared{}
+	for i := range p.bonds {
+		if excluded[i] {
+			continue
+		}
+		q.bonds = append(q.bonds, p.bonds[i])
+		q.dirty = append(q.dirty, p.dirty[i])
+		q.yields = append(q.yields, p.yields[i])
+		q.weights = append(q.weights, p.weights[i])
+		q.times = append(q.times, p.times[i])
+		q.amounts = append(q.amounts, p.amounts[i])
+	}
+	q.maturities = maturities(q.times)
+	return &q
+}
+
+// maturity returns the time of the last cash flow of bond i
+func (p *prepared) maturity(i int) float64 {
+	return p.times[i][len(p.times[i])-1]
+}
+
+// cashflows returns the times and amounts of the cash flows of the bond
+func cashflows(b *bond.Straight) ([]float64, []float64) {
+	times, amounts := []float64{}, []float64{}
+	coupon := b.EffectiveCoupon(b.Coupon)
+	for _, m := range b.M() {
+		times = append(times, m)
+		amounts = append(amounts, coupon)
+	}
+	times = append(times, b.Last())
+	amounts = append(amounts, b.Redemption)
+	return times, amounts
+}
+
+// value returns the "dirty" price of bond i for the term structure
+func (p *prepared) value(i int, ts term.Structure) float64 {
+	v := 0.0
+	for j, t := range p.times[i] {
+		v += p.amounts[i][j] * ts.Z(t)
+	}
+	return v
+}
+
+// yield returns the yield of bond i for the "dirty" price (equal to
+// fixedincome.Irr)
+func (p *prepared) yield(i int, value float64) (float64, error) {
+	f := func(irr float64) float64 {
+		return p.value(i, &term.Flat{R: irr}) - value
+	}
+	return rootfinding.Brent(f, -20.0, 20.0, fixedincome.Precision)
+}
+
+// duration returns the (negative) duration of bond i for the term structure
+func (p *prepared) duration(i int, ts term.Structure) float64 {
+	value, duration := 0.0, 0.0
+	for j, t := range p.times[i] {
+		cf := p.amounts[i][j] * ts.Z(t)
+		value += cf
+		duration += t * cf
+	}
+	if value == 0.0 {
+		return 0.0
+	}
+	return -duration / value
+}
+
+// residual returns the error of bond i for the term structure
+func (f *Fitter) residual(p *prepared, i int, ts term.Structure) (float64, bool) {
+	value := p.value(i, ts)
+	if f.Errors == PriceErrors {
+		e := value - p.dirty[i]
+		if f.DurationWeighted {
+			e *= math.Sqrt(p.weights[i])
+		}
+		return e, true
+	}
+	if math.IsNaN(p.yields[i]) {
+		return 0.0, true
+	}
+	est, err := p.yield(i, value)
+	if err != nil {
+		return 0.0, false
+	}
+	return est - p.yields[i], true
+}
+
+// objective returns the objective function for the model with the given
+// loss function
+func (f *Fitter) objective(p *prepared, m *model, loss func(float64) float64) func([]float64) float64 {
+	onCC := rate.Continuous(f.OvernightRate, 360)
+	anchor := math.Abs(onCC) > 1e-7
+	scale := float64(len(p.bonds))
+
+	return func(x []float64) float64 {
+		// parameter bounds
+		y, violation := f.clamp(x)
+		sst := Penalty * violation
+
+		ts, err := m.structure(y)
+		if err != nil {
+			return math.Inf(1)
+		}
+
+		// O/N constraint
+		if anchor {
+			sst += scale * math.Pow(onCC-ts.Rate(1.0/360.0), 2.0)
+		}
+
+		for i := range p.bonds {
+			e, ok := f.residual(p, i, ts)
+			if !ok {
+				sst += Penalty
+				continue
+			}
+			sst += loss(e)
+		}
+		return sst
+	}
+}
+
+// clamp returns a copy of the parameters within the bounds and the squared
+// violation of the bounds (plus one for each violated bound)
+func (f *Fitter) clamp(x []float64) ([]float64, float64) {
+	y := make([]float64, len(x))
+	copy(y, x)
+	violation := 0.0
+	for k := range y {
+		if k < len(f.Lower) && y[k] < f.Lower[k] {
+			violation += 1.0 + math.Pow(f.Lower[k]-y[k], 2.0)
+			y[k] = f.Lower[k]
+		}
+		if k < len(f.Upper) && y[k] > f.Upper[k] {
+			violation += 1.0 + math.Pow(y[k]-f.Upper[k], 2.0)
+			y[k] = f.Upper[k]
+		}
+	}
+	return y, violation
+}
+
+// fitted is a term structure fitted to a set of bonds
+type fitted struct {
+	model       *model
+	structure   term.Structure
+	parameters  []float64
+	scale       float64
+	evaluations int
+}
+
+// Fit fits the term structure to the prices of the bonds and iteratively
+// excludes the outliers
+func (f *Fitter) Fit(bonds []Bond) (*Result, error) {
+	all, err := prepare(bonds)
+	if err != nil {
+		return nil, err
+	}
+
+	maxOutliers := f.MaxOutliers
+	if maxOutliers <= 0 {
+		maxOutliers = len(bonds) / 4
+	}
+
+	excluded := make([]bool, len(bonds))
+	outliers := []Outlier{}
+	for iteration := 1; ; iteration += 1 {
+		fit, err := f.fitBonds(all.subset(excluded))
+		if err != nil {
+			return nil, err
+		}
+		if f.Outliers <= 0.0 || len(outliers) >= maxOutliers {
+			return f.result(all, excluded, outliers, fit), nil
+		}
+
+		// exclude the bond with the largest error beyond the threshold
+		errs := make([]float64, len(bonds))
+		activeErrs := []float64{}
+		for i := range bonds {
+			errs[i], _ = f.residual(all, i, fit.structure)
+			if !excluded[i] {
+				activeErrs = append(activeErrs, errs[i])
+			}
+		}
+		med, sigma := robustScale(activeErrs)
+		sigma = math.Max(sigma, MinScale)
+		worst, score := -1, f.Outliers
+		for i := range bonds {
+			if s := math.Abs(errs[i]-med) / sigma; !excluded[i] && s > score {
+				worst, score = i, s
+			}
+		}
+		if worst < 0 {
+			return f.result(all, excluded, outliers, fit), nil
+		}
+		excluded[worst] = true
+		outliers = append(outliers, Outlier{
+			ID:        bonds[worst].ID,
+			Maturity:  all.maturity(worst),
+			Error:     errs[worst],
+			Score:     score,
+			Iteration: iteration,
+		})
+	}
+}
+
+// fitBonds fits the term structure to the prepared bonds
+func (f *Fitter) fitBonds(p *prepared) (*fitted, error) {
+	if f.Model == SmoothingSpline {
+		return f.fitSmoothing(p)
+	}
+
+	m, err := f.model(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// least-squares fit from all starting values
+	x, evaluations, err := f.minimize(p, m, f.starts(p, m), Squared, 0.0)
+	if err != nil {
+		return nil, err
+	}
+
+	// robust fit starting from the least-squares fit
+	scale := f.Scale
+	if f.Loss != Squared {
+		if scale <= 0.0 {
+			ts, err := m.structure(x)
+			if err != nil {
+				return nil, err
+			}
+			errs := []float64{}
+			for i := range p.bonds {
+				if e, ok := f.residual(p, i, ts); ok {
+					errs = append(errs, e)
+				}
+			}
+			_, scale = robustScale(errs)
+		}
+		if scale > 0.0 {
+			var n int
+			x, n, err = f.minimize(p, m, [][]float64{x}, f.Loss, scale)
+			if err != nil {
+				return nil, err
+			}
+			evaluations += n
+		}
+	}
+
+	ts, err := m.structure(x)
+	if err != nil {
+		return nil, err
+	}
+	return &fitted{
+		model:       m,
+		structure:   ts,
+		parameters:  x,
+		scale:       scale,
+		evaluations: evaluations,
+	}, nil
+}
+
+// minimize returns the parameters with the lowest objective for the loss
+// function kind from all starting values and the number of function
+// evaluations
+func (f *Fitter) minimize(p *prepared, m *model, starts [][]float64, kind int, scale float64) ([]float64, int, error) {
+	if f.Method == LevenbergMarquardt {
+		return f.leastSquares(p, m, starts, kind, scale)
+	}
+	problem := optimize.Problem{
+		Func: f.objective(p, m, loss(kind, scale)),
+	}
+	var best *optimize.Result
+	evaluations := 0
+	var err error
+	for _, start := range starts {
+		result, e := optimize.Minimize(problem, start, nil, nil)
+		if e == nil {
+			e = result.Status.Err()
+		}
+		if e != nil {
+			err = e
+			continue
+		}
+		evaluations += result.Stats.FuncEvaluations
+		if best == nil || result.F < best.F {
+			best = result
+		}
+	}
+	if best == nil {
+		return nil, evaluations, err
+	}
+	x, _ := f.clamp(best.X)
+	return x, evaluations, nil
+}
+
+// fitSmoothing fits the smoothing B-spline to the (duration weighted) prices
+func (f *Fitter) fitSmoothing(p *prepared) (*fitted, error) {
+	knots, err := f.knots(p)
+	if err != nil {
+		return nil, err
+	}
+	instruments := []term.Instrument{}
+	for i := range p.bonds {
+		inst := term.Instrument{
+			Times:   p.times[i],
+			Amounts: p.amounts[i],
+			Price:   p.dirty[i],
+			Weight:  1.0,
+		}
+		if f.DurationWeighted || f.Errors == YieldErrors {
+			inst.Weight = p.weights[i]
+		}
+		instruments = append(instruments, inst)
+	}
+	if onCC := rate.Continuous(f.OvernightRate, 360); math.Abs(onCC) > 1e-7 {
+		// the overnight rate is an additional instrument
+		t := 1.0 / 360.0
+		instruments = append(instruments, term.Instrument{
+			Times:   []float64{t},
+			Amounts: []float64{100.0},
+			Price:   100.0 * math.Exp(-onCC*0.01*t),
+			Weight:  float64(len(p.bonds)) / (t * t),
+		})
+	}
+	b, err := term.FitSmoothing(instruments, knots, f.Lambda)
+	if err != nil {
+		return nil, err
+	}
+	m := &model{
+		names: coefficientNames(len(b.Coefficients)),
+		structure: func(x []float64) (term.Structure, error) {
+			ts := &term.BSpline{Knots: knots, Coefficients: x}
+			return ts, ts.Init()
+		},
+	}
+	return &fitted{
+		model:      m,
+		structure:  b,
+		parameters: b.Coefficients,
+	}, nil
+}
+
+// knots returns the knots for the spline models
+func (f *Fitter) knots(p *prepared) ([]float64, error) {
+	n := f.Nodes
+	if n <= 0 {
+		n = len(p.bonds) / 2
+	}
+	maturities := make([]float64, len(p.bonds))
+	for i := range p.bonds {
+		maturities[i] = p.maturity(i)
+	}
+	return term.Knots(maturities, n, f.Knots)
+}
+
+// ModelName returns the name of the model family
+func ModelName(model int) string {
+	for name, m := range Models {
+		if m == model {
+			return name
+		}
+	}
+	return fmt.Sprintf("model %d", model)
+}
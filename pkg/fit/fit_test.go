@@ -0,0 +1,125 @@
+package fit_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/fit"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/rate"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+var settlement = time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+// bonds returns bonds priced off the term structure
+func bonds(ts term.Structure) []fit.Bond {
+	list := []fit.Bond{}
+	for i, years := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 12, 15, 20, 25, 30} {
+		b := bond.Straight{
+			Schedule: maturity.Schedule{
+				Settlement: settlement,
+				Maturity:   settlement.AddDate(years, i%12, 0),
+				Frequency:  1,
+				Basis:      "30E360",
+			},
+			Coupon:     0.25 * float64(i%5),
+			Redemption: 100.0,
+		}
+		list = append(list, fit.Bond{
+			ID:       fmt.Sprintf("B%02d", i),
+			Straight: &b,
+			Price:    b.PresentValue(ts) - b.Accrued(),
+		})
+	}
+	return list
+}
+
+func TestFit(t *testing.T) {
+	nss := &term.NelsonSiegelSvensson{B0: 1.5, B1: -2.0, B2: 2.0, B3: -1.0, T1: 2.0, T2: 6.0}
+	data := bonds(nss)
+	prices := fit.Options{Errors: fit.PriceErrors, DurationWeighted: true}
+
+	testData := []struct {
+		Model   int
+		Options fit.Options
+		// tolerance of the rates in percent
+		Tolerance float64
+	}{
+		{Model: fit.NelsonSiegelSvensson, Tolerance: 0.02},
+		{Model: fit.NelsonSiegelSvensson, Options: prices, Tolerance: 0.02},
+		{Model: fit.NelsonSiegel, Options: prices, Tolerance: 0.1},
+		{Model: fit.CubicSpline, Options: prices, Tolerance: 0.1},
+		{Model: fit.MonotoneConvex, Options: prices, Tolerance: 0.1},
+		{Model: fit.Hermite, Options: prices, Tolerance: 0.1},
+		{Model: fit.SmoothingSpline, Options: fit.Options{Lambda: func(float64) float64 { return 0.01 }}, Tolerance: 0.1},
+	}
+
+	for _, test := range testData {
+		name := fit.ModelName(test.Model)
+		result, err := fit.New(test.Model, test.Options).Fit(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, m := range []float64{2, 5, 10, 20} {
+			if math.Abs(result.Structure.Rate(m)-nss.Rate(m)) > test.Tolerance {
+				t.Errorf("%s: maturity %v: got rate %v, expected %v", name, m, result.Structure.Rate(m), nss.Rate(m))
+			}
+		}
+		if len(result.Residuals) != len(data) {
+			t.Errorf("%s: got %d residuals, expected %d", name, len(result.Residuals), len(data))
+		}
+		if len(result.Parameters) != len(result.Names) {
+			t.Errorf("%s: got %d parameters for %d names", name, len(result.Parameters), len(result.Names))
+		}
+		if result.YieldRMSE > 10.0*test.Tolerance*100.0 || math.IsNaN(result.PriceRMSE) {
+			t.Errorf("%s: got yield RMSE %v bps and price RMSE %v", name, result.YieldRMSE, result.PriceRMSE)
+		}
+		if result.Condition < 1.0 {
+			t.Errorf("%s: got condition number %v", name, result.Condition)
+		}
+	}
+}
+
+func TestFit_Options(t *testing.T) {
+	nss := &term.NelsonSiegelSvensson{B0: 1.5, B1: -2.0, B2: 2.0, B3: -1.0, T1: 2.0, T2: 6.0}
+	data := bonds(nss)
+
+	// O/N anchor
+	on := 0.1
+	result, err := fit.New(fit.NelsonSiegelSvensson, fit.Options{Errors: fit.PriceErrors, OvernightRate: on}).Fit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onCC := rate.Continuous(on, 360); math.Abs(result.Structure.Rate(1.0/360.0)-onCC) > 0.05 {
+		t.Errorf("got O/N rate %v, expected %v", result.Structure.Rate(1.0/360.0), onCC)
+	}
+
+	// bounds
+	options := fit.Options{
+		Errors: fit.PriceErrors,
+		Lower:  []float64{-5, -5, -5, -5, 0.1, 0.1},
+		Upper:  []float64{5, 5, 5, 5, 1.5, 30},
+	}
+	result, err = fit.New(fit.NelsonSiegelSvensson, options).Fit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Parameters[4] > 1.5 {
+		t.Errorf("got t1 %v above upper bound 1.5", result.Parameters[4])
+	}
+
+	// errors
+	if _, err := fit.New(fit.NelsonSiegel, fit.Options{Start: []float64{1, 2}}).Fit(data); err == nil {
+		t.Errorf("expected error for wrong number of initial parameters")
+	}
+	if _, err := fit.New(99, fit.Options{}).Fit(data); err == nil {
+		t.Errorf("expected error for unknown model")
+	}
+	if _, err := fit.New(fit.NelsonSiegel, fit.Options{}).Fit(nil); err == nil {
+		t.Errorf("expected error for missing bonds")
+	}
+}
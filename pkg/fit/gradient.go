@@ -0,0 +1,300 @@
+package fit
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/konimarti/fixedincome/pkg/rate"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Optimization methods
+const (
+	NelderMead int = iota
+	LevenbergMarquardt
+)
+
+// Methods maps the names of the optimization methods to the methods
+var Methods = map[string]int{
+	"neldermead": NelderMead,
+	"lm":         LevenbergMarquardt,
+}
+
+var (
+	// MaxIterations is the maximum number of iterations of the
+	// Levenberg-Marquardt method (and of the reweighting of robust losses)
+	MaxIterations = 200
+	// Tolerance is the relative change of the objective at convergence
+	Tolerance = 1e-12
+)
+
+// nssGradient returns the discount factor of the Nelson-Siegel(-Svensson)
+// model with parameters x at maturity m and sets its gradient dz
+func nssGradient(x []float64, m float64, dz []float64) float64 {
+	if m == 0.0 {
+		m = 1e-7
+	}
+	svensson := len(x) == 6
+	t1 := x[3]
+	if svensson {
+		t1 = x[4]
+	}
+
+	// factor loadings and their derivatives with respect to the decays
+	e1 := math.Exp(-m / t1)
+	l1 := (1.0 - e1) * t1 / m
+	dl1 := (1.0-e1)/m - e1/t1
+	dc1 := dl1 - e1*m/(t1*t1)
+
+	// derivatives of the rate
+	r := x[0] + x[1]*l1 + x[2]*(l1-e1)
+	dz[0], dz[1], dz[2] = 1.0, l1, l1-e1
+	if svensson {
+		t2 := x[5]
+		e2 := math.Exp(-m / t2)
+		l2 := (1.0 - e2) * t2 / m
+		dc2 := (1.0-e2)/m - e2/t2 - e2*m/(t2*t2)
+		r += x[3] * (l2 - e2)
+		dz[3], dz[4], dz[5] = l2-e2, x[1]*dl1+x[2]*dc1, x[3]*dc2
+	} else {
+		dz[3] = x[1]*dl1 + x[2]*dc1
+	}
+
+	z := math.Exp(-r * 0.01 * m)
+	for k := range dz {
+		dz[k] *= -z * 0.01 * m
+	}
+	return z
+}
+
+// residuals returns the price errors of the bonds (weighted with the robust
+// weights and, if requested, the inverse durations) and of the O/N anchor for
+// the parameters x and, if jac is not nil, sets their Jacobian
+func (f *Fitter) residuals(p *prepared, m *model, x, robust []float64, jac *mat.Dense) ([]float64, error) {
+	onCC := rate.Continuous(f.OvernightRate, 360)
+	anchor := math.Abs(onCC) > 1e-7
+	scale := math.Sqrt(float64(len(p.bonds)))
+
+	n := len(p.bonds)
+	if anchor {
+		n += 1
+	}
+	r := make([]float64, n)
+
+	weight := func(i int) float64 {
+		w := math.Sqrt(robust[i])
+		if f.DurationWeighted {
+			w *= math.Sqrt(p.weights[i])
+		}
+		return w
+	}
+
+	// analytic gradients
+	if m.gradient != nil {
+		if _, err := m.structure(x); err != nil {
+			return nil, err
+		}
+		dz := make([]float64, len(x))
+		row := make([]float64, len(x))
+		for i := range p.bonds {
+			value := 0.0
+			for k := range row {
+				row[k] = 0.0
+			}
+			for j, t := range p.times[i] {
+				a := p.amounts[i][j]
+				value += a * m.gradient(x, t, dz)
+				for k := range row {
+					row[k] += a * dz[k]
+				}
+			}
+			w := weight(i)
+			r[i] = w * (value - p.dirty[i])
+			if jac != nil {
+				for k := range row {
+					jac.Set(i, k, w*row[k])
+				}
+			}
+		}
+		if anchor {
+			t := 1.0 / 360.0
+			z := m.gradient(x, t, dz)
+			r[n-1] = scale * (onCC + 100.0*math.Log(z)/t)
+			if jac != nil {
+				for k := range dz {
+					jac.Set(n-1, k, scale*100.0/(t*z)*dz[k])
+				}
+			}
+		}
+		return r, nil
+	}
+
+	// finite differences
+	eval := func(x []float64, r []float64) error {
+		ts, err := m.structure(x)
+		if err != nil {
+			return err
+		}
+		for i := range p.bonds {
+			r[i] = weight(i) * (p.value(i, ts) - p.dirty[i])
+		}
+		if anchor {
+			r[n-1] = scale * (onCC - ts.Rate(1.0/360.0))
+		}
+		return nil
+	}
+	if err := eval(x, r); err != nil {
+		return nil, err
+	}
+	if jac != nil {
+		y := make([]float64, len(x))
+		ry := make([]float64, n)
+		for k := range x {
+			copy(y, x)
+			h := 1e-7 * math.Max(1.0, math.Abs(x[k]))
+			y[k] += h
+			if err := eval(y, ry); err != nil {
+				return nil, err
+			}
+			for i := range r {
+				jac.Set(i, k, (ry[i]-r[i])/h)
+			}
+		}
+	}
+	return r, nil
+}
+
+// levenbergMarquardt minimizes the sum of the squared residuals starting from
+// the parameters x within the bounds and returns the parameters, the sum of
+// squares and the number of evaluations of the residuals
+func (f *Fitter) levenbergMarquardt(p *prepared, m *model, x, robust []float64) ([]float64, float64, int, error) {
+	x, _ = f.clamp(x)
+	nx := len(x)
+	nr := len(p.bonds)
+	if math.Abs(rate.Continuous(f.OvernightRate, 360)) > 1e-7 {
+		nr += 1
+	}
+
+	jac := mat.NewDense(nr, nx, nil)
+	r, err := f.residuals(p, m, x, robust, jac)
+	if err != nil {
+		return nil, 0.0, 1, err
+	}
+	cost := floats.Dot(r, r)
+	evaluations := 1
+
+	var jtj mat.SymDense
+	var g mat.VecDense
+	mu := -1.0
+	for iter := 0; iter < MaxIterations; iter += 1 {
+		jtj.SymOuterK(1.0, jac.T())
+		g.MulVec(jac.T(), mat.NewVecDense(nr, r))
+		if mu < 0.0 {
+			mu = 0.0
+			for k := 0; k < nx; k += 1 {
+				mu = math.Max(mu, jtj.At(k, k))
+			}
+			mu *= 1e-3
+		}
+
+		// increase the damping until the step reduces the sum of squares
+		accepted := false
+		for !accepted && mu < 1e16 {
+			a := mat.NewSymDense(nx, nil)
+			a.CopySym(&jtj)
+			for k := 0; k < nx; k += 1 {
+				a.SetSym(k, k, jtj.At(k, k)+mu*math.Max(jtj.At(k, k), 1e-12))
+			}
+			var step mat.VecDense
+			if err := step.SolveVec(a, &g); err != nil {
+				mu *= 10.0
+				continue
+			}
+			y := make([]float64, nx)
+			for k := range y {
+				y[k] = x[k] - step.AtVec(k)
+			}
+			y, _ = f.clamp(y)
+			ry, err := f.residuals(p, m, y, robust, nil)
+			evaluations += 1
+			if err != nil || floats.Dot(ry, ry) >= cost {
+				mu *= 10.0
+				continue
+			}
+
+			accepted = true
+			decrease := cost - floats.Dot(ry, ry)
+			x, r, cost = y, ry, floats.Dot(ry, ry)
+			mu = math.Max(mu/10.0, 1e-12)
+			if decrease <= Tolerance*cost {
+				return x, cost, evaluations, nil
+			}
+		}
+		if !accepted {
+			// no further decrease possible
+			return x, cost, evaluations, nil
+		}
+		if _, err := f.residuals(p, m, x, robust, jac); err != nil {
+			return nil, 0.0, evaluations, err
+		}
+	}
+	return x, cost, evaluations, nil
+}
+
+// leastSquares fits the parameters from the starting values by the
+// Levenberg-Marquardt method with the loss function kind (robust losses by
+// iteratively reweighted least squares) and returns the parameters with the
+// lowest objective and the number of evaluations
+func (f *Fitter) leastSquares(p *prepared, m *model, starts [][]float64, kind int, scale float64) ([]float64, int, error) {
+	if f.Errors != PriceErrors {
+		return nil, 0, fmt.Errorf("Levenberg-Marquardt method requires price errors")
+	}
+	objective := f.objective(p, m, loss(kind, scale))
+	weight := weights(kind, scale)
+
+	var best []float64
+	bestF := math.Inf(1)
+	evaluations := 0
+	var err error
+	for _, start := range starts {
+		robust := make([]float64, len(p.bonds))
+		for i := range robust {
+			robust[i] = 1.0
+		}
+		x := start
+		for iter := 0; iter < MaxIterations; iter += 1 {
+			var n int
+			x, _, n, err = f.levenbergMarquardt(p, m, x, robust)
+			evaluations += n
+			if err != nil || kind == Squared {
+				break
+			}
+			ts, e := m.structure(x)
+			if e != nil {
+				err = e
+				break
+			}
+			change := 0.0
+			for i := range p.bonds {
+				e, _ := f.residual(p, i, ts)
+				w := weight(e)
+				change = math.Max(change, math.Abs(w-robust[i]))
+				robust[i] = w
+			}
+			if change < 1e-8 {
+				break
+			}
+		}
+		if err != nil {
+			continue
+		}
+		if v := objective(x); v < bestF {
+			best, bestF = x, v
+		}
+	}
+	if best == nil {
+		return nil, evaluations, err
+	}
+	return best, evaluations, nil
+}
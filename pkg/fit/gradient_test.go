@@ -0,0 +1,134 @@
+package fit_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/fit"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/rate"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// manyBonds returns n bonds with maturities up to 30 years priced off the term
+// structure
+func manyBonds(ts term.Structure, n int) []fit.Bond {
+	list := []fit.Bond{}
+	for i := 0; i < n; i += 1 {
+		b := bond.Straight{
+			Schedule: maturity.Schedule{
+				Settlement: settlement,
+				Maturity:   settlement.AddDate(0, 3+i*357/n, 7*(i%4)),
+				Frequency:  1,
+				Basis:      "30E360",
+			},
+			Coupon:     0.125 * float64(i%17),
+			Redemption: 100.0,
+		}
+		list = append(list, fit.Bond{
+			ID:       fmt.Sprintf("B%03d", i),
+			Straight: &b,
+			Price:    b.PresentValue(ts) - b.Accrued(),
+		})
+	}
+	return list
+}
+
+func TestFit_LevenbergMarquardt(t *testing.T) {
+	nss := &term.NelsonSiegelSvensson{B0: 1.5, B1: -2.0, B2: 2.0, B3: -1.0, T1: 2.0, T2: 6.0}
+	data := manyBonds(nss, 200)
+	options := fit.Options{Errors: fit.PriceErrors, DurationWeighted: true, Method: fit.LevenbergMarquardt}
+
+	testData := []struct {
+		Model int
+		// tolerance of the rates in percent
+		Tolerance float64
+	}{
+		{Model: fit.NelsonSiegelSvensson, Tolerance: 1e-4},
+		{Model: fit.NelsonSiegel, Tolerance: 0.1},
+		{Model: fit.CubicSpline, Tolerance: 0.02},
+		{Model: fit.MonotoneConvex, Tolerance: 0.05},
+		{Model: fit.Hermite, Tolerance: 0.05},
+	}
+
+	for _, test := range testData {
+		name := fit.ModelName(test.Model)
+		result, err := fit.New(test.Model, options).Fit(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if e := maxError(result.Structure, nss); e > test.Tolerance {
+			t.Errorf("%s: got maximum rate error %v", name, e)
+		}
+		if len(result.Residuals) != len(data) {
+			t.Errorf("%s: got %d residuals, expected %d", name, len(result.Residuals), len(data))
+		}
+	}
+}
+
+func TestFit_LevenbergMarquardtOptions(t *testing.T) {
+	nss := &term.NelsonSiegelSvensson{B0: 1.5, B1: -2.0, B2: 2.0, B3: -1.0, T1: 2.0, T2: 6.0}
+	data := bonds(nss)
+	options := fit.Options{Errors: fit.PriceErrors, Method: fit.LevenbergMarquardt}
+
+	// agrees with the simplex method
+	simplex := options
+	simplex.Method = fit.NelderMead
+	expected, err := fit.New(fit.NelsonSiegelSvensson, simplex).Fit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := fit.New(fit.NelsonSiegelSvensson, options).Fit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := maxError(result.Structure, expected.Structure); e > 0.01 {
+		t.Errorf("got maximum rate difference %v to the simplex method", e)
+	}
+	if result.Evaluations >= expected.Evaluations {
+		t.Errorf("got %d evaluations, simplex method %d", result.Evaluations, expected.Evaluations)
+	}
+
+	// O/N anchor
+	on := 0.1
+	anchored := options
+	anchored.OvernightRate = on
+	result, err = fit.New(fit.NelsonSiegelSvensson, anchored).Fit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onCC := rate.Continuous(on, 360); math.Abs(result.Structure.Rate(1.0/360.0)-onCC) > 0.05 {
+		t.Errorf("got O/N rate %v, expected %v", result.Structure.Rate(1.0/360.0), onCC)
+	}
+
+	// bounds
+	bounded := options
+	bounded.Lower = []float64{-5, -5, -5, -5, 0.1, 0.1}
+	bounded.Upper = []float64{5, 5, 5, 5, 1.5, 30}
+	result, err = fit.New(fit.NelsonSiegelSvensson, bounded).Fit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Parameters[4] > 1.5 {
+		t.Errorf("got t1 %v above upper bound 1.5", result.Parameters[4])
+	}
+
+	// robust loss
+	robust := options
+	robust.DurationWeighted = true
+	robust.Loss = fit.Tukey
+	result, err = fit.New(fit.NelsonSiegelSvensson, robust).Fit(corrupt(data, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := maxError(result.Structure, nss); e > 0.01 {
+		t.Errorf("got maximum rate error %v with Tukey loss", e)
+	}
+
+	// yield errors
+	if _, err := fit.New(fit.NelsonSiegelSvensson, fit.Options{Method: fit.LevenbergMarquardt}).Fit(data); err == nil {
+		t.Errorf("expected error for yield errors")
+	}
+}
@@ -0,0 +1,200 @@
+package fit
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+	"gonum.org/v1/gonum/floats"
+)
+
+// model defines the parameters of a model family and how to build the term
+// structure from them
+type model struct {
+	names     []string
+	start     []float64
+	structure func(x []float64) (term.Structure, error)
+	// gradient returns the discount factor at maturity t and sets its
+	// gradient dz with respect to the parameters (nil if the model has no
+	// analytic gradient)
+	gradient func(x []float64, t float64, dz []float64) float64
+}
+
+// model returns the model with the initial parameters for the bonds
+func (f *Fitter) model(p *prepared) (*model, error) {
+	var m *model
+	switch f.Model {
+	case NelsonSiegel:
+		m = &model{
+			names: []string{"b0", "b1", "b2", "t1"},
+			structure: func(x []float64) (term.Structure, error) {
+				if x[3] <= 0.0 {
+					return nil, fmt.Errorf("decay parameter must be positive")
+				}
+				return &term.NelsonSiegel{B0: x[0], B1: x[1], B2: x[2], T1: x[3]}, nil
+			},
+			gradient: nssGradient,
+		}
+		if f.Start == nil {
+			maturities, yields := p.observed()
+			long, short := p.levels()
+			m.start = []float64{long, short - long, 0.0, 2.0}
+			if x, ok := regression(maturities, yields, 2.0); ok {
+				m.start = append(x, 2.0)
+			}
+		}
+
+	case NelsonSiegelSvensson:
+		m = &model{
+			names: []string{"b0", "b1", "b2", "b3", "t1", "t2"},
+			structure: func(x []float64) (term.Structure, error) {
+				if x[4] <= 0.0 || x[5] <= 0.0 {
+					return nil, fmt.Errorf("decay parameters must be positive")
+				}
+				return &term.NelsonSiegelSvensson{B0: x[0], B1: x[1], B2: x[2], B3: x[3], T1: x[4], T2: x[5]}, nil
+			},
+			gradient: nssGradient,
+		}
+		if f.Start == nil {
+			maturities, yields := p.observed()
+			long, short := p.levels()
+			m.start = []float64{long, short - long, 0.0, 0.0, 2.0, 5.0}
+			if x, ok := regression(maturities, yields, 2.0, 5.0); ok {
+				m.start = append(x, 2.0, 5.0)
+			}
+		}
+
+	case CubicSpline, MonotoneConvex, Hermite:
+		knots, err := f.knots(p)
+		if err != nil {
+			return nil, err
+		}
+		// the first node is the earliest cash flow
+		nodes := knots[1:]
+		if p.maturities[0] < nodes[0] {
+			nodes = append([]float64{p.maturities[0]}, nodes...)
+		}
+		m = &model{
+			names: nodeNames(nodes),
+			structure: func(x []float64) (term.Structure, error) {
+				switch f.Model {
+				case CubicSpline:
+					return term.NewSpline(nodes, x, 0.0), nil
+				case MonotoneConvex:
+					ts := &term.MonotoneConvex{Maturities: nodes, Rates: x}
+					return ts, ts.Init()
+				default:
+					ts := &term.Hermite{Maturities: nodes, DiscountFactors: x, Slopes: term.Monotone}
+					return ts, ts.Init()
+				}
+			},
+		}
+		if f.Model == CubicSpline {
+			m.gradient = splineGradient(nodes)
+		}
+		if f.Start == nil {
+			// start with the fitted Nelson-Siegel-Svensson curve
+			prefit, err := New(NelsonSiegelSvensson, Options{
+				Errors:           f.Errors,
+				DurationWeighted: f.DurationWeighted,
+				OvernightRate:    f.OvernightRate,
+				Method:           f.Method,
+			}).Fit(p.bonds)
+			if err != nil {
+				return nil, err
+			}
+			m.start = make([]float64, len(nodes))
+			for i, t := range nodes {
+				if f.Model == MonotoneConvex {
+					m.start[i] = prefit.Structure.Rate(t)
+				} else {
+					m.start[i] = prefit.Structure.Z(t)
+				}
+			}
+		}
+
+	case FlatSpread, LinearSpread, NelsonSiegelSpread:
+		var err error
+		if m, err = f.spreadModel(p); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown model %d", f.Model)
+	}
+
+	if f.Start != nil {
+		if len(f.Start) != len(m.names) {
+			return nil, fmt.Errorf("expected %d initial parameters, got %d", len(m.names), len(f.Start))
+		}
+		m.start = make([]float64, len(f.Start))
+		copy(m.start, f.Start)
+	}
+	return m, nil
+}
+
+// splineGradient returns the gradient of the cubic spline which is linear in
+// the discount factors at the nodes, i.e. the splines through the unit vectors
+// (cached for the maturities)
+func splineGradient(nodes []float64) func([]float64, float64, []float64) float64 {
+	units := make([]term.Structure, len(nodes))
+	for k := range units {
+		z := make([]float64, len(nodes))
+		z[k] = 1.0
+		units[k] = term.NewSpline(nodes, z, 0.0)
+	}
+	cache := make(map[float64][]float64)
+	return func(x []float64, t float64, dz []float64) float64 {
+		basis, ok := cache[t]
+		if !ok {
+			basis = make([]float64, len(units))
+			for k, u := range units {
+				basis[k] = u.Z(t)
+			}
+			cache[t] = basis
+		}
+		copy(dz, basis)
+		return floats.Dot(x, basis)
+	}
+}
+
+// levels returns the average yields of the longest and the shortest bonds
+// as starting values for the level and slope parameters
+func (p *prepared) levels() (float64, float64) {
+	type point struct{ t, y float64 }
+	points := []point{}
+	for i := range p.bonds {
+		if !math.IsNaN(p.yields[i]) {
+			points = append(points, point{p.maturity(i), p.yields[i]})
+		}
+	}
+	if len(points) == 0 {
+		return 0.0, 0.0
+	}
+	short, long := points[0], points[0]
+	for _, pt := range points {
+		if pt.t < short.t {
+			short = pt
+		}
+		if pt.t > long.t {
+			long = pt
+		}
+	}
+	return long.y, short.y
+}
+
+func nodeNames(nodes []float64) []string {
+	names := make([]string, len(nodes))
+	for i, t := range nodes {
+		names[i] = fmt.Sprintf("%.4g", t)
+	}
+	return names
+}
+
+func coefficientNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("c%d", i)
+	}
+	return names
+}
@@ -0,0 +1,114 @@
+package fit
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Range of the decay parameters of the grid of starting values
+var (
+	TauMin = 0.25
+	TauMax = 30.0
+)
+
+// loadings returns the slope and curvature factor loadings of the
+// Nelson-Siegel models for maturity m and decay parameter tau
+func loadings(m, tau float64) (float64, float64) {
+	e := math.Exp(-m / tau)
+	slope := (1.0 - e) * tau / m
+	return slope, slope - e
+}
+
+// decays returns the indices of the decay parameters of the model
+func (f *Fitter) decays() []int {
+	switch f.Model {
+	case NelsonSiegel:
+		return []int{3}
+	case NelsonSiegelSvensson:
+		return []int{4, 5}
+	}
+	return nil
+}
+
+// starts returns the starting parameters for the optimization: the initial
+// parameters of the model and, if multiple starts are requested for the
+// Nelson-Siegel models, a grid of decay parameters with the linear parameters
+// from a regression of the yields on the factor loadings
+func (f *Fitter) starts(p *prepared, m *model) [][]float64 {
+	starts := [][]float64{m.start}
+	taus := f.decays()
+	if f.Starts <= 1 || taus == nil {
+		return starts
+	}
+
+	// grid of decay parameters within the bounds
+	lo, hi := TauMin, TauMax
+	for _, k := range taus {
+		if k < len(f.Lower) {
+			lo = math.Max(lo, f.Lower[k])
+		}
+		if k < len(f.Upper) {
+			hi = math.Min(hi, f.Upper[k])
+		}
+	}
+	grid := make([]float64, f.Starts)
+	for i := range grid {
+		grid[i] = lo * math.Pow(hi/lo, float64(i)/float64(f.Starts-1))
+	}
+
+	maturities, yields := p.observed()
+	for i, t1 := range grid {
+		if len(taus) == 1 {
+			if x, ok := regression(maturities, yields, t1); ok {
+				starts = append(starts, append(x, t1))
+			}
+			continue
+		}
+		// the decay parameters of NSS are interchangeable: t1 < t2
+		for _, t2 := range grid[i+1:] {
+			if x, ok := regression(maturities, yields, t1, t2); ok {
+				starts = append(starts, append(x, t1, t2))
+			}
+		}
+	}
+	return starts
+}
+
+// observed returns the maturities and yields of the bonds with known yields
+func (p *prepared) observed() ([]float64, []float64) {
+	maturities, yields := []float64{}, []float64{}
+	for i := range p.bonds {
+		if !math.IsNaN(p.yields[i]) {
+			maturities = append(maturities, p.maturity(i))
+			yields = append(yields, p.yields[i])
+		}
+	}
+	return maturities, yields
+}
+
+// regression returns the level, slope and curvature parameters for the given
+// decay parameters by a least-squares regression of the yields on the factor
+// loadings
+func regression(maturities, yields []float64, taus ...float64) ([]float64, bool) {
+	cols := 2 + len(taus)
+	if len(yields) < cols {
+		return nil, false
+	}
+	a := mat.NewDense(len(yields), cols, nil)
+	for i, m := range maturities {
+		slope, curvature := loadings(m, taus[0])
+		a.Set(i, 0, 1.0)
+		a.Set(i, 1, slope)
+		a.Set(i, 2, curvature)
+		if len(taus) > 1 {
+			_, curvature2 := loadings(m, taus[1])
+			a.Set(i, 3, curvature2)
+		}
+	}
+	var x mat.VecDense
+	if err := x.SolveVec(a, mat.NewVecDense(len(yields), yields)); err != nil {
+		return nil, false
+	}
+	return x.RawVector().Data, true
+}
@@ -0,0 +1,130 @@
+package fit
+
+import (
+	"math"
+	"sort"
+)
+
+// Loss functions of the errors
+const (
+	Squared int = iota
+	Huber
+	Tukey
+)
+
+// Losses maps the names of the loss functions to the losses
+var Losses = map[string]int{
+	"squared": Squared,
+	"huber":   Huber,
+	"tukey":   Tukey,
+}
+
+// Tuning constants of the robust loss functions in multiples of the robust
+// standard deviation of the errors (95% efficiency for normal errors)
+var (
+	HuberK = 1.345
+	TukeyC = 4.685
+)
+
+// MinScale is the smallest robust standard deviation of the errors (in
+// percent for yield errors) for the exclusion of outliers, i.e. bonds are not
+// excluded from an almost exact fit
+var MinScale = 1e-4
+
+// loss returns the loss function for the errors with the given scale (the
+// robust standard deviation of the errors); the robust losses are equal to
+// the squared errors for small errors
+func loss(kind int, scale float64) func(float64) float64 {
+	switch kind {
+	case Huber:
+		k := HuberK * scale
+		return func(e float64) float64 {
+			if a := math.Abs(e); a > k {
+				return 2.0*k*a - k*k
+			}
+			return e * e
+		}
+	case Tukey:
+		c := TukeyC * scale
+		return func(e float64) float64 {
+			if math.Abs(e) > c {
+				return c * c / 3.0
+			}
+			u := 1.0 - (e/c)*(e/c)
+			return c * c / 3.0 * (1.0 - u*u*u)
+		}
+	default:
+		return func(e float64) float64 {
+			return e * e
+		}
+	}
+}
+
+// weights returns the weights of the squared errors of the iteratively
+// reweighted least squares for the loss function (the derivative of the loss
+// divided by twice the error)
+func weights(kind int, scale float64) func(float64) float64 {
+	switch kind {
+	case Huber:
+		k := HuberK * scale
+		return func(e float64) float64 {
+			if a := math.Abs(e); a > k {
+				return k / a
+			}
+			return 1.0
+		}
+	case Tukey:
+		c := TukeyC * scale
+		return func(e float64) float64 {
+			if math.Abs(e) > c {
+				return 0.0
+			}
+			u := 1.0 - (e/c)*(e/c)
+			return u * u
+		}
+	default:
+		return func(float64) float64 {
+			return 1.0
+		}
+	}
+}
+
+// median returns the median of the values
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return 0.5 * (sorted[n/2-1] + sorted[n/2])
+}
+
+// robustScale returns the median and the robust standard deviation of the
+// values (median absolute deviation scaled for normal errors)
+func robustScale(values []float64) (float64, float64) {
+	med := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - med)
+	}
+	return med, 1.4826 * median(deviations)
+}
+
+// Outlier is a bond which has been excluded from the fit
+type Outlier struct {
+	ID       string
+	Maturity float64
+	// Error is the error of the bond (in percent for yield errors) when it
+	// was excluded
+	Error float64
+	// Score is the distance of the error from the median error in robust
+	// standard deviations
+	Score float64
+	// Iteration in which the bond was excluded
+	Iteration int
+}
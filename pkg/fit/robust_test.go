@@ -0,0 +1,112 @@
+package fit_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/fit"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// corrupt returns the bonds with mis-quoted prices for the given indices
+func corrupt(data []fit.Bond, indices ...int) []fit.Bond {
+	list := make([]fit.Bond, len(data))
+	copy(list, data)
+	for _, i := range indices {
+		list[i].Price += 3.0
+	}
+	return list
+}
+
+// maxError returns the largest deviation of the rates from the true curve
+func maxError(ts, expected term.Structure) float64 {
+	max := 0.0
+	for m := 1.0; m <= 30.0; m += 1.0 {
+		max = math.Max(max, math.Abs(ts.Rate(m)-expected.Rate(m)))
+	}
+	return max
+}
+
+func TestFit_Outliers(t *testing.T) {
+	nss := &term.NelsonSiegelSvensson{B0: 1.5, B1: -2.0, B2: 2.0, B3: -1.0, T1: 2.0, T2: 6.0}
+	data := corrupt(bonds(nss), 3, 9)
+
+	options := fit.Options{Errors: fit.PriceErrors, DurationWeighted: true, Outliers: 3.0}
+	result, err := fit.New(fit.NelsonSiegelSvensson, options).Fit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Outliers) != 2 {
+		t.Fatalf("got %d outliers, expected 2: %+v", len(result.Outliers), result.Outliers)
+	}
+	for _, outlier := range result.Outliers {
+		if outlier.ID != data[3].ID && outlier.ID != data[9].ID {
+			t.Errorf("bond %s excluded", outlier.ID)
+		}
+		if outlier.Score < 3.0 {
+			t.Errorf("got score %v below the threshold", outlier.Score)
+		}
+	}
+	if !result.Residuals[3].Excluded || !result.Residuals[9].Excluded || result.Residuals[0].Excluded {
+		t.Errorf("residuals not flagged correctly")
+	}
+	if e := maxError(result.Structure, nss); e > 0.01 {
+		t.Errorf("got maximum rate error %v after the exclusion of outliers", e)
+	}
+	if result.PriceRMSE > 0.01 {
+		t.Errorf("got price RMSE %v of the included bonds", result.PriceRMSE)
+	}
+
+	// limit of excluded bonds
+	options.MaxOutliers = 1
+	result, err = fit.New(fit.NelsonSiegelSvensson, options).Fit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Outliers) != 1 {
+		t.Errorf("got %d outliers, expected 1", len(result.Outliers))
+	}
+}
+
+func TestFit_Loss(t *testing.T) {
+	nss := &term.NelsonSiegelSvensson{B0: 1.5, B1: -2.0, B2: 2.0, B3: -1.0, T1: 2.0, T2: 6.0}
+	data := corrupt(bonds(nss), 6)
+
+	errors := make(map[int]float64)
+	for _, loss := range []int{fit.Squared, fit.Huber, fit.Tukey} {
+		options := fit.Options{Errors: fit.PriceErrors, DurationWeighted: true, Loss: loss}
+		result, err := fit.New(fit.NelsonSiegelSvensson, options).Fit(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		errors[loss] = maxError(result.Structure, nss)
+		if loss != fit.Squared && result.Scale <= 0.0 {
+			t.Errorf("loss %d: got scale %v", loss, result.Scale)
+		}
+	}
+	if errors[fit.Huber] >= errors[fit.Squared] || errors[fit.Tukey] >= errors[fit.Huber] {
+		t.Errorf("robust losses do not reduce the influence of the outlier: %v", errors)
+	}
+}
+
+func TestFit_Starts(t *testing.T) {
+	nss := &term.NelsonSiegelSvensson{B0: 1.5, B1: -2.0, B2: 2.0, B3: -1.0, T1: 2.0, T2: 6.0}
+	data := bonds(nss)
+
+	// poor starting values
+	options := fit.Options{
+		Errors:           fit.PriceErrors,
+		DurationWeighted: true,
+		Start:            []float64{0.0, 0.0, 0.0, 0.0, 25.0, 26.0},
+		Starts:           5,
+		Lower:            []float64{-10, -10, -10, -10, 0.1, 0.1},
+		Upper:            []float64{10, 10, 10, 10, 30, 30},
+	}
+	result, err := fit.New(fit.NelsonSiegelSvensson, options).Fit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := maxError(result.Structure, nss); e > 0.01 {
+		t.Errorf("got maximum rate error %v with multiple starts", e)
+	}
+}
@@ -0,0 +1,111 @@
+package fit
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// spreadModel returns the issuer spread model on top of the base curve
+func (f *Fitter) spreadModel(p *prepared) (*model, error) {
+	if f.Base == nil {
+		return nil, fmt.Errorf("base term structure required for %s", ModelName(f.Model))
+	}
+
+	// median spread of the yields over the base curve in bps
+	maturities, yields := p.observed()
+	spreads := make([]float64, len(yields))
+	for i, y := range yields {
+		spreads[i] = (y - f.Base.Rate(maturities[i])) * 100.0
+	}
+	level := 0.0
+	if len(spreads) > 0 {
+		level = median(spreads)
+	}
+
+	m := &model{}
+	switch f.Model {
+	case FlatSpread:
+		m.names = []string{"s"}
+		m.start = []float64{level}
+	case LinearSpread:
+		m.names = []string{"s0", "s1"}
+		m.start = []float64{level, 0.0}
+	default:
+		m.names = []string{"b0", "b1", "b2", "t1"}
+		m.start = []float64{level, 0.0, 0.0, 2.0}
+	}
+	shape := f.Model - FlatSpread
+
+	m.structure = func(x []float64) (term.Structure, error) {
+		if shape == term.NelsonSiegelShape && x[3] <= 0.0 {
+			return nil, fmt.Errorf("decay parameter must be positive")
+		}
+		parameters := make([]float64, len(x))
+		copy(parameters, x)
+		return &term.IssuerSpread{Base: f.Base, Shape: shape, Parameters: parameters}, nil
+	}
+
+	// discount factors of the base curve are cached for the maturities
+	cache := make(map[float64]float64)
+	m.gradient = func(x []float64, t float64, dz []float64) float64 {
+		base, ok := cache[t]
+		if !ok {
+			base = f.Base.Z(t)
+			cache[t] = base
+		}
+		ts := term.IssuerSpread{Shape: shape, Parameters: x}
+		z := base * math.Exp(-ts.Credit(t)*0.0001*t)
+
+		// derivatives of the spread
+		dz[0] = 1.0
+		switch shape {
+		case term.LinearShape:
+			dz[1] = t
+		case term.NelsonSiegelShape:
+			u := t
+			if u == 0.0 {
+				u = 1e-7
+			}
+			e := math.Exp(-u / x[3])
+			l := (1.0 - e) * x[3] / u
+			dl := (1.0-e)/u - e/x[3]
+			dz[1], dz[2] = l, l-e
+			dz[3] = x[1]*dl + x[2]*(dl-e*u/(x[3]*x[3]))
+		}
+		for k := range dz {
+			dz[k] *= -z * 0.0001 * t
+		}
+		return z
+	}
+	return m, nil
+}
+
+// FitIssuers fits the issuer spread model on top of the base curve of the
+// options to the bonds of each issuer
+func FitIssuers(model int, options Options, bonds []Bond) (map[string]*Result, error) {
+	if model != FlatSpread && model != LinearSpread && model != NelsonSiegelSpread {
+		return nil, fmt.Errorf("%s is not an issuer spread model", ModelName(model))
+	}
+	issuers := make(map[string][]Bond)
+	for _, b := range bonds {
+		issuers[b.Issuer] = append(issuers[b.Issuer], b)
+	}
+	names := []string{}
+	for name := range issuers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make(map[string]*Result)
+	for _, name := range names {
+		result, err := New(model, options).Fit(issuers[name])
+		if err != nil {
+			return nil, fmt.Errorf("issuer %s: %v", name, err)
+		}
+		results[name] = result
+	}
+	return results, nil
+}
@@ -0,0 +1,80 @@
+package fit_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/fit"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestFitIssuers(t *testing.T) {
+	govt := &term.NelsonSiegelSvensson{B0: 1.5, B1: -2.0, B2: 2.0, B3: -1.0, T1: 2.0, T2: 6.0}
+	issuers := map[string]*term.IssuerSpread{
+		"A": {Base: govt, Shape: term.FlatShape, Parameters: []float64{40.0}},
+		"B": {Base: govt, Shape: term.LinearShape, Parameters: []float64{20.0, 4.0}},
+		"C": {Base: govt, Shape: term.NelsonSiegelShape, Parameters: []float64{120.0, -90.0, 30.0, 3.0}},
+	}
+	data := []fit.Bond{}
+	for name, ts := range issuers {
+		for _, b := range bonds(ts) {
+			b.Issuer = name
+			data = append(data, b)
+		}
+	}
+
+	for _, method := range []int{fit.NelderMead, fit.LevenbergMarquardt} {
+		options := fit.Options{Base: govt, Errors: fit.PriceErrors, DurationWeighted: true, Method: method}
+		results, err := fit.FitIssuers(fit.NelsonSiegelSpread, options, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != len(issuers) {
+			t.Fatalf("got %d issuers, expected %d", len(results), len(issuers))
+		}
+		for name, expected := range issuers {
+			s, ok := results[name].Structure.(*term.IssuerSpread)
+			if !ok {
+				t.Fatalf("issuer %s: got %T", name, results[name].Structure)
+			}
+			for _, m := range []float64{1, 3, 5, 10, 20} {
+				if math.Abs(s.Credit(m)-expected.Credit(m)) > 1.0 {
+					t.Errorf("method %d: issuer %s: maturity %v: got spread %v, expected %v", method, name, m, s.Credit(m), expected.Credit(m))
+				}
+			}
+			if math.Abs(s.Rate(5.0)-govt.Rate(5.0)-s.Credit(5.0)*0.01) > 1e-9 {
+				t.Errorf("issuer %s: curve not composed with the base curve", name)
+			}
+		}
+	}
+
+	// shapes
+	options := fit.Options{Base: govt, Errors: fit.PriceErrors, Method: fit.LevenbergMarquardt}
+	for _, model := range []int{fit.FlatSpread, fit.LinearSpread} {
+		results, err := fit.FitIssuers(model, options, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := results["A"].Parameters; math.Abs(p[0]-40.0) > 0.01 {
+			t.Errorf("%s: got parameters %v for flat spread of 40 bps", fit.ModelName(model), p)
+		}
+		if len(results["B"].Parameters) != len(results["B"].Names) {
+			t.Errorf("%s: got %d parameters for %d names", fit.ModelName(model), len(results["B"].Parameters), len(results["B"].Names))
+		}
+	}
+	results, err := fit.FitIssuers(fit.LinearSpread, options, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := results["B"].Parameters; math.Abs(p[0]-20.0) > 0.01 || math.Abs(p[1]-4.0) > 0.01 {
+		t.Errorf("got parameters %v for linear spread", p)
+	}
+
+	// errors
+	if _, err := fit.FitIssuers(fit.FlatSpread, fit.Options{}, data); err == nil {
+		t.Errorf("expected error for missing base curve")
+	}
+	if _, err := fit.FitIssuers(fit.NelsonSiegel, options, data); err == nil {
+		t.Errorf("expected error for model without base curve")
+	}
+}
@@ -0,0 +1,157 @@
+package fixing
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DateFmt is the date format used in the fixings files
+const DateFmt = "2006-01-02"
+
+// MaxGap is the maximum number of calendar days a fixing is carried forward
+// over weekends and holidays (see Fixing)
+var MaxGap = 4
+
+// Store holds the historical fixings (in percent) of interest rate indices
+// (e.g. SARON, ESTR, SOFR) by index name and date
+type Store struct {
+	mu     sync.RWMutex
+	series map[string]map[time.Time]float64
+}
+
+// NewStore creates an empty fixings store
+func NewStore() *Store {
+	return &Store{
+		series: make(map[string]map[time.Time]float64),
+	}
+}
+
+// Add adds (or replaces) the fixing of an index for the given date
+func (s *Store) Add(index string, date time.Time, rate float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.series == nil {
+		s.series = make(map[string]map[time.Time]float64)
+	}
+	if _, ok := s.series[index]; !ok {
+		s.series[index] = make(map[time.Time]float64)
+	}
+	s.series[index][truncate(date)] = rate
+}
+
+// Get returns the fixing of an index for the given date
+func (s *Store) Get(index string, date time.Time) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rate, ok := s.series[index][truncate(date)]
+	return rate, ok
+}
+
+// Latest returns the most recent fixing of an index on or before the given date
+func (s *Store) Latest(index string, date time.Time) (time.Time, float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	date = truncate(date)
+	var (
+		found bool
+		last  time.Time
+	)
+	for d := range s.series[index] {
+		if d.After(date) {
+			continue
+		}
+		if !found || d.After(last) {
+			last, found = d, true
+		}
+	}
+	if !found {
+		return time.Time{}, 0.0, false
+	}
+	return last, s.series[index][last], true
+}
+
+// Fixing returns the fixing of an index for the given date; over weekends and
+// holidays the most recent fixing is used if it is at most MaxGap days old
+func (s *Store) Fixing(index string, date time.Time) (float64, error) {
+	last, rate, ok := s.Latest(index, date)
+	if !ok {
+		return 0.0, fmt.Errorf("missing fixing for %s on %s", index, date.Format(DateFmt))
+	}
+	if truncate(date).Sub(last) > time.Duration(MaxGap)*24*time.Hour {
+		return 0.0, fmt.Errorf("missing fixing for %s on %s (last fixing on %s)", index, date.Format(DateFmt), last.Format(DateFmt))
+	}
+	return rate, nil
+}
+
+// Dates returns the sorted fixing dates of an index
+func (s *Store) Dates(index string) []time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	dates := make([]time.Time, 0, len(s.series[index]))
+	for d := range s.series[index] {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
+
+// ReadCSV reads the fixings for an index from comma or semicolon separated
+// values with the date (format: 2006-01-02) in the first and the rate in
+// percent in the second column; a header line is skipped
+func (s *Store) ReadCSV(index string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if strings.Count(string(data), ";") > strings.Count(string(data), ",") {
+		reader.Comma = ';'
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return err
+	}
+	for i, line := range records {
+		if len(line) < 2 {
+			continue
+		}
+		date, err := time.Parse(DateFmt, strings.TrimSpace(line[0]))
+		if err != nil {
+			if i == 0 {
+				// header
+				continue
+			}
+			return fmt.Errorf("line %d: %v", i+1, err)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(line[1]), 64)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", i+1, err)
+		}
+		s.Add(index, date, rate)
+	}
+	return nil
+}
+
+// LoadCSV reads the fixings for an index from a CSV file (see ReadCSV)
+func (s *Store) LoadCSV(index, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.ReadCSV(index, f)
+}
+
+func truncate(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,52 @@
+package fixing_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/fixing"
+)
+
+func TestStore_ReadCSV(t *testing.T) {
+	data := `Date;SARON
+2021-11-29;-0.7102
+2021-11-30;-0.7066
+2021-12-01;-0.7088
+`
+	store := fixing.NewStore()
+	if err := store.ReadCSV("SARON", strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(store.Dates("SARON")); n != 3 {
+		t.Errorf("wrong number of fixings; got: %d, expected: %d", n, 3)
+	}
+
+	rate, ok := store.Get("SARON", time.Date(2021, 11, 30, 0, 0, 0, 0, time.UTC))
+	if !ok || math.Abs(rate-(-0.7066)) > 1e-9 {
+		t.Errorf("wrong fixing; got: %v, expected: %v", rate, -0.7066)
+	}
+
+	// weekend falls back to last available fixing
+	date, rate, ok := store.Latest("SARON", time.Date(2021, 12, 4, 0, 0, 0, 0, time.UTC))
+	if !ok || !date.Equal(time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)) || math.Abs(rate-(-0.7088)) > 1e-9 {
+		t.Errorf("wrong latest fixing; got: %v %v, expected: 2021-12-01 %v", date, rate, -0.7088)
+	}
+
+	if _, _, ok := store.Latest("SARON", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)); ok {
+		t.Errorf("found fixing before first available date")
+	}
+
+	// weekend and holiday gaps are bridged, stale fixings are rejected
+	if rate, err := store.Fixing("SARON", time.Date(2021, 12, 5, 0, 0, 0, 0, time.UTC)); err != nil || math.Abs(rate-(-0.7088)) > 1e-9 {
+		t.Errorf("wrong fixing; got: %v %v, expected: %v", rate, err, -0.7088)
+	}
+	if _, err := store.Fixing("SARON", time.Date(2021, 12, 6, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("expected error for stale fixing")
+	}
+	if _, err := store.Fixing("SARON", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("expected error for missing fixing")
+	}
+}
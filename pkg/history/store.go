@@ -0,0 +1,203 @@
+package history
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/konimarti/fixedincome/pkg/importer"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const DateFmt = "2006-01-02"
+
+// Store keeps term structures by curve name (e.g. CHF) and date. If the
+// store is backed by a directory, every curve is saved as a json file
+// <dir>/<name>/<date>.json which can be read with term.Parse.
+type Store struct {
+	// Dir is the directory of the json files (empty for an in-memory store)
+	Dir    string
+	mu     sync.RWMutex
+	curves map[string]map[time.Time]term.Structure
+}
+
+// NewStore returns an empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		curves: make(map[string]map[time.Time]term.Structure),
+	}
+}
+
+// Open returns the store backed by the directory and loads all curves
+func Open(dir string) (*Store, error) {
+	s := NewStore()
+	s.Dir = dir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range files {
+		date, err := time.Parse(DateFmt, strings.TrimSuffix(filepath.Base(name), ".json"))
+		if err != nil {
+			continue
+		}
+		curve := filepath.Base(filepath.Dir(name))
+		if err := checkName(curve); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		ts, err := term.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		s.set(curve, date, ts)
+	}
+	return s, nil
+}
+
+func (s *Store) set(name string, date time.Time, ts term.Structure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.curves[name]; !ok {
+		s.curves[name] = make(map[time.Time]term.Structure)
+	}
+	s.curves[name][truncate(date)] = ts
+}
+
+// checkName returns an error unless the curve name, which is a directory of
+// the store, consists of letters, digits, '-', '_' and '.' and is neither "."
+// nor ".."
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("invalid curve name %q", name)
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_.", r) {
+			return fmt.Errorf("invalid curve name %q", name)
+		}
+	}
+	return nil
+}
+
+// Add stores the term structure of the curve for the date (and saves it to
+// the directory of the store)
+func (s *Store) Add(name string, date time.Time, ts term.Structure) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	if s.Dir != "" {
+		data, err := term.MarshalIndent(ts, "", "  ")
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(s.Dir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, truncate(date).Format(DateFmt)+".json"), data, 0644); err != nil {
+			return err
+		}
+	}
+	s.set(name, date, ts)
+	return nil
+}
+
+// Import stores the imported curves under the name
+func (s *Store) Import(name string, curves []importer.Curve) error {
+	for _, c := range curves {
+		if err := s.Add(name, c.Date, c.Structure); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the term structure of the curve for the date
+func (s *Store) Get(name string, date time.Time) (term.Structure, bool) {
+	if checkName(name) != nil {
+		return nil, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ts, ok := s.curves[name][truncate(date)]
+	return ts, ok
+}
+
+// Latest returns the latest term structure of the curve on or before the date
+func (s *Store) Latest(name string, date time.Time) (time.Time, term.Structure, bool) {
+	dates := s.Dates(name)
+	i := sort.Search(len(dates), func(i int) bool { return dates[i].After(truncate(date)) })
+	if i == 0 {
+		return time.Time{}, nil, false
+	}
+	ts, ok := s.Get(name, dates[i-1])
+	return dates[i-1], ts, ok
+}
+
+// Names returns the sorted names of the curves
+func (s *Store) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.curves))
+	for name := range s.curves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dates returns the sorted dates of the curve
+func (s *Store) Dates(name string) []time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	dates := make([]time.Time, 0, len(s.curves[name]))
+	for date := range s.curves[name] {
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
+
+// Range returns the dates and term structures of the curve between from and
+// to (both inclusive) ordered by date
+func (s *Store) Range(name string, from, to time.Time) ([]time.Time, []term.Structure) {
+	dates := []time.Time{}
+	curves := []term.Structure{}
+	for _, date := range s.Dates(name) {
+		if date.Before(truncate(from)) || date.After(truncate(to)) {
+			continue
+		}
+		ts, _ := s.Get(name, date)
+		dates = append(dates, date)
+		curves = append(curves, ts)
+	}
+	return dates, curves
+}
+
+// Series returns the time series of the continuously compounded spot rate in
+// percent for the tenor (in years) of the curve between from and to
+func (s *Store) Series(name string, tenor float64, from, to time.Time) ([]time.Time, []float64) {
+	dates, curves := s.Range(name, from, to)
+	rates := make([]float64, len(curves))
+	for i, ts := range curves {
+		rates[i] = ts.Rate(tenor)
+	}
+	return dates, rates
+}
+
+func truncate(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
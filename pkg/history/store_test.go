@@ -0,0 +1,131 @@
+package history_test
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/history"
+	"github.com/konimarti/fixedincome/pkg/importer"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func date(y, m, d int) time.Time {
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestStore(t *testing.T) {
+	s := history.NewStore()
+	for i, r := range []float64{1.0, 1.1, 1.2} {
+		if err := s.Add("CHF", date(2021, 4, 1+2*i), &term.Flat{R: r}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testData := []struct {
+		Date     time.Time
+		Found    bool
+		Expected time.Time
+	}{
+		{Date: date(2021, 3, 31), Found: false},
+		{Date: date(2021, 4, 1), Found: true, Expected: date(2021, 4, 1)},
+		{Date: date(2021, 4, 4), Found: true, Expected: date(2021, 4, 3)},
+		{Date: time.Date(2021, 4, 5, 17, 0, 0, 0, time.UTC), Found: true, Expected: date(2021, 4, 5)},
+		{Date: date(2022, 1, 1), Found: true, Expected: date(2021, 4, 5)},
+	}
+	for nr, test := range testData {
+		got, ts, ok := s.Latest("CHF", test.Date)
+		if ok != test.Found {
+			t.Errorf("test nr %d: got found %v, expected %v", nr, ok, test.Found)
+			continue
+		}
+		if ok && (!got.Equal(test.Expected) || ts == nil) {
+			t.Errorf("test nr %d: got %v, expected %v", nr, got, test.Expected)
+		}
+	}
+
+	if _, _, ok := s.Latest("EUR", date(2021, 4, 5)); ok {
+		t.Errorf("expected no curve for EUR")
+	}
+
+	dates, rates := s.Series("CHF", 5.0, date(2021, 4, 2), date(2021, 4, 5))
+	if len(dates) != 2 || rates[0] != 1.1 || rates[1] != 1.2 {
+		t.Errorf("got series %v %v", dates, rates)
+	}
+
+	for _, name := range []string{"../CHF", "..", ".", "", `a\b`, "CHF 2"} {
+		if err := s.Add(name, date(2021, 4, 1), &term.Flat{}); err == nil {
+			t.Errorf("expected error for invalid name %q", name)
+		}
+		if _, ok := s.Get(name, date(2021, 4, 1)); ok {
+			t.Errorf("got curve for invalid name %q", name)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := history.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nss := &term.NelsonSiegelSvensson{B0: 1.0, B1: -1.0, B2: 0.5, B3: 0.1, T1: 2.0, T2: 5.0}
+	if err := s.Add("CHF", date(2021, 4, 1), nss); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("EUR", date(2021, 4, 1), &term.Flat{R: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := history.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := reopened.Names(); len(names) != 2 || names[0] != "CHF" || names[1] != "EUR" {
+		t.Errorf("got names %v", names)
+	}
+	ts, ok := reopened.Get("CHF", date(2021, 4, 1))
+	if !ok {
+		t.Fatal("curve not found")
+	}
+	if math.Abs(ts.Rate(7.0)-nss.Rate(7.0)) > 1e-12 {
+		t.Errorf("got rate %v, expected %v", ts.Rate(7.0), nss.Rate(7.0))
+	}
+
+	// files in directories with invalid curve names
+	if err := os.Mkdir(filepath.Join(dir, "CHF 2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "CHF 2", "2021-04-01.json"), []byte(`{"type": "flat", "r": 1.0}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := history.Open(dir); err == nil {
+		t.Errorf("expected error for invalid curve name")
+	}
+}
+
+func TestImport(t *testing.T) {
+	data := `"Date";"D0";"Value"
+"2021-03-31";"B0";"-0.596356"
+"2021-03-31";"B1";"-0.153952"
+"2021-03-31";"B2";"5.79009"
+"2021-03-31";"B3";"-4.69599"
+"2021-03-31";"T1";"6.5912"
+"2021-03-31";"T2";"4.63027"
+`
+	curves, err := importer.SNB(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := history.NewStore()
+	if err := s.Import("CHF", curves); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("CHF", date(2021, 3, 31)); !ok {
+		t.Errorf("imported curve not found")
+	}
+}
@@ -0,0 +1,69 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bundesbank reads the Svensson parameters of the Deutsche Bundesbank for
+// the term structure of listed Federal securities from the CSV download of
+// the time series database (series BBSIS.D.I.ZST.<B0|B1|B2|B3|T1|T2>...).
+// The first line contains the series codes; the following meta data lines
+// are skipped and missing values (".") are ignored.
+func Bundesbank(r io.Reader) ([]Curve, error) {
+	records, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no header line")
+	}
+
+	// parameter per column from the series codes
+	keys := make(map[int]string)
+	for i, code := range records[0] {
+		code = strings.TrimSpace(code)
+		if strings.HasSuffix(code, "_FLAGS") {
+			continue
+		}
+		for _, part := range strings.Split(code, ".") {
+			switch part {
+			case "B0", "B1", "B2", "B3", "T1", "T2":
+				keys[i] = strings.ToLower(part)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no Svensson parameter series found in header")
+	}
+
+	params := make(parameters)
+	for i, line := range records[1:] {
+		if len(line) == 0 {
+			continue
+		}
+		date, err := time.Parse(DateFmt, strings.TrimSpace(line[0]))
+		if err != nil {
+			// meta data
+			continue
+		}
+		for col, key := range keys {
+			if col >= len(line) {
+				continue
+			}
+			field := strings.TrimSpace(line[col])
+			if field == "" || field == "." {
+				continue
+			}
+			value, err := strconv.ParseFloat(strings.Replace(field, ",", ".", 1), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+2, err)
+			}
+			params.set(date, key, value)
+		}
+	}
+	return params.curves()
+}
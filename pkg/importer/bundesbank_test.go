@@ -0,0 +1,35 @@
+package importer_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/importer"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestBundesbank(t *testing.T) {
+	f := open(t, "bundesbank_svensson.csv")
+	defer f.Close()
+
+	curves, err := importer.Bundesbank(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// no values on 2021-03-29
+	if len(curves) != 2 {
+		t.Fatalf("got %d curves, expected 2", len(curves))
+	}
+	if !curves[1].Date.Equal(time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got date %v", curves[1].Date)
+	}
+	expected := term.NelsonSiegelSvensson{B0: 0.75, B1: -1.50, B2: -4.18, B3: 2.52, T1: 1.45, T2: 9.90}
+	if nss, ok := curves[1].Structure.(*term.NelsonSiegelSvensson); !ok || *nss != expected {
+		t.Errorf("got %+v, expected %+v", curves[1].Structure, expected)
+	}
+
+	if _, err := importer.Bundesbank(strings.NewReader(",BBK01.WT1010\n2021-03-31,0.5\n")); err == nil {
+		t.Errorf("expected error for missing parameter series")
+	}
+}
@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ecbParameters maps the ECB data types to the Nelson-Siegel-Svensson parameters
+var ecbParameters = map[string]string{
+	"BETA0": "b0",
+	"BETA1": "b1",
+	"BETA2": "b2",
+	"BETA3": "b3",
+	"TAU1":  "t1",
+	"TAU2":  "t2",
+}
+
+// ECB reads the Svensson parameters of the European Central Bank euro area
+// yield curves from the SDMX CSV format of the ECB data portal (e.g. series
+// YC.B.U2.EUR.4F.G_N_A.SV_C_YM.BETA0). The header line must contain the
+// columns TIME_PERIOD and OBS_VALUE; the parameter is taken from the column
+// DATA_TYPE_FM or the last part of the series KEY.
+func ECB(r io.Reader) ([]Curve, error) {
+	records, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no header line")
+	}
+	header := records[0]
+	dateCol, valueCol := column(header, "TIME_PERIOD"), column(header, "OBS_VALUE")
+	typeCol, keyCol := column(header, "DATA_TYPE_FM"), column(header, "KEY")
+	if dateCol < 0 || valueCol < 0 || (typeCol < 0 && keyCol < 0) {
+		return nil, fmt.Errorf("header does not contain the columns TIME_PERIOD, OBS_VALUE and DATA_TYPE_FM or KEY")
+	}
+
+	params := make(parameters)
+	for i, line := range records[1:] {
+		if len(line) <= dateCol || len(line) <= valueCol {
+			continue
+		}
+		var dataType string
+		if typeCol >= 0 && typeCol < len(line) {
+			dataType = line[typeCol]
+		} else if keyCol >= 0 && keyCol < len(line) {
+			parts := strings.Split(line[keyCol], ".")
+			dataType = parts[len(parts)-1]
+		}
+		key, ok := ecbParameters[strings.ToUpper(strings.TrimSpace(dataType))]
+		if !ok {
+			continue
+		}
+		date, err := time.Parse(DateFmt, strings.TrimSpace(line[dateCol]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		if strings.TrimSpace(line[valueCol]) == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(line[valueCol]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		params.set(date, key, value)
+	}
+	return params.curves()
+}
@@ -0,0 +1,70 @@
+package importer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/importer"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestECB(t *testing.T) {
+	data := `KEY,FREQ,REF_AREA,CURRENCY,PROVIDER_FM,INSTRUMENT_FM,PROVIDER_FM_ID,DATA_TYPE_FM,TIME_PERIOD,OBS_VALUE
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.BETA0,B,U2,EUR,4F,G_N_A,SV_C_YM,BETA0,2021-03-31,0.813
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.BETA1,B,U2,EUR,4F,G_N_A,SV_C_YM,BETA1,2021-03-31,-1.356
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.BETA2,B,U2,EUR,4F,G_N_A,SV_C_YM,BETA2,2021-03-31,8.012
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.BETA3,B,U2,EUR,4F,G_N_A,SV_C_YM,BETA3,2021-03-31,-10.3
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.TAU1,B,U2,EUR,4F,G_N_A,SV_C_YM,TAU1,2021-03-31,1.95
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.TAU2,B,U2,EUR,4F,G_N_A,SV_C_YM,TAU2,2021-03-31,2.91
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.SR_10Y,B,U2,EUR,4F,G_N_A,SV_C_YM,SR_10Y,2021-03-31,-0.28
+`
+	curves, err := importer.ECB(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curves) != 1 {
+		t.Fatalf("got %d curves, expected 1", len(curves))
+	}
+	expected := term.NelsonSiegelSvensson{B0: 0.813, B1: -1.356, B2: 8.012, B3: -10.3, T1: 1.95, T2: 2.91}
+	if nss, ok := curves[0].Structure.(*term.NelsonSiegelSvensson); !ok || *nss != expected {
+		t.Errorf("got %+v, expected %+v", curves[0].Structure, expected)
+	}
+
+	// parameter from the series key
+	keyOnly := `KEY,TIME_PERIOD,OBS_VALUE
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.BETA0,2021-03-31,0.813
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.BETA1,2021-03-31,-1.356
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.BETA2,2021-03-31,8.012
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.BETA3,2021-03-31,-10.3
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.TAU1,2021-03-31,1.95
+YC.B.U2.EUR.4F.G_N_A.SV_C_YM.TAU2,2021-03-31,2.91
+`
+	curves, err = importer.ECB(strings.NewReader(keyOnly))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nss, ok := curves[0].Structure.(*term.NelsonSiegelSvensson); !ok || *nss != expected {
+		t.Errorf("got %+v, expected %+v", curves[0].Structure, expected)
+	}
+
+	if _, err := importer.ECB(strings.NewReader("DATE,VALUE\n")); err == nil {
+		t.Errorf("expected error for missing columns")
+	}
+}
+
+func TestECB_File(t *testing.T) {
+	f := open(t, "ecb_yc.csv")
+	defer f.Close()
+
+	curves, err := importer.ECB(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curves) != 2 {
+		t.Fatalf("got %d curves, expected 2", len(curves))
+	}
+	expected := term.NelsonSiegelSvensson{B0: 0.813, B1: -1.356, B2: 8.012, B3: -10.3, T1: 1.95, T2: 2.91}
+	if nss, ok := curves[1].Structure.(*term.NelsonSiegelSvensson); !ok || *nss != expected {
+		t.Errorf("got %+v, expected %+v", curves[1].Structure, expected)
+	}
+}
@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const DateFmt = "2006-01-02"
+
+// Curve is a term structure published for a date
+type Curve struct {
+	Date      time.Time
+	Structure term.Structure
+}
+
+// parameters collects the Nelson-Siegel-Svensson parameters per date
+type parameters map[time.Time]map[string]float64
+
+func (p parameters) set(date time.Time, key string, value float64) {
+	if _, ok := p[date]; !ok {
+		p[date] = make(map[string]float64)
+	}
+	p[date][key] = value
+}
+
+// curves returns the Nelson-Siegel-Svensson term structures ordered by date
+// for all dates with a complete set of parameters
+func (p parameters) curves() ([]Curve, error) {
+	curves := []Curve{}
+	for date, params := range p {
+		nss := &term.NelsonSiegelSvensson{}
+		complete := true
+		for key, field := range map[string]*float64{
+			"b0": &nss.B0, "b1": &nss.B1, "b2": &nss.B2, "b3": &nss.B3, "t1": &nss.T1, "t2": &nss.T2,
+		} {
+			value, ok := params[key]
+			if !ok {
+				complete = false
+				break
+			}
+			*field = value
+		}
+		if complete {
+			curves = append(curves, Curve{Date: date, Structure: nss})
+		}
+	}
+	if len(curves) == 0 {
+		return nil, fmt.Errorf("no complete set of parameters found")
+	}
+	sort.Slice(curves, func(i, j int) bool { return curves[i].Date.Before(curves[j].Date) })
+	return curves, nil
+}
+
+// readCSV reads all records of comma or semicolon separated values
+func readCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	if strings.Count(string(data), ";") > strings.Count(string(data), ",") {
+		reader.Comma = ';'
+	}
+	return reader.ReadAll()
+}
+
+// column returns the index of the column with the given name in the header
+func column(header []string, name string) int {
+	for i, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Formats maps the names of the supported file formats to their parsers
+var Formats = map[string]func(io.Reader) ([]Curve, error){
+	"snb":        SNB,
+	"ecb":        ECB,
+	"treasury":   Treasury,
+	"bundesbank": Bundesbank,
+}
+
+// Load reads the curves from a local file in the given format (snb, ecb,
+// treasury or bundesbank)
+func Load(format, name string) ([]Curve, error) {
+	parse, ok := Formats[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %s", format)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parse(f)
+}
@@ -0,0 +1,42 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SNB reads the Nelson-Siegel-Svensson parameters of the Swiss National
+// Bank for the CHF spot rates of Confederation bonds (data cube rendopar,
+// https://data.snb.ch/api/cube/rendopar/data/csv/en). The semicolon separated
+// data lines contain the date, the parameter (B0, B1, B2, B3, T1, T2) and the
+// value; the preamble is skipped.
+func SNB(r io.Reader) ([]Curve, error) {
+	records, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	params := make(parameters)
+	for i, line := range records {
+		if len(line) < 3 {
+			continue
+		}
+		date, err := time.Parse(DateFmt, strings.TrimSpace(line[0]))
+		if err != nil {
+			// preamble and header
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[1]))
+		if strings.TrimSpace(line[2]) == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(line[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		params.set(date, key, value)
+	}
+	return params.curves()
+}
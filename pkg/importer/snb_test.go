@@ -0,0 +1,65 @@
+package importer_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/importer"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestSNB(t *testing.T) {
+	data := `"CubeId";"rendopar"
+"PublishingDate";"2021-04-01 14:30"
+
+"Date";"D0";"Value"
+"2021-03-30";"B0";"-0.6"
+"2021-03-30";"B1";"-0.15"
+"2021-03-30";"B2";"5.8"
+"2021-03-30";"B3";"-4.7"
+"2021-03-30";"T1";"6.6"
+"2021-03-31";"B0";"-0.596356"
+"2021-03-31";"B1";"-0.153952"
+"2021-03-31";"B2";"5.79009"
+"2021-03-31";"B3";"-4.69599"
+"2021-03-31";"T1";"6.5912"
+"2021-03-31";"T2";"4.63027"
+`
+	curves, err := importer.SNB(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// incomplete parameters on 2021-03-30
+	if len(curves) != 1 {
+		t.Fatalf("got %d curves, expected 1", len(curves))
+	}
+	if !curves[0].Date.Equal(time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got date %v", curves[0].Date)
+	}
+	expected := term.NelsonSiegelSvensson{B0: -0.596356, B1: -0.153952, B2: 5.79009, B3: -4.69599, T1: 6.5912, T2: 4.63027}
+	if nss, ok := curves[0].Structure.(*term.NelsonSiegelSvensson); !ok || *nss != expected {
+		t.Errorf("got %+v, expected %+v", curves[0].Structure, expected)
+	}
+
+	if _, err := importer.SNB(strings.NewReader(`"Date";"D0";"Value"`)); err == nil {
+		t.Errorf("expected error for missing parameters")
+	}
+}
+
+func TestSNB_File(t *testing.T) {
+	f := open(t, "snb_rendopar.csv")
+	defer f.Close()
+
+	curves, err := importer.SNB(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curves) != 2 {
+		t.Fatalf("got %d curves, expected 2", len(curves))
+	}
+	expected := term.NelsonSiegelSvensson{B0: -0.596356, B1: -0.153952, B2: 5.79009, B3: -4.69599, T1: 6.5912, T2: 4.63027}
+	if nss, ok := curves[1].Structure.(*term.NelsonSiegelSvensson); !ok || *nss != expected {
+		t.Errorf("got %+v, expected %+v", curves[1].Structure, expected)
+	}
+}
@@ -0,0 +1,159 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// TreasuryDateFmt is the date format of the US Treasury par yield curve
+const TreasuryDateFmt = "01/02/2006"
+
+// tenor returns the maturity in years for a column label like "3 Mo" or "10 Yr"
+func tenor(label string) (float64, error) {
+	fields := strings.Fields(strings.TrimSpace(label))
+	if len(fields) != 2 {
+		return 0.0, fmt.Errorf("invalid tenor %s", label)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0.0, err
+	}
+	switch strings.ToLower(fields[1]) {
+	case "mo", "month", "months":
+		return value / 12.0, nil
+	case "wk", "week", "weeks":
+		return value / 52.0, nil
+	case "yr", "year", "years":
+		return value, nil
+	}
+	return 0.0, fmt.Errorf("invalid tenor %s", label)
+}
+
+// Treasury reads the daily US Treasury par yield curve rates (CSV download of
+// treasury.gov with the date in the first column and the bond-equivalent par
+// yields in percent per tenor, e.g. "1 Mo" or "10 Yr") and bootstraps a
+// spline term structure of discount factors for each date
+func Treasury(r io.Reader) ([]Curve, error) {
+	records, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no header line")
+	}
+	tenors := make([]float64, len(records[0]))
+	for i, label := range records[0][1:] {
+		if tenors[i+1], err = tenor(label); err != nil {
+			return nil, err
+		}
+	}
+
+	curves := []Curve{}
+	for i, line := range records[1:] {
+		if len(line) == 0 {
+			continue
+		}
+		date, err := time.Parse(TreasuryDateFmt, strings.TrimSpace(line[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		maturities, yields := []float64{}, []float64{}
+		for j := 1; j < len(line) && j < len(tenors); j += 1 {
+			field := strings.TrimSpace(line[j])
+			if field == "" || field == "N/A" {
+				continue
+			}
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+2, err)
+			}
+			maturities = append(maturities, tenors[j])
+			yields = append(yields, value)
+		}
+		ts, err := BootstrapParYields(maturities, yields, 2)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		curves = append(curves, Curve{Date: date, Structure: ts})
+	}
+	if len(curves) == 0 {
+		return nil, fmt.Errorf("no par yields found")
+	}
+	sort.Slice(curves, func(i, j int) bool { return curves[i].Date.Before(curves[j].Date) })
+	return curves, nil
+}
+
+// BootstrapParYields returns the spline term structure of discount factors
+// for par yields in percent with the given compounding frequency per year.
+// Maturities shorter than one coupon period are treated as zero-coupon
+// yields. For longer maturities, the par yields are linearly interpolated on
+// the coupon dates and the discount factors are bootstrapped from par bonds.
+func BootstrapParYields(maturities, yields []float64, frequency int) (*term.Spline, error) {
+	if len(maturities) != len(yields) || len(maturities) < 2 {
+		return nil, fmt.Errorf("at least two maturities and par yields required")
+	}
+	if frequency <= 0 {
+		frequency = 1
+	}
+	n := float64(frequency)
+	period := 1.0 / n
+
+	s := &term.Spline{
+		Maturities:      []float64{0.0},
+		DiscountFactors: []float64{1.0},
+	}
+
+	// zero-coupon yields
+	for i, t := range maturities {
+		if t < period-1e-9 {
+			s.Maturities = append(s.Maturities, t)
+			s.DiscountFactors = append(s.DiscountFactors, math.Pow(1.0+yields[i]/100.0/n, -n*t))
+		}
+	}
+
+	// par bonds on the coupon dates
+	last := maturities[len(maturities)-1]
+	sum := 0.0
+	for k := 1; float64(k)*period <= last+1e-9; k += 1 {
+		t := float64(k) * period
+		c := interpolate(maturities, yields, t) / 100.0 / n
+		z := (1.0 - c*sum) / (1.0 + c)
+		if z <= 0.0 {
+			return nil, fmt.Errorf("negative discount factor at %v years", t)
+		}
+		sum += z
+		s.Maturities = append(s.Maturities, t)
+		s.DiscountFactors = append(s.DiscountFactors, z)
+	}
+
+	if err := s.Init(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// interpolate returns the linearly interpolated value at x for the nodes
+// (xs, ys) sorted by xs with flat extrapolation
+func interpolate(xs, ys []float64, x float64) float64 {
+	n := len(xs)
+	if x <= xs[0] {
+		return ys[0]
+	}
+	if x >= xs[n-1] {
+		return ys[n-1]
+	}
+	for i := 1; i < n; i += 1 {
+		if x <= xs[i] {
+			w := (x - xs[i-1]) / (xs[i] - xs[i-1])
+			return ys[i-1] + w*(ys[i]-ys[i-1])
+		}
+	}
+	return ys[n-1]
+}
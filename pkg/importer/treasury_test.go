@@ -0,0 +1,66 @@
+package importer_test
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/importer"
+)
+
+func open(t *testing.T, name string) *os.File {
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestTreasury(t *testing.T) {
+	f := open(t, "treasury_par_yield.csv")
+	defer f.Close()
+
+	curves, err := importer.Treasury(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curves) != 2 {
+		t.Fatalf("got %d curves, expected 2", len(curves))
+	}
+	if !curves[1].Date.Equal(time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("curves not ordered by date: %v", curves[1].Date)
+	}
+
+	// par bonds are priced at par
+	ts := curves[1].Structure
+	testData := []struct {
+		Maturity float64
+		Yield    float64
+	}{
+		{Maturity: 2, Yield: 0.16},
+		{Maturity: 10, Yield: 1.74},
+		{Maturity: 30, Yield: 2.41},
+	}
+	for _, test := range testData {
+		price := 0.0
+		for k := 1; float64(k)*0.5 <= test.Maturity+1e-9; k += 1 {
+			price += test.Yield / 2.0 * ts.Z(float64(k)*0.5)
+		}
+		price += 100.0 * ts.Z(test.Maturity)
+		if math.Abs(price-100.0) > 1e-6 {
+			t.Errorf("maturity %v: got price %v, expected 100", test.Maturity, price)
+		}
+	}
+
+	// bills are zero-coupon yields
+	expected := math.Pow(1.0+0.0003/2.0, -2.0*0.25)
+	if z := ts.Z(0.25); math.Abs(z-expected) > 1e-9 {
+		t.Errorf("got discount factor %v, expected %v", z, expected)
+	}
+
+	if _, err := importer.Treasury(strings.NewReader("Date,\"3 Days\"\n")); err == nil {
+		t.Errorf("expected error for invalid tenor")
+	}
+}
@@ -0,0 +1,54 @@
+package bond
+
+import (
+	"encoding/json"
+
+	"github.com/konimarti/fixedincome/pkg/maturity"
+)
+
+// The bonds embed the schedule, so they need their own marshalling methods
+// to keep the coupon and redemption next to the schedule.
+
+// straightJSON is the json representation of a straight bond
+type straightJSON struct {
+	Schedule   maturity.Schedule `json:"schedule"`
+	Coupon     float64           `json:"coupon"`
+	Redemption float64           `json:"redemption"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (b Straight) MarshalJSON() ([]byte, error) {
+	return json.Marshal(straightJSON{b.Schedule, b.Coupon, b.Redemption})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (b *Straight) UnmarshalJSON(data []byte) error {
+	var s straightJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*b = Straight{Schedule: s.Schedule, Coupon: s.Coupon, Redemption: s.Redemption}
+	return nil
+}
+
+// floatingJSON is the json representation of a floating-rate bond
+type floatingJSON struct {
+	Schedule   maturity.Schedule `json:"schedule"`
+	Rate       float64           `json:"rate"`
+	Redemption float64           `json:"redemption"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (f Floating) MarshalJSON() ([]byte, error) {
+	return json.Marshal(floatingJSON{f.Schedule, f.Rate, f.Redemption})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (f *Floating) UnmarshalJSON(data []byte) error {
+	var s floatingJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*f = Floating{Schedule: s.Schedule, Rate: s.Rate, Redemption: s.Redemption}
+	return nil
+}
@@ -0,0 +1,47 @@
+package bond_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+)
+
+func TestJSON(t *testing.T) {
+	schedule := maturity.Schedule{
+		Settlement: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+		Maturity:   time.Date(2026, 5, 28, 0, 0, 0, 0, time.UTC),
+		Frequency:  1,
+	}
+
+	straight := bond.Straight{Schedule: schedule, Coupon: 1.25, Redemption: 100.0}
+	data, err := json.Marshal(straight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"schedule":{"settlement":"2021-04-01","maturity":"2026-05-28","frequency":1,"basis":"","firstcoupon":""},"coupon":1.25,"redemption":100}`
+	if string(data) != expected {
+		t.Errorf("got %s, expected %s", data, expected)
+	}
+	var parsedStraight bond.Straight
+	if err := json.Unmarshal(data, &parsedStraight); err != nil {
+		t.Fatal(err)
+	}
+	if parsedStraight != straight {
+		t.Errorf("got %+v, expected %+v", parsedStraight, straight)
+	}
+
+	floating := bond.Floating{Schedule: schedule, Rate: 0.5, Redemption: 100.0}
+	if data, err = json.Marshal(&floating); err != nil {
+		t.Fatal(err)
+	}
+	var parsedFloating bond.Floating
+	if err := json.Unmarshal(data, &parsedFloating); err != nil {
+		t.Fatal(err)
+	}
+	if parsedFloating != floating {
+		t.Errorf("got %+v, expected %+v", parsedFloating, floating)
+	}
+}
@@ -0,0 +1,398 @@
+package cds
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/khezen/rootfinding"
+	"github.com/konimarti/fixedincome/pkg/credit"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const (
+	// Buyer buys protection and pays the running coupon
+	Buyer int = iota
+	// Seller sells protection and receives the running coupon
+	Seller
+)
+
+var (
+	// Precision of the root finding for calibrating hazard curves
+	Precision = 10
+	// StepsPerYear is the minimal number of integration points per year
+	// between the nodes of the term structures
+	StepsPerYear = 12
+)
+
+// Contract implements a single-name credit default swap following the ISDA
+// standard model: quarterly premium payments on the IMM dates (act/360,
+// following business day), accrued premium paid at default, piecewise
+// constant hazard rates and flat forward rates between the nodes.
+type Contract struct {
+	// Type is either Buyer or Seller of protection
+	Type int
+	// Trade is the trade date (valuation date); protection starts at T+1
+	Trade time.Time
+	// Maturity is the scheduled termination date
+	Maturity time.Time
+	// Coupon is the running spread in bps (e.g. 100 or 500)
+	Coupon float64
+	// Notional is the notional amount
+	Notional float64
+	// Recovery is the recovery rate in percent
+	Recovery float64
+	// Curve is the default risk of the reference entity
+	Curve credit.Curve
+}
+
+// contractJSON is the json representation of a CDS; Type is marshalled as
+// "kind" as "type" tags the security
+type contractJSON struct {
+	Type     int                 `json:"kind"`
+	Trade    maturity.Date       `json:"trade"`
+	Maturity maturity.Date       `json:"maturity"`
+	Coupon   float64             `json:"coupon"`
+	Notional float64             `json:"notional"`
+	Recovery float64             `json:"recovery"`
+	Curve    *credit.HazardCurve `json:"curve"`
+}
+
+// MarshalJSON implements the json.Marshaler interface with the dates as
+// strings; the default risk has to be a hazard curve
+func (c Contract) MarshalJSON() ([]byte, error) {
+	curve, err := credit.ToHazardCurve(c.Curve)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(contractJSON{
+		Type:     c.Type,
+		Trade:    maturity.Date(c.Trade),
+		Maturity: maturity.Date(c.Maturity),
+		Coupon:   c.Coupon,
+		Notional: c.Notional,
+		Recovery: c.Recovery,
+		Curve:    curve,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (c *Contract) UnmarshalJSON(data []byte) error {
+	var s contractJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	curve, err := credit.FromHazardCurve(s.Curve)
+	if err != nil {
+		return err
+	}
+	*c = Contract{
+		Type:     s.Type,
+		Trade:    time.Time(s.Trade),
+		Maturity: time.Time(s.Maturity),
+		Coupon:   s.Coupon,
+		Notional: s.Notional,
+		Recovery: s.Recovery,
+		Curve:    curve,
+	}
+	return nil
+}
+
+// New returns a standard CDS contract traded at the given date with a tenor in months
+func New(typ int, trade time.Time, months int, coupon, notional, recovery float64) *Contract {
+	return &Contract{
+		Type:     typ,
+		Trade:    trade,
+		Maturity: StandardMaturity(trade, months),
+		Coupon:   coupon,
+		Notional: notional,
+		Recovery: recovery,
+	}
+}
+
+// period is a premium accrual period
+type period struct {
+	start, end, payment time.Time
+}
+
+// years returns the year fraction (act/365F) from the trade date
+func (c *Contract) years(date time.Time) float64 {
+	return math.Round(date.Sub(c.Trade).Hours()/24.0) / 365.0
+}
+
+// StepIn returns the date from which protection starts
+func (c *Contract) StepIn() time.Time {
+	return c.Trade.AddDate(0, 0, 1)
+}
+
+// AccrualStart returns the start of the current premium accrual period
+func (c *Contract) AccrualStart() time.Time {
+	return adjust(PreviousIMMDate(c.StepIn()))
+}
+
+// periods returns the premium accrual periods; the last period includes the maturity date
+func (c *Contract) periods() []period {
+	dates := []time.Time{}
+	for d := c.Maturity; d.After(c.StepIn()); d = d.AddDate(0, -3, 0) {
+		dates = append([]time.Time{d}, dates...)
+	}
+	periods := make([]period, len(dates))
+	start := c.AccrualStart()
+	for i, d := range dates {
+		end := adjust(d)
+		payment := end
+		if i == len(dates)-1 {
+			end = c.Maturity.AddDate(0, 0, 1)
+			payment = adjust(c.Maturity)
+		}
+		periods[i] = period{start: start, end: end, payment: payment}
+		start = end
+	}
+	return periods
+}
+
+// grid returns the integration points between t1 and t2 including the nodes
+// of the hazard curve
+func (c *Contract) grid(t1, t2 float64) []float64 {
+	points := []float64{t1, t2}
+	if h, ok := c.Curve.(*credit.HazardCurve); ok {
+		for _, t := range h.Times {
+			if t > t1 && t < t2 {
+				points = append(points, t)
+			}
+		}
+	}
+	n := int(math.Ceil((t2 - t1) * float64(StepsPerYear)))
+	for i := 1; i < n; i += 1 {
+		points = append(points, t1+float64(i)*(t2-t1)/float64(n))
+	}
+	sort.Float64s(points)
+	return points
+}
+
+// rates returns the constant hazard rate and forward rate between two
+// integration points
+func (c *Contract) rates(t1, t2 float64, ts term.Structure) (lambda, f, z, s float64) {
+	z, s = ts.Z(t1), c.Curve.Survival(t1)
+	dt := t2 - t1
+	lambda = math.Log(s/c.Curve.Survival(t2)) / dt
+	f = math.Log(z/ts.Z(t2)) / dt
+	return
+}
+
+// ProtectionLeg returns the value of the protection leg per unit notional,
+// i.e. the value of receiving (1-R) at default between step-in and maturity
+func (c *Contract) ProtectionLeg(ts term.Structure) float64 {
+	t1, t2 := c.years(c.StepIn()), c.years(c.Maturity)
+	if t2 <= t1 {
+		return 0.0
+	}
+	points := c.grid(t1, t2)
+	value := 0.0
+	for i := 1; i < len(points); i += 1 {
+		dt := points[i] - points[i-1]
+		if dt <= 0.0 {
+			continue
+		}
+		lambda, f, z, s := c.rates(points[i-1], points[i], ts)
+		if lambda+f == 0.0 {
+			value += z * s * lambda * dt
+			continue
+		}
+		value += z * s * lambda / (lambda + f) * (1.0 - math.Exp(-(lambda+f)*dt))
+	}
+	return (1.0 - c.Recovery/100.0) * value
+}
+
+// RiskyAnnuity returns the value of paying a premium of 1 per year (act/360)
+// up to maturity or default including the accrued premium at default (RPV01)
+func (c *Contract) RiskyAnnuity(ts term.Structure) float64 {
+	stepIn := c.years(c.StepIn())
+	annuity := 0.0
+	for _, p := range c.periods() {
+		accrual := math.Round(p.end.Sub(p.start).Hours()/24.0) / 360.0
+		tEnd := c.years(p.end.AddDate(0, 0, -1))
+		annuity += accrual * ts.Z(c.years(p.payment)) * c.Curve.Survival(tEnd)
+
+		// accrued premium at default
+		tStart := c.years(p.start)
+		t1 := math.Max(tStart, stepIn)
+		if tEnd <= t1 {
+			continue
+		}
+		points := c.grid(t1, tEnd)
+		for i := 1; i < len(points); i += 1 {
+			dt := points[i] - points[i-1]
+			if dt <= 0.0 {
+				continue
+			}
+			lambda, f, z, s := c.rates(points[i-1], points[i], ts)
+			a := (points[i-1] - tStart) * 365.0 / 360.0
+			k := lambda + f
+			if k == 0.0 {
+				annuity += z * s * lambda * (a*dt + 0.5*dt*dt*365.0/360.0)
+				continue
+			}
+			e := math.Exp(-k * dt)
+			annuity += z * s * lambda * (a*(1.0-e)/k + 365.0/360.0*(1.0-e*(1.0+k*dt))/(k*k))
+		}
+	}
+	return annuity
+}
+
+// Accrued returns the premium accrued since the start of the current period
+// up to the step-in date per unit notional for a running spread of 1
+func (c *Contract) Accrued() float64 {
+	return math.Round(c.StepIn().Sub(c.AccrualStart()).Hours()/24.0) / 360.0
+}
+
+// sign returns 1.0 for the protection buyer and -1.0 for the seller
+func (c *Contract) sign() float64 {
+	if c.Type == Seller {
+		return -1.0
+	}
+	return 1.0
+}
+
+// PresentValue returns the (dirty) value of the contract, i.e. protection
+// leg minus premium leg for the protection buyer
+func (c *Contract) PresentValue(ts term.Structure) float64 {
+	value := c.ProtectionLeg(ts) - c.Coupon/10000.0*c.RiskyAnnuity(ts)
+	return c.sign() * c.Notional * value
+}
+
+// CleanValue returns the value of the contract without the accrued premium
+// which is paid back to the protection buyer at the trade
+func (c *Contract) CleanValue(ts term.Structure) float64 {
+	return c.PresentValue(ts) + c.sign()*c.Notional*c.Coupon/10000.0*c.Accrued()
+}
+
+// ParSpread returns the running spread in bps that sets the clean value of
+// the contract to zero
+func (c *Contract) ParSpread(ts term.Structure) (float64, error) {
+	annuity := c.RiskyAnnuity(ts) - c.Accrued()
+	if annuity <= 0.0 {
+		return 0.0, fmt.Errorf("risky annuity is zero")
+	}
+	return c.ProtectionLeg(ts) / annuity * 10000.0, nil
+}
+
+// Upfront returns the (clean) points upfront in percent of the notional paid
+// by the protection buyer for the running coupon of the contract
+func (c *Contract) Upfront(ts term.Structure) float64 {
+	return (c.ProtectionLeg(ts) - c.Coupon/10000.0*(c.RiskyAnnuity(ts)-c.Accrued())) * 100.0
+}
+
+// flat returns a copy of the contract with a flat hazard curve
+func (c *Contract) flat(lambda float64) *Contract {
+	flat := *c
+	flat.Curve = &credit.HazardCurve{
+		Times:       []float64{c.years(c.Maturity)},
+		Intensities: []float64{lambda},
+	}
+	return &flat
+}
+
+// FlatHazard returns the flat hazard rate in percent for which the par
+// spread of the contract equals the given spread in bps
+func (c *Contract) FlatHazard(spread float64, ts term.Structure) (float64, error) {
+	var parErr error
+	f := func(lambda float64) float64 {
+		s, err := c.flat(lambda).ParSpread(ts)
+		if err != nil {
+			parErr = err
+			return math.NaN()
+		}
+		return s - spread
+	}
+	lambda, err := rootfinding.Brent(f, 0.0, 200.0, Precision)
+	if parErr != nil {
+		return 0.0, parErr
+	}
+	return lambda, err
+}
+
+// UpfrontFromSpread converts a quoted spread in bps into points upfront for
+// the running coupon of the contract using a flat hazard curve
+func (c *Contract) UpfrontFromSpread(spread float64, ts term.Structure) (float64, error) {
+	lambda, err := c.FlatHazard(spread, ts)
+	if err != nil {
+		return 0.0, err
+	}
+	return c.flat(lambda).Upfront(ts), nil
+}
+
+// SpreadFromUpfront converts points upfront into a quoted spread in bps
+// using a flat hazard curve
+func (c *Contract) SpreadFromUpfront(upfront float64, ts term.Structure) (float64, error) {
+	f := func(lambda float64) float64 {
+		return c.flat(lambda).Upfront(ts) - upfront
+	}
+	lambda, err := rootfinding.Brent(f, 0.0, 200.0, Precision)
+	if err != nil {
+		return 0.0, err
+	}
+	return c.flat(lambda).ParSpread(ts)
+}
+
+// CS01 returns the change of the value of the contract for an increase of
+// the (flat) par spread by one basis point
+func (c *Contract) CS01(ts term.Structure) (float64, error) {
+	spread, err := c.ParSpread(ts)
+	if err != nil {
+		return 0.0, err
+	}
+	l0, err := c.FlatHazard(spread, ts)
+	if err != nil {
+		return 0.0, err
+	}
+	l1, err := c.FlatHazard(spread+1.0, ts)
+	if err != nil {
+		return 0.0, err
+	}
+	return c.flat(l1).CleanValue(ts) - c.flat(l0).CleanValue(ts), nil
+}
+
+// Calibrate bootstraps the hazard curve of a reference entity from the par
+// spreads (in bps) of standard contracts traded at the given date with the
+// tenors in months
+func Calibrate(trade time.Time, tenors []int, spreads []float64, recovery float64, ts term.Structure) (*credit.HazardCurve, error) {
+	if len(tenors) != len(spreads) {
+		return nil, fmt.Errorf("number of tenors and spreads do not match")
+	}
+	h := &credit.HazardCurve{}
+	for i, months := range tenors {
+		c := New(Buyer, trade, months, 0.0, 1.0, recovery)
+		c.Curve = h
+		t := c.years(c.Maturity)
+		if n := len(h.Times); n > 0 && t <= h.Times[n-1] {
+			return nil, fmt.Errorf("tenors are not increasing")
+		}
+		h.Times = append(h.Times, t)
+		h.Intensities = append(h.Intensities, 0.0)
+		k := len(h.Intensities) - 1
+
+		var parErr error
+		f := func(lambda float64) float64 {
+			h.Intensities[k] = lambda
+			s, err := c.ParSpread(ts)
+			if err != nil {
+				parErr = err
+				return math.NaN()
+			}
+			return s - spreads[i]
+		}
+		root, err := rootfinding.Brent(f, 0.0, 200.0, Precision)
+		if parErr != nil {
+			return nil, fmt.Errorf("calibration of tenor %d months failed: %v", months, parErr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("calibration of tenor %d months failed: %v", months, err)
+		}
+		h.Intensities[k] = root
+	}
+	return h, nil
+}
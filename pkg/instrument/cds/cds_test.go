@@ -0,0 +1,116 @@
+package cds_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/credit"
+	"github.com/konimarti/fixedincome/pkg/instrument/cds"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+var trade = time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+
+func TestContract(t *testing.T) {
+	ts := term.Flat{R: 1.0}
+
+	contract := cds.New(cds.Buyer, trade, 60, 100.0, 1e7, 40.0)
+	contract.Curve, _ = credit.NewHazardCurve([]float64{10.0}, []float64{2.0})
+
+	// credit triangle: spread is approximately (1-R) * lambda (act/360 premium)
+	spread, err := contract.ParSpread(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(spread-120.0*360.0/365.0) > 1.0 {
+		t.Errorf("wrong par spread; got: %v, expected: %v", spread, 120.0*360.0/365.0)
+	}
+
+	// contract at par spread has zero value
+	contract.Coupon = spread
+	if value := contract.CleanValue(&ts); math.Abs(value) > 1e-6 {
+		t.Errorf("wrong value at par spread; got: %v, expected: %v", value, 0.0)
+	}
+
+	// protection seller holds the opposite position
+	contract.Coupon = 100.0
+	buyer := contract.PresentValue(&ts)
+	contract.Type = cds.Seller
+	if seller := contract.PresentValue(&ts); math.Abs(buyer+seller) > 1e-6 || buyer <= 0.0 {
+		t.Errorf("wrong values of buyer and seller; got: %v, %v", buyer, seller)
+	}
+
+	// accrued premium from 2021-09-20 to step-in date 2021-12-04
+	if got, expected := contract.Accrued(), 75.0/360.0; math.Abs(got-expected) > 1e-12 {
+		t.Errorf("wrong accrued; got: %v, expected: %v", got, expected)
+	}
+}
+
+func TestContract_Upfront(t *testing.T) {
+	ts := term.Flat{R: 1.0}
+	contract := cds.New(cds.Buyer, trade, 60, 100.0, 1e7, 40.0)
+
+	upfront, err := contract.UpfrontFromSpread(250.0, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// roughly (250 - 100) bps times a risky duration of 4.5 years
+	if upfront < 6.0 || upfront > 7.5 {
+		t.Errorf("wrong points upfront; got: %v", upfront)
+	}
+
+	spread, err := contract.SpreadFromUpfront(upfront, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(spread-250.0) > 1e-6 {
+		t.Errorf("wrong spread from upfront; got: %v, expected: %v", spread, 250.0)
+	}
+
+	// failures of the par spread are not hidden by the root finder
+	contract.Maturity = contract.StepIn()
+	if _, err := contract.FlatHazard(250.0, &ts); err == nil || !strings.Contains(err.Error(), "risky annuity") {
+		t.Errorf("expected risky annuity error; got: %v", err)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	ts := term.NelsonSiegelSvensson{
+		B0: -0.43381,
+		B1: -0.308942,
+		B2: 4.83643,
+		B3: -4.10991,
+		T1: 4.65211,
+		T2: 3.33637,
+	}
+
+	tenors := []int{12, 36, 60, 84, 120}
+	spreads := []float64{40.0, 60.0, 85.0, 100.0, 110.0}
+
+	curve, err := cds.Calibrate(trade, tenors, spreads, 40.0, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, months := range tenors {
+		contract := cds.New(cds.Buyer, trade, months, spreads[i], 1.0, 40.0)
+		contract.Curve = curve
+		if value := contract.CleanValue(&ts); math.Abs(value) > 1e-8 {
+			t.Errorf("tenor %d: calibrated contract not at par; got: %v", months, value)
+		}
+	}
+
+	// CS01 of protection buyer is positive and close to RPV01 * 1bp
+	contract := cds.New(cds.Buyer, trade, 60, 100.0, 1e7, 40.0)
+	contract.Curve = curve
+	cs01, err := contract.CS01(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := (contract.RiskyAnnuity(&ts) - contract.Accrued()) * 1e7 / 10000.0
+	if math.Abs(cs01-expected)/expected > 0.02 {
+		t.Errorf("wrong CS01; got: %v, expected: %v", cs01, expected)
+	}
+}
@@ -0,0 +1,40 @@
+package cds
+
+import (
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/maturity"
+)
+
+// IMMDate returns the first quarterly IMM date (20 March, June, September,
+// December) strictly after the given date
+func IMMDate(date time.Time) time.Time {
+	y, m, d := date.Date()
+	// next IMM month on or after current month
+	month := ((int(m)-1)/3)*3 + 3
+	imm := time.Date(y, time.Month(month), 20, 0, 0, 0, 0, time.UTC)
+	if int(m) == month && d >= 20 {
+		imm = imm.AddDate(0, 3, 0)
+	}
+	return imm
+}
+
+// PreviousIMMDate returns the last quarterly IMM date on or before the given date
+func PreviousIMMDate(date time.Time) time.Time {
+	return IMMDate(date).AddDate(0, -3, 0)
+}
+
+// StandardMaturity returns the scheduled termination date of a standard CDS
+// traded at the given date with a tenor in months, i.e. the IMM date after
+// the trade date rolled forward by the tenor
+func StandardMaturity(trade time.Time, months int) time.Time {
+	return IMMDate(trade).AddDate(0, months, 0)
+}
+
+// adjust moves the date to the following business day
+func adjust(date time.Time) time.Time {
+	for !maturity.IsBusinessDay(date) {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
@@ -0,0 +1,46 @@
+package cds_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/cds"
+)
+
+func TestIMMDates(t *testing.T) {
+	testData := []struct {
+		Date     time.Time
+		Next     time.Time
+		Previous time.Time
+	}{
+		{
+			Date:     time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC),
+			Next:     time.Date(2021, 12, 20, 0, 0, 0, 0, time.UTC),
+			Previous: time.Date(2021, 9, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Date:     time.Date(2021, 12, 20, 0, 0, 0, 0, time.UTC),
+			Next:     time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC),
+			Previous: time.Date(2021, 12, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Date:     time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC),
+			Next:     time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC),
+			Previous: time.Date(2021, 12, 20, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for i, test := range testData {
+		if got := cds.IMMDate(test.Date); !got.Equal(test.Next) {
+			t.Errorf("test nr %d: wrong next IMM date; got: %v, expected: %v", i, got, test.Next)
+		}
+		if got := cds.PreviousIMMDate(test.Date); !got.Equal(test.Previous) {
+			t.Errorf("test nr %d: wrong previous IMM date; got: %v, expected: %v", i, got, test.Previous)
+		}
+	}
+
+	maturity := cds.StandardMaturity(time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC), 60)
+	if expected := time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC); !maturity.Equal(expected) {
+		t.Errorf("wrong standard maturity; got: %v, expected: %v", maturity, expected)
+	}
+}
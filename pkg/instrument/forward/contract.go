@@ -1,6 +1,8 @@
 package forward
 
 import (
+	"fmt"
+
 	"github.com/konimarti/fixedincome/pkg/term"
 )
 
@@ -10,11 +12,11 @@ import (
 // called the forward price.
 type Contract struct {
 	// K is the delivery price agreed upon at initiation
-	K float64
+	K float64 `json:"k"`
 	// F is the current forward price (F_t,T) at time t
-	F float64
+	F float64 `json:"f"`
 	// T is the remaining maturity of the forward contract (M=T-t)
-	T float64
+	T float64 `json:"t"`
 }
 
 // PresentValue returns the value of the forward contract
@@ -31,12 +33,15 @@ func ZeroBondPrice(t, m float64, ts term.Structure) (float64, error) {
 	return ts.Z(m) / ts.Z(t), nil
 }
 
-// // Fx calculates the forward rate for the currency pair (two term structure)
-// // If currentFx is CHF/EUR, then tsLong should be CHF rats and tsShort should be EUR rates
-// func Fx(currentFx, t float64, tsLong, tsShort term.Structure) (float64, error) {
-// 	return currentFx * tsShort.Z(t) / tsLong.Z(t), nil
-// }
-//
+// Fx calculates the forward rate for the currency pair (two term structure)
+// If currentFx is CHF/EUR, then tsLong should be CHF rates and tsShort should be EUR rates
+func Fx(currentFx, t float64, tsLong, tsShort term.Structure) (float64, error) {
+	z := tsLong.Z(t)
+	if z == 0.0 {
+		return 0.0, fmt.Errorf("discount factor of domestic term structure is zero")
+	}
+	return currentFx * tsShort.Z(t) / z, nil
+}
 
 //
 // // StockPrice calculates the forward price for a stock with no dividends
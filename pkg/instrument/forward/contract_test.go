@@ -36,7 +36,17 @@ func TestZeroBondPrice(t *testing.T) {
 }
 
 func TestFx(t *testing.T) {
-	// t.Errorf("not implemented yet")
+	chf := term.Flat{R: -0.75}
+	eur := term.Flat{R: -0.50}
+	spot := 1.05 // CHF per EUR
+	fx, err := forward.Fx(spot, 2.0, &chf, &eur)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := spot * math.Exp((-0.0075+0.0050)*2.0)
+	if math.Abs(fx-expected) > 0.00001 {
+		t.Errorf("wrong fx forward rate; got: %v, expected: %v", fx, expected)
+	}
 }
 
 func TestStockPrice(t *testing.T) {
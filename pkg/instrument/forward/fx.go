@@ -0,0 +1,70 @@
+package forward
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// FxForward is a contract to buy a notional amount of foreign currency at the
+// future time T for the delivery price K (in units of domestic currency per
+// unit of foreign currency). The value is expressed in domestic currency.
+type FxForward struct {
+	// Notional is the amount of foreign currency bought at maturity (a
+	// negative notional sells the foreign currency)
+	Notional float64
+	// K is the delivery exchange rate (domestic per foreign) agreed upon at initiation
+	K float64
+	// T is the remaining maturity of the forward contract
+	T float64
+	// Spot is the current spot exchange rate (domestic per foreign)
+	Spot float64
+	// Foreign is the term structure of the foreign currency
+	Foreign term.Structure
+}
+
+// fxForwardJSON is the json representation of an FX forward
+type fxForwardJSON struct {
+	Notional float64     `json:"notional"`
+	K        float64     `json:"k"`
+	T        float64     `json:"t"`
+	Spot     float64     `json:"spot"`
+	Foreign  term.Tagged `json:"foreign"`
+}
+
+// MarshalJSON implements the json.Marshaler interface with the foreign term
+// structure tagged with its type
+func (f FxForward) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fxForwardJSON{f.Notional, f.K, f.T, f.Spot, term.Tagged{Structure: f.Foreign}})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (f *FxForward) UnmarshalJSON(data []byte) error {
+	var s fxForwardJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*f = FxForward{Notional: s.Notional, K: s.K, T: s.T, Spot: s.Spot, Foreign: s.Foreign.Structure}
+	return nil
+}
+
+// PresentValue returns the value of the FX forward in domestic currency
+// where ts is the domestic term structure
+func (f *FxForward) PresentValue(ts term.Structure) float64 {
+	return f.Notional * (f.Spot*f.Foreign.Z(f.T) - f.K*ts.Z(f.T))
+}
+
+// ForeignValue returns the value of the FX forward in foreign currency; it
+// returns an error if the spot exchange rate is not positive
+func (f *FxForward) ForeignValue(ts term.Structure) (float64, error) {
+	if f.Spot <= 0.0 {
+		return 0.0, fmt.Errorf("spot exchange rate %v is not positive", f.Spot)
+	}
+	return f.PresentValue(ts) / f.Spot, nil
+}
+
+// Rate returns the fair forward exchange rate for maturity T
+func (f *FxForward) Rate(ts term.Structure) (float64, error) {
+	return Fx(f.Spot, f.T, ts, f.Foreign)
+}
@@ -0,0 +1,66 @@
+package forward_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/forward"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestFxForward(t *testing.T) {
+	chf := term.Flat{R: -0.75}
+	eur := term.Flat{R: -0.50}
+
+	contract := forward.FxForward{
+		Notional: 1e6,
+		T:        1.5,
+		Spot:     1.05,
+		Foreign:  &eur,
+	}
+
+	// contract at the forward rate has zero value
+	k, err := contract.Rate(&chf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract.K = k
+	if value := contract.PresentValue(&chf); math.Abs(value) > 1e-6 {
+		t.Errorf("wrong value of fx forward; got: %v, expected: %v", value, 0.0)
+	}
+
+	// off-market contract
+	contract.K = k - 0.01
+	expected := 1e6 * 0.01 * chf.Z(1.5)
+	if value := contract.PresentValue(&chf); math.Abs(value-expected) > 1e-6 {
+		t.Errorf("wrong value of fx forward; got: %v, expected: %v", value, expected)
+	}
+	if value, err := contract.ForeignValue(&chf); err != nil || math.Abs(value-expected/1.05) > 1e-6 {
+		t.Errorf("wrong foreign value of fx forward; got: %v and error %v, expected: %v", value, err, expected/1.05)
+	}
+	contract.Spot = 0.0
+	if _, err := contract.ForeignValue(&chf); err == nil {
+		t.Errorf("expected error for zero spot exchange rate")
+	}
+}
+
+func TestFxForwardJSON(t *testing.T) {
+	contract := forward.FxForward{Notional: 1e6, K: 1.04, T: 1.5, Spot: 1.05, Foreign: &term.Flat{R: -0.50}}
+	data, err := json.Marshal(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"notional":1000000,"k":1.04,"t":1.5,"spot":1.05,"foreign":{"type":"flat","r":-0.5,"spread":0}}`
+	if string(data) != expected {
+		t.Errorf("got %s, expected %s", data, expected)
+	}
+	var parsed forward.FxForward
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	chf := &term.Flat{R: -0.75}
+	if parsed.PresentValue(chf) != contract.PresentValue(chf) {
+		t.Errorf("got value %v, expected %v", parsed.PresentValue(chf), contract.PresentValue(chf))
+	}
+}
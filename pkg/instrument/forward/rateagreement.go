@@ -9,18 +9,18 @@ import "github.com/konimarti/fixedincome/pkg/term"
 // to pay according to the future market floating rate r_n(T_1,T_2).
 type RateAgreement struct {
 	// N is the notional amount
-	N float64
+	N float64 `json:"n"`
 
 	// M is the number of securities in the long position to T_2
 	// determined at t=0 in order to ensure that the FRA is 0 at initiation
 	// M = Z(0,T_1) / Z(0,T_2)
-	M float64
+	M float64 `json:"m"`
 
 	// T1 is the beginning time for paying the forward rate
-	T1 float64
+	T1 float64 `json:"t1"`
 
 	// T2 is the ending time for paying the forward rate
-	T2 float64
+	T2 float64 `json:"t2"`
 }
 
 // PresentValue calculated the value of the FRA at time t
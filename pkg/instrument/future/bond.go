@@ -0,0 +1,265 @@
+package future
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// Deliverable is a bond in the deliverable basket of a bond future
+type Deliverable struct {
+	// Bond is the deliverable bond; the settlement date of its schedule is
+	// the valuation date
+	Bond bond.Straight `json:"bond"`
+	// Price is the quoted clean price of the bond at the valuation date (if
+	// it is zero, the price is calculated from the term structure)
+	Price float64 `json:"price"`
+	// ConversionFactor of the bond (if it is zero, it is calculated with
+	// the rules of the exchange)
+	ConversionFactor float64 `json:"conversionfactor"`
+}
+
+// BondFuture implements a futures contract on a notional bond which is
+// settled by delivering one of the bonds in the deliverable basket (e.g.
+// Euro-Bund, Swiss CONF, US Treasury bond futures)
+type BondFuture struct {
+	// Rule is the exchange rule for the conversion factors (Eurex or CBOT)
+	Rule int
+	// NotionalCoupon is the coupon of the notional bond in percent
+	NotionalCoupon float64
+	// Delivery is the delivery date of the futures contract
+	Delivery time.Time
+	// Price is the quoted futures price
+	Price float64
+	// RepoRate is the (simple, act/360) repo rate in percent for financing
+	// the deliverable bonds up to delivery
+	RepoRate float64
+	// Basket contains the deliverable bonds
+	Basket []Deliverable
+}
+
+// bondFutureJSON is the json representation of a bond future
+type bondFutureJSON struct {
+	Rule           int           `json:"rule"`
+	NotionalCoupon float64       `json:"notionalcoupon"`
+	Delivery       maturity.Date `json:"delivery"`
+	Price          float64       `json:"price"`
+	RepoRate       float64       `json:"reporate"`
+	Basket         []Deliverable `json:"basket"`
+}
+
+// MarshalJSON implements the json.Marshaler interface with the delivery date
+// as string
+func (f BondFuture) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bondFutureJSON{f.Rule, f.NotionalCoupon, maturity.Date(f.Delivery), f.Price, f.RepoRate, f.Basket})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (f *BondFuture) UnmarshalJSON(data []byte) error {
+	var s bondFutureJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*f = BondFuture{s.Rule, s.NotionalCoupon, time.Time(s.Delivery), s.Price, s.RepoRate, s.Basket}
+	return nil
+}
+
+// EuroBund returns a Euro-Bund futures contract (Eurex, 6% notional coupon)
+func EuroBund(delivery time.Time, price float64, basket []Deliverable) *BondFuture {
+	return &BondFuture{Rule: Eurex, NotionalCoupon: 6.0, Delivery: delivery, Price: price, Basket: basket}
+}
+
+// CONF returns a Swiss government bond futures contract (Eurex, 6% notional coupon)
+func CONF(delivery time.Time, price float64, basket []Deliverable) *BondFuture {
+	return &BondFuture{Rule: Eurex, NotionalCoupon: 6.0, Delivery: delivery, Price: price, Basket: basket}
+}
+
+// USTreasury returns a US Treasury bond futures contract (CBOT, 6% notional coupon)
+func USTreasury(delivery time.Time, price float64, basket []Deliverable) *BondFuture {
+	return &BondFuture{Rule: CBOT, NotionalCoupon: 6.0, Delivery: delivery, Price: price, Basket: basket}
+}
+
+// Analysis contains the basis analysis of a deliverable bond
+type Analysis struct {
+	// ConversionFactor of the bond
+	ConversionFactor float64
+	// Clean and Dirty are the clean and dirty prices at the valuation date
+	Clean float64
+	Dirty float64
+	// AccruedAtDelivery is the accrued interest at the delivery date
+	AccruedAtDelivery float64
+	// ForwardDirty is the forward dirty price at delivery implied by the
+	// term structure
+	ForwardDirty float64
+	// RepoForwardDirty is the forward dirty price at delivery from
+	// financing the bond at the repo rate
+	RepoForwardDirty float64
+	// ImpliedRepo is the repo rate in percent (simple, act/360) earned by
+	// buying the bond and delivering it into the futures contract
+	ImpliedRepo float64
+	// GrossBasis is the clean price minus the converted futures price
+	GrossBasis float64
+	// Carry is the coupon income minus the financing cost up to delivery
+	Carry float64
+	// NetBasis is the gross basis minus the carry
+	NetBasis float64
+}
+
+// days returns the actual number of days between two dates
+func days(d1, d2 time.Time) float64 {
+	return math.Round(d2.Sub(d1).Hours() / 24.0)
+}
+
+// conversionFactor returns the conversion factor of the i-th deliverable
+func (f *BondFuture) conversionFactor(i int) float64 {
+	d := f.Basket[i]
+	if d.ConversionFactor != 0.0 {
+		return d.ConversionFactor
+	}
+	return ConversionFactor(f.Rule, f.NotionalCoupon, f.Delivery, d.Bond.Maturity, d.Bond.Coupon)
+}
+
+// Analyze returns the basis analysis of the i-th deliverable bond
+func (f *BondFuture) Analyze(i int, ts term.Structure) (Analysis, error) {
+	if i < 0 || i >= len(f.Basket) {
+		return Analysis{}, fmt.Errorf("basket has no bond %d", i)
+	}
+	b := f.Basket[i].Bond
+	settlement := b.Settlement
+	if !f.Delivery.After(settlement) {
+		return Analysis{}, fmt.Errorf("delivery date is not after valuation date")
+	}
+	if !b.Maturity.After(f.Delivery) {
+		return Analysis{}, fmt.Errorf("bond %d matures before delivery", i)
+	}
+
+	a := Analysis{ConversionFactor: f.conversionFactor(i)}
+
+	// spot prices
+	a.Dirty = b.PresentValue(ts)
+	a.Clean = a.Dirty - b.Accrued()
+	if p := f.Basket[i].Price; p != 0.0 {
+		a.Clean = p
+		a.Dirty = p + b.Accrued()
+	}
+
+	// accrued interest at delivery
+	atDelivery := b
+	atDelivery.Settlement = f.Delivery
+	a.AccruedAtDelivery = atDelivery.Accrued()
+
+	// coupons paid up to delivery
+	effCoupon := b.EffectiveCoupon(b.Coupon)
+	tDelivery := maturity.YearFraction(settlement, f.Delivery, b.Basis)
+	interim, interimValue, interimRepo, interimDays := 0.0, 0.0, 0.0, 0.0
+	for _, date := range b.Dates() {
+		if date.After(f.Delivery) {
+			break
+		}
+		interim += effCoupon
+		interimValue += effCoupon * ts.Z(maturity.YearFraction(settlement, date, b.Basis))
+		interimRepo += effCoupon * (1.0 + f.RepoRate/100.0*days(date, f.Delivery)/360.0)
+		interimDays += effCoupon * days(date, f.Delivery)
+	}
+
+	// forward prices
+	z := ts.Z(tDelivery)
+	if z == 0.0 {
+		return a, fmt.Errorf("discount factor at delivery is zero")
+	}
+	a.ForwardDirty = (b.PresentValue(ts) - interimValue) / z
+	period := days(settlement, f.Delivery)
+	a.RepoForwardDirty = a.Dirty*(1.0+f.RepoRate/100.0*period/360.0) - interimRepo
+
+	// implied repo rate
+	invoice := f.Price*a.ConversionFactor + a.AccruedAtDelivery
+	denominator := a.Dirty*period - interimDays
+	if denominator != 0.0 {
+		a.ImpliedRepo = (invoice + interim - a.Dirty) / denominator * 360.0 * 100.0
+	}
+
+	// basis
+	a.GrossBasis = a.Clean - f.Price*a.ConversionFactor
+	a.Carry = a.Clean - (a.RepoForwardDirty - a.AccruedAtDelivery)
+	a.NetBasis = a.GrossBasis - a.Carry
+
+	return a, nil
+}
+
+// CheapestToDeliver returns the index of the deliverable bond with the
+// highest implied repo rate and its analysis
+func (f *BondFuture) CheapestToDeliver(ts term.Structure) (int, Analysis, error) {
+	ctd, best := -1, Analysis{}
+	for i := range f.Basket {
+		a, err := f.Analyze(i, ts)
+		if err != nil {
+			return -1, Analysis{}, err
+		}
+		if ctd < 0 || a.ImpliedRepo > best.ImpliedRepo {
+			ctd, best = i, a
+		}
+	}
+	if ctd < 0 {
+		return -1, Analysis{}, fmt.Errorf("deliverable basket is empty")
+	}
+	return ctd, best, nil
+}
+
+// FairPrice returns the theoretical futures price from the term structure,
+// i.e. the lowest converted forward clean price in the basket
+func (f *BondFuture) FairPrice(ts term.Structure) (float64, error) {
+	fair := math.Inf(1)
+	for i := range f.Basket {
+		a, err := f.Analyze(i, ts)
+		if err != nil {
+			return 0.0, err
+		}
+		if price := (a.ForwardDirty - a.AccruedAtDelivery) / a.ConversionFactor; price < fair {
+			fair = price
+		}
+	}
+	if math.IsInf(fair, 1) {
+		return 0.0, fmt.Errorf("deliverable basket is empty")
+	}
+	return fair, nil
+}
+
+// Value returns the value of a long futures position (per 100 of notional)
+// as the difference between the fair and the quoted futures price discounted
+// from the delivery date
+func (f *BondFuture) Value(ts term.Structure) (float64, error) {
+	fair, err := f.FairPrice(ts)
+	if err != nil {
+		return 0.0, err
+	}
+	t := maturity.YearFraction(f.Basket[0].Bond.Settlement, f.Delivery, f.Basket[0].Bond.Basis)
+	return (fair - f.Price) * ts.Z(t), nil
+}
+
+// PresentValue returns the value of a long futures position or NaN if the
+// future cannot be valued (see Value)
+func (f *BondFuture) PresentValue(ts term.Structure) float64 {
+	value, err := f.Value(ts)
+	if err != nil {
+		return math.NaN()
+	}
+	return value
+}
+
+// PVBP returns the change of the futures price for a parallel shift of one
+// basis point of the term structure, i.e. the price value of a basis point of
+// the cheapest-to-deliver bond divided by its conversion factor
+func (f *BondFuture) PVBP(ts term.Structure) (float64, error) {
+	ctd, a, err := f.CheapestToDeliver(ts)
+	if err != nil {
+		return 0.0, err
+	}
+	b := f.Basket[ctd].Bond
+	pvbp := 0.0001 * b.Duration(ts) * b.PresentValue(ts)
+	return pvbp / a.ConversionFactor, nil
+}
@@ -0,0 +1,94 @@
+package future_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/instrument/future"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestBondFuture(t *testing.T) {
+	settlement := time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+	delivery := time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	ts := term.NelsonSiegelSvensson{
+		B0: -0.43381,
+		B1: -0.308942,
+		B2: 4.83643,
+		B3: -4.10991,
+		T1: 4.65211,
+		T2: 3.33637,
+	}
+
+	newBond := func(m time.Time, coupon float64) bond.Straight {
+		return bond.Straight{
+			Schedule: maturity.Schedule{
+				Settlement: settlement,
+				Maturity:   m,
+				Frequency:  1,
+				Basis:      "30E360",
+			},
+			Coupon:     coupon,
+			Redemption: 100.0,
+		}
+	}
+
+	conf := future.CONF(delivery, 0.0, []future.Deliverable{
+		{Bond: newBond(time.Date(2029, 6, 27, 0, 0, 0, 0, time.UTC), 0.0)},
+		{Bond: newBond(time.Date(2031, 6, 27, 0, 0, 0, 0, time.UTC), 0.5)},
+		{Bond: newBond(time.Date(2032, 1, 14, 0, 0, 0, 0, time.UTC), 4.0)},
+	})
+	conf.RepoRate = -0.75
+
+	fair, err := conf.FairPrice(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.Price = fair
+
+	// futures at fair price has zero value
+	if value := conf.PresentValue(&ts); math.Abs(value) > 1e-9 {
+		t.Errorf("wrong futures value; got: %v, expected: %v", value, 0.0)
+	}
+
+	ctd, a, err := conf.CheapestToDeliver(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cheapest-to-deliver has the lowest converted forward price
+	for i := range conf.Basket {
+		other, err := conf.Analyze(i, &ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other.ImpliedRepo > a.ImpliedRepo {
+			t.Errorf("bond %d has higher implied repo than ctd %d", i, ctd)
+		}
+	}
+	if price := (a.ForwardDirty - a.AccruedAtDelivery) / a.ConversionFactor; math.Abs(price-fair) > 1e-9 {
+		t.Errorf("ctd does not determine fair price; got: %v, expected: %v", price, fair)
+	}
+
+	// financing at the implied repo rate gives zero net basis
+	conf.RepoRate = a.ImpliedRepo
+	a, err = conf.Analyze(ctd, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(a.NetBasis) > 1e-9 {
+		t.Errorf("net basis at implied repo rate should be zero; got: %v", a.NetBasis)
+	}
+	if math.Abs(a.GrossBasis-a.Carry) > 1e-9 {
+		t.Errorf("gross basis should equal carry at implied repo rate; got: %v, %v", a.GrossBasis, a.Carry)
+	}
+
+	// hedge ratio is negative for a long futures position
+	if pvbp, err := conf.PVBP(&ts); err != nil || pvbp >= 0.0 {
+		t.Errorf("wrong pvbp of futures; got: %v, %v", pvbp, err)
+	}
+}
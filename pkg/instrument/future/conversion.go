@@ -0,0 +1,90 @@
+package future
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Eurex computes the conversion factors for Euro-Bund, Euro-Bobl,
+	// Euro-Schatz and CONF futures
+	Eurex int = iota
+	// CBOT computes the conversion factors for US Treasury bond and note futures
+	CBOT
+)
+
+// ConversionFactor calculates the conversion factor of a deliverable bond
+// with the given coupon (in percent) and maturity according to the rules of
+// the exchange. The notional coupon is given in percent (e.g. 6.0).
+func ConversionFactor(rule int, notionalCoupon float64, delivery, maturity time.Time, coupon float64) float64 {
+	if rule == CBOT {
+		return cbotConversionFactor(notionalCoupon, delivery, maturity, coupon)
+	}
+	return eurexConversionFactor(notionalCoupon, delivery, maturity, coupon)
+}
+
+// eurexConversionFactor implements the conversion factor for bonds with annual
+// coupons according to the Eurex rules (actual/actual)
+func eurexConversionFactor(nc float64, delivery, maturity time.Time, coupon float64) float64 {
+	// next coupon date after delivery
+	ncd := maturity
+	for ncd.AddDate(-1, 0, 0).After(delivery) {
+		ncd = ncd.AddDate(-1, 0, 0)
+	}
+	ncd1y := ncd.AddDate(-1, 0, 0)
+	ncd2y := ncd.AddDate(-2, 0, 0)
+	lcd := ncd1y
+
+	days := func(d1, d2 time.Time) float64 {
+		return math.Round(d2.Sub(d1).Hours() / 24.0)
+	}
+
+	deltaE := days(delivery, ncd1y)
+	deltaI := days(lcd, ncd1y)
+
+	act1 := days(ncd2y, ncd1y)
+	if deltaE < 0 {
+		act1 = days(ncd1y, ncd)
+	}
+	act2 := days(ncd2y, ncd1y)
+	if deltaI < 0 {
+		act2 = days(ncd1y, ncd)
+	}
+
+	f := 1.0 + deltaE/act1
+	n := float64(maturity.Year() - ncd.Year())
+	v := 1.0 / (1.0 + nc/100.0)
+
+	cf := math.Pow(v, f) * (coupon/100.0*deltaI/act2 + coupon/nc*(1.0+nc/100.0-math.Pow(v, n)) + math.Pow(v, n))
+	cf -= coupon / 100.0 * (deltaI/act2 - deltaE/act1)
+
+	return math.Round(cf*1e6) / 1e6
+}
+
+// cbotConversionFactor implements the conversion factor for bonds with
+// semi-annual coupons according to the CBOT rules, i.e. the price of the bond
+// per unit of par yielding the notional coupon with the remaining maturity
+// from the first day of the delivery month rounded down to full quarters
+func cbotConversionFactor(nc float64, delivery, maturity time.Time, coupon float64) float64 {
+	first := time.Date(delivery.Year(), delivery.Month(), 1, 0, 0, 0, 0, time.UTC)
+	months := (maturity.Year()-first.Year())*12 + int(maturity.Month()-first.Month())
+	months -= months % 3
+
+	c := coupon / 100.0
+	y := nc / 200.0
+	v := 1.0 / (1.0 + y)
+
+	// price just after a coupon payment with k remaining periods
+	price := func(k int) float64 {
+		vk := math.Pow(v, float64(k))
+		return c/2.0*(1.0-vk)/y + vk
+	}
+
+	n := months / 6
+	cf := price(n)
+	if months%6 == 3 {
+		cf = (c/2.0+price(n))*math.Sqrt(v) - c/4.0
+	}
+
+	return math.Round(cf*1e4) / 1e4
+}
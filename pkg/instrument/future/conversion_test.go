@@ -0,0 +1,40 @@
+package future_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/future"
+)
+
+func TestConversionFactor(t *testing.T) {
+	delivery := time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	testData := []struct {
+		Rule     int
+		Maturity time.Time
+		Coupon   float64
+		Expected float64
+	}{
+		// bond with notional coupon
+		{future.Eurex, time.Date(2031, 3, 10, 0, 0, 0, 0, time.UTC), 6.0, 1.0},
+		{future.CBOT, time.Date(2042, 3, 15, 0, 0, 0, 0, time.UTC), 6.0, 1.0},
+		// 4.5% bond with 9 years to maturity at coupon date:
+		// 0.045 * (1 - 1.06^-9) / 0.06 + 1.06^-9
+		{future.Eurex, time.Date(2031, 3, 10, 0, 0, 0, 0, time.UTC), 4.5, 0.897975},
+		// 4.5% bond with 20 years to maturity:
+		// 0.0225 * (1 - 1.03^-40) / 0.03 + 1.03^-40
+		{future.CBOT, time.Date(2042, 3, 15, 0, 0, 0, 0, time.UTC), 4.5, 0.8266},
+		{future.CBOT, time.Date(2042, 5, 15, 0, 0, 0, 0, time.UTC), 6.0, 1.0},
+		// between coupon dates: 1.06^-(1-181/365) * 1.06 - 0.06 * 181/365
+		{future.Eurex, time.Date(2031, 9, 10, 0, 0, 0, 0, time.UTC), 6.0, 0.999563},
+	}
+
+	for i, test := range testData {
+		cf := future.ConversionFactor(test.Rule, 6.0, delivery, test.Maturity, test.Coupon)
+		if math.Abs(cf-test.Expected) > 0.0001 {
+			t.Errorf("test nr %d: wrong conversion factor; got: %v, expected: %v", i, cf, test.Expected)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package instrument marshals the securities to json tagged with their type,
+// e.g. {"type":"straight","schedule":{...},"coupon":1.25,"redemption":100},
+// so that trades can be stored and exchanged. Dates are strings (format:
+// 2006-01-02) and term structures are tagged as well (see term.Marshal).
+// Market data that is not part of the trade (e.g. fixings) is not marshalled.
+package instrument
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/credit"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/instrument/cds"
+	"github.com/konimarti/fixedincome/pkg/instrument/forward"
+	"github.com/konimarti/fixedincome/pkg/instrument/future"
+	"github.com/konimarti/fixedincome/pkg/instrument/option"
+	"github.com/konimarti/fixedincome/pkg/instrument/repo"
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+)
+
+// types maps the type tags to the securities
+var types = map[string]func() fixedincome.Security{
+	"straight":   func() fixedincome.Security { return &bond.Straight{} },
+	"floating":   func() fixedincome.Security { return &bond.Floating{} },
+	"riskybond":  func() fixedincome.Security { return &credit.RiskyBond{} },
+	"european":   func() fixedincome.Security { return &option.European{} },
+	"forward":    func() fixedincome.Security { return &forward.Contract{} },
+	"fxforward":  func() fixedincome.Security { return &forward.FxForward{} },
+	"fra":        func() fixedincome.Security { return &forward.RateAgreement{} },
+	"irs":        func() fixedincome.Security { return &swap.InterestRateSwap{} },
+	"swap":       func() fixedincome.Security { return &swap.Swap{} },
+	"ois":        func() fixedincome.Security { return &swap.OvernightIndexSwap{} },
+	"ccs":        func() fixedincome.Security { return &swap.CrossCurrencySwap{} },
+	"bondfuture": func() fixedincome.Security { return &future.BondFuture{} },
+	"repo":       func() fixedincome.Security { return &repo.Agreement{} },
+	"cds":        func() fixedincome.Security { return &cds.Contract{} },
+}
+
+// Types returns the sorted type tags of the securities
+func Types() []string {
+	names := []string{}
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TypeOf returns the type tag of the security
+func TypeOf(s fixedincome.Security) (string, error) {
+	for name, newSecurity := range types {
+		if reflect.TypeOf(newSecurity()) == reflect.TypeOf(s) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown security %T", s)
+}
+
+// Marshal returns the json of the security with its type tag as first key
+func Marshal(s fixedincome.Security) ([]byte, error) {
+	name, err := TypeOf(s)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	tag := []byte(fmt.Sprintf(`{"type":%q`, name))
+	if bytes.Equal(data, []byte("{}")) {
+		return append(tag, '}'), nil
+	}
+	return append(append(tag, ','), data[1:]...), nil
+}
+
+// Unmarshal returns the security of the json written by Marshal
+func Unmarshal(data []byte) (fixedincome.Security, error) {
+	var tag struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &tag); err != nil {
+		return nil, err
+	}
+	if tag.Type == "" {
+		return nil, fmt.Errorf("security type missing")
+	}
+	newSecurity, ok := types[tag.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown security type %q (types: %s)", tag.Type, strings.Join(Types(), ", "))
+	}
+	s := newSecurity()
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%s: %v", tag.Type, err)
+	}
+	return s, nil
+}
+
+// Tagged wraps a security for fields of other types so that it is marshalled
+// with Marshal and unmarshalled with Unmarshal (null for nil)
+type Tagged struct {
+	fixedincome.Security
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (t Tagged) MarshalJSON() ([]byte, error) {
+	if t.Security == nil {
+		return []byte("null"), nil
+	}
+	return Marshal(t.Security)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (t *Tagged) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		t.Security = nil
+		return nil
+	}
+	s, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	t.Security = s
+	return nil
+}
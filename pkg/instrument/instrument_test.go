@@ -0,0 +1,190 @@
+package instrument_test
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/credit"
+	"github.com/konimarti/fixedincome/pkg/instrument"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/instrument/cds"
+	"github.com/konimarti/fixedincome/pkg/instrument/forward"
+	"github.com/konimarti/fixedincome/pkg/instrument/future"
+	"github.com/konimarti/fixedincome/pkg/instrument/option"
+	"github.com/konimarti/fixedincome/pkg/instrument/repo"
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+var settlement = time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+
+// securities returns one security of each type
+func securities() map[string]fixedincome.Security {
+	schedule := maturity.Schedule{
+		Settlement: settlement,
+		Maturity:   time.Date(2026, 5, 28, 0, 0, 0, 0, time.UTC),
+		Frequency:  1,
+		Basis:      "30E360",
+	}
+	straight := bond.Straight{Schedule: schedule, Coupon: 1.25, Redemption: 100.0}
+	floating := bond.Floating{Schedule: schedule, Rate: 0.5, Redemption: 100.0}
+	legSchedule := func(direction int) swap.LegSchedule {
+		return swap.LegSchedule{
+			Direction: direction,
+			Effective: settlement.AddDate(0, 0, 2),
+			Maturity:  settlement.AddDate(5, 0, 2),
+			Frequency: 2,
+			Notional:  1e6,
+		}
+	}
+	curve, _ := credit.NewHazardCurve([]float64{1.0, 10.0}, []float64{1.0, 2.0})
+	contract := cds.New(cds.Buyer, settlement, 60, 100.0, 1e7, 40.0)
+	contract.Curve = curve
+
+	return map[string]fixedincome.Security{
+		"straight":  &straight,
+		"floating":  &floating,
+		"riskybond": &credit.RiskyBond{Straight: straight, Curve: curve, Recovery: 40.0, Convention: credit.RecoveryOfMarket},
+		"european":  &option.European{Type: option.Put, S: 100.0, K: 95.0, T: 0.5, Q: 1.0, Vola: 0.2},
+		"forward":   &forward.Contract{K: 100.0, F: 102.0, T: 1.0},
+		"fxforward": &forward.FxForward{Notional: 1e6, K: 1.04, T: 1.5, Spot: 1.05, Foreign: &term.Flat{R: -0.5}},
+		"fra":       &forward.RateAgreement{N: 1e6, M: 1.01, T1: 1.0, T2: 1.5},
+		"irs":       &swap.InterestRateSwap{Floating: floating, Fixed: straight},
+		"swap": &swap.Swap{
+			Settlement: settlement,
+			Legs: []swap.Leg{
+				&swap.FixedLeg{LegSchedule: legSchedule(swap.Receive), Rate: 0.75},
+				&swap.FloatingLeg{LegSchedule: legSchedule(swap.Pay), Spread: 10.0},
+			},
+		},
+		"ois": &swap.OvernightIndexSwap{
+			Settlement: settlement,
+			Effective:  settlement.AddDate(0, 0, 2),
+			Maturity:   settlement.AddDate(2, 0, 2),
+			FixedRate:  -0.5,
+			Notional:   1e6,
+			Overnight:  swap.Overnight{Index: "SARON"},
+		},
+		"ccs": &swap.CrossCurrencySwap{
+			Settlement:   settlement,
+			Domestic:     &swap.FixedLeg{LegSchedule: legSchedule(swap.Receive), Rate: 0.5},
+			Foreign:      &swap.FloatingLeg{LegSchedule: legSchedule(swap.Pay)},
+			Spot:         1.05,
+			ForeignCurve: &term.Flat{R: -0.5},
+		},
+		"bondfuture": future.CONF(time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC), 160.0, []future.Deliverable{{Bond: straight, Price: 105.0}}),
+		"repo": &repo.Agreement{
+			Type:       repo.ReverseRepo,
+			Settlement: settlement,
+			Start:      settlement,
+			End:        settlement.AddDate(0, 3, 0),
+			Collateral: straight,
+			Nominal:    1e6,
+			Price:      106.0,
+			Haircut:    2.0,
+			Rate:       -0.6,
+		},
+		"cds": contract,
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	ts := &term.Flat{R: 0.5}
+	all := securities()
+	if len(all) != len(instrument.Types()) {
+		t.Errorf("got %d securities for %d types", len(all), len(instrument.Types()))
+	}
+	for name, s := range all {
+		data, err := instrument.Marshal(s)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if prefix := `{"type":"` + name + `",`; !strings.HasPrefix(string(data), prefix) {
+			t.Errorf("%s: got %s", name, data)
+		}
+		if strings.Contains(string(data), "T00:00:00") {
+			t.Errorf("%s: dates not formatted in %s", name, data)
+		}
+
+		parsed, err := instrument.Unmarshal(data)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if reflect.TypeOf(parsed) != reflect.TypeOf(s) {
+			t.Errorf("%s: got %T, expected %T", name, parsed, s)
+			continue
+		}
+		if pv, expected := parsed.PresentValue(ts), s.PresentValue(ts); math.Abs(pv-expected) > 1e-9*math.Max(1.0, math.Abs(expected)) {
+			t.Errorf("%s: got present value %v, expected %v", name, pv, expected)
+		}
+
+		// marshalling is stable
+		again, err := instrument.Marshal(parsed)
+		if err != nil || string(again) != string(data) {
+			t.Errorf("%s: got %s, expected %s", name, again, data)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	invalid := []string{
+		`{"coupon": 1.25}`,
+		`{"type": "convertible"}`,
+		`{"type": "straight", "schedule": {"maturity": "28.05.2026"}}`,
+		`{"type": "swap", "legs": [{"type": "cap"}]}`,
+		`{"type": "fxforward", "foreign": {"type": "cubic"}}`,
+		`{"type": "cds", "trade": "2021-04-01", "maturity": "2026-06-20", "curve": null}`,
+		`{"type": "riskybond", "bond": {}, "recovery": 40}`,
+		`[]`,
+	}
+	for _, data := range invalid {
+		if _, err := instrument.Unmarshal([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", data)
+		}
+	}
+
+	// unknown security types
+	if _, err := instrument.Marshal(&struct{ option.European }{}); err == nil {
+		t.Errorf("expected error for unknown security")
+	}
+}
+
+func TestTagged(t *testing.T) {
+	type trade struct {
+		ID       string            `json:"id"`
+		Security instrument.Tagged `json:"security"`
+	}
+	trades := []trade{
+		{"T1", instrument.Tagged{Security: securities()["straight"]}},
+		{"T2", instrument.Tagged{Security: securities()["european"]}},
+		{"T3", instrument.Tagged{}},
+	}
+	data, err := json.Marshal(trades)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := []trade{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != 3 {
+		t.Fatalf("got %d trades", len(parsed))
+	}
+	if _, ok := parsed[0].Security.Security.(*bond.Straight); !ok {
+		t.Errorf("got %T, expected straight bond", parsed[0].Security.Security)
+	}
+	if o, ok := parsed[1].Security.Security.(*option.European); !ok || o.Type != option.Put {
+		t.Errorf("got %+v, expected put option", parsed[1].Security.Security)
+	}
+	if parsed[2].Security.Security != nil {
+		t.Errorf("got %v, expected nil", parsed[2].Security.Security)
+	}
+}
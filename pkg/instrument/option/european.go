@@ -13,18 +13,19 @@ const (
 
 // European is the implementation of plain vanilla European option
 type European struct {
-	// Type is the type of the option (call=0, put=1)
-	Type int
+	// Type is the type of the option (call=0, put=1); the json key is "kind"
+	// as "type" tags the security
+	Type int `json:"kind"`
 	// S is the price of the underlying asset
-	S float64
+	S float64 `json:"s"`
 	// K is the strike price
-	K float64
+	K float64 `json:"k"`
 	// T is remaining maturity in years
-	T float64
+	T float64 `json:"t"`
 	// Q is the dividend yield in percent
-	Q float64
+	Q float64 `json:"q"`
 	// Vola is the volatility of the underlying asset
-	Vola float64
+	Vola float64 `json:"vola"`
 }
 
 // Presentvalues implements the Black-Scholes pricing for European call and put options
@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// Carry contains the decomposition of the expected return (per 100 nominal)
+// of a bond position financed in the repo market up to a horizon date
+type Carry struct {
+	// Clean is the clean price at the valuation date
+	Clean float64
+	// ForwardClean is the forward clean price at the horizon from the repo rate
+	ForwardClean float64
+	// HorizonClean is the clean price at the horizon if the term structure
+	// remains unchanged
+	HorizonClean float64
+	// Income is the coupon income (coupons and change of accrued interest)
+	Income float64
+	// Financing is the repo interest paid on the dirty price
+	Financing float64
+	// Carry is the coupon income minus the financing cost, i.e. the
+	// difference between the spot and the forward clean price
+	Carry float64
+	// RollDown is the change of the clean price from rolling down the
+	// unchanged term structure
+	RollDown float64
+	// Total is the carry plus the roll-down
+	Total float64
+}
+
+// CarryAndRollDown calculates carry and roll-down of a long bond position
+// financed at the repo rate (in percent, simple act/360) up to the horizon
+// date. The clean price is calculated from the term structure if it is zero.
+func CarryAndRollDown(b bond.Straight, clean float64, horizon time.Time, repoRate float64, ts term.Structure) (Carry, error) {
+	if !horizon.After(b.Settlement) {
+		return Carry{}, fmt.Errorf("horizon is not after valuation date")
+	}
+	if !b.Maturity.After(horizon) {
+		return Carry{}, fmt.Errorf("bond matures before horizon")
+	}
+
+	c := Carry{Clean: clean}
+	if c.Clean == 0.0 {
+		c.Clean = b.PresentValue(ts) - b.Accrued()
+	}
+	dirty := c.Clean + b.Accrued()
+
+	// bond at horizon on the unchanged term structure
+	atHorizon := b
+	atHorizon.Settlement = horizon
+	c.HorizonClean = atHorizon.PresentValue(ts) - atHorizon.Accrued()
+
+	// coupon income and financing
+	effCoupon := b.EffectiveCoupon(b.Coupon)
+	c.Income = atHorizon.Accrued() - b.Accrued()
+	for _, d := range b.Dates() {
+		if d.After(horizon) {
+			break
+		}
+		c.Income += effCoupon
+	}
+	c.Financing = dirty * repoRate / 100.0 * days(b.Settlement, horizon) / 360.0
+
+	c.ForwardClean = ForwardPrice(b, dirty, horizon, repoRate) - atHorizon.Accrued()
+	c.Carry = c.Clean - c.ForwardClean
+	c.RollDown = c.HorizonClean - c.Clean
+	c.Total = c.Carry + c.RollDown
+
+	return c, nil
+}
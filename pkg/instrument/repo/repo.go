@@ -0,0 +1,192 @@
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const (
+	// Repo sells the collateral and borrows cash (cash taker)
+	Repo int = iota
+	// ReverseRepo buys the collateral and lends cash (cash giver)
+	ReverseRepo
+)
+
+// Agreement implements a repurchase agreement where one counterparty sells a
+// bond as collateral for cash and agrees to buy it back at a later date for
+// the repurchase price. Open repos have no fixed end date and are valued as
+// if terminated at the end of the next day.
+type Agreement struct {
+	// Type is either Repo or ReverseRepo
+	Type int
+	// Settlement is the valuation date
+	Settlement time.Time
+	// Start is the purchase date of the collateral
+	Start time.Time
+	// End is the repurchase date of the collateral (zero for open repos)
+	End time.Time
+	// Collateral is the bond delivered as collateral
+	Collateral bond.Straight
+	// Nominal is the face amount of the collateral
+	Nominal float64
+	// Price is the dirty price of the collateral at the start date (per
+	// 100 nominal)
+	Price float64
+	// Haircut is the discount in percent on the market value of the
+	// collateral that determines the cash amount
+	Haircut float64
+	// Rate is the repo rate in percent (simple, act/360)
+	Rate float64
+}
+
+// agreementJSON is the json representation of a repo; Type is marshalled as
+// "kind" as "type" tags the security
+type agreementJSON struct {
+	Type       int           `json:"kind"`
+	Settlement maturity.Date `json:"settlement"`
+	Start      maturity.Date `json:"start"`
+	End        maturity.Date `json:"end"`
+	Collateral bond.Straight `json:"collateral"`
+	Nominal    float64       `json:"nominal"`
+	Price      float64       `json:"price"`
+	Haircut    float64       `json:"haircut"`
+	Rate       float64       `json:"rate"`
+}
+
+// MarshalJSON implements the json.Marshaler interface with the dates as
+// strings (empty end date for open repos)
+func (a Agreement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(agreementJSON{
+		Type:       a.Type,
+		Settlement: maturity.Date(a.Settlement),
+		Start:      maturity.Date(a.Start),
+		End:        maturity.Date(a.End),
+		Collateral: a.Collateral,
+		Nominal:    a.Nominal,
+		Price:      a.Price,
+		Haircut:    a.Haircut,
+		Rate:       a.Rate,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (a *Agreement) UnmarshalJSON(data []byte) error {
+	var s agreementJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*a = Agreement{
+		Type:       s.Type,
+		Settlement: time.Time(s.Settlement),
+		Start:      time.Time(s.Start),
+		End:        time.Time(s.End),
+		Collateral: s.Collateral,
+		Nominal:    s.Nominal,
+		Price:      s.Price,
+		Haircut:    s.Haircut,
+		Rate:       s.Rate,
+	}
+	return nil
+}
+
+// days returns the actual number of days between two dates
+func days(d1, d2 time.Time) float64 {
+	return math.Round(d2.Sub(d1).Hours() / 24.0)
+}
+
+// Open returns true for an open repo without fixed end date
+func (a *Agreement) Open() bool {
+	return a.End.IsZero()
+}
+
+// Termination returns the repurchase date; open repos are terminated at the
+// next day after the valuation date
+func (a *Agreement) Termination() time.Time {
+	if a.Open() {
+		end := a.Settlement.AddDate(0, 0, 1)
+		if end.Before(a.Start) {
+			end = a.Start.AddDate(0, 0, 1)
+		}
+		return end
+	}
+	return a.End
+}
+
+// Cash returns the cash amount paid at the start date
+func (a *Agreement) Cash() float64 {
+	return a.Nominal / 100.0 * a.Price * (1.0 - a.Haircut/100.0)
+}
+
+// RepurchasePrice returns the cash amount paid back at termination
+func (a *Agreement) RepurchasePrice() float64 {
+	return a.Cash() * (1.0 + a.Rate/100.0*days(a.Start, a.Termination())/360.0)
+}
+
+// Accrued returns the repo interest accrued up to the valuation date
+func (a *Agreement) Accrued() float64 {
+	if !a.Settlement.After(a.Start) {
+		return 0.0
+	}
+	return a.Cash() * a.Rate / 100.0 * days(a.Start, a.Settlement) / 360.0
+}
+
+// sign returns 1.0 for the cash giver and -1.0 for the cash taker
+func (a *Agreement) sign() float64 {
+	if a.Type == ReverseRepo {
+		return 1.0
+	}
+	return -1.0
+}
+
+// PresentValue returns the value of the financing cash flows (the cash
+// lent at the start and the repurchase price at termination); the value is
+// positive for the cash giver (reverse repo)
+func (a *Agreement) PresentValue(ts term.Structure) float64 {
+	pv := a.RepurchasePrice() * ts.Z(maturity.DifferenceInYears(a.Settlement, a.Termination()))
+	if a.Start.After(a.Settlement) {
+		pv -= a.Cash() * ts.Z(maturity.DifferenceInYears(a.Settlement, a.Start))
+	}
+	return a.sign() * pv
+}
+
+// Exposure returns the value of the cash claim minus the value of the
+// collateral at the valuation date from the cash giver's perspective
+// (positive values indicate under-collateralization)
+func (a *Agreement) Exposure(ts term.Structure) float64 {
+	collateral := a.Collateral
+	collateral.Settlement = a.Settlement
+	return a.Cash() + a.Accrued() - a.Nominal/100.0*collateral.PresentValue(ts)
+}
+
+// TermRate returns the simple (act/360) repo rate in percent between the
+// two dates implied by the repo rate term structure
+func TermRate(ts term.Structure, valuation, start, end time.Time) (float64, error) {
+	n := days(start, end)
+	if n <= 0 {
+		return 0.0, fmt.Errorf("end date is not after start date")
+	}
+	z1 := ts.Z(maturity.DifferenceInYears(valuation, start))
+	z2 := ts.Z(maturity.DifferenceInYears(valuation, end))
+	return (z1/z2 - 1.0) * 360.0 / n * 100.0, nil
+}
+
+// ForwardPrice returns the forward dirty price of the bond at the given date
+// from financing the dirty price at the repo rate (simple, act/360) and
+// reinvesting the coupons paid up to that date at the repo rate
+func ForwardPrice(b bond.Straight, dirty float64, date time.Time, repoRate float64) float64 {
+	forward := dirty * (1.0 + repoRate/100.0*days(b.Settlement, date)/360.0)
+	effCoupon := b.EffectiveCoupon(b.Coupon)
+	for _, d := range b.Dates() {
+		if d.After(date) {
+			break
+		}
+		forward -= effCoupon * (1.0 + repoRate/100.0*days(d, date)/360.0)
+	}
+	return forward
+}
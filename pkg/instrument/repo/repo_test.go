@@ -0,0 +1,106 @@
+package repo_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/instrument/repo"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+var (
+	valuation  = time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+	collateral = bond.Straight{
+		Schedule: maturity.Schedule{
+			Settlement: valuation,
+			Maturity:   time.Date(2026, 5, 28, 0, 0, 0, 0, time.UTC),
+			Frequency:  1,
+			Basis:      "30E360",
+		},
+		Coupon:     1.25,
+		Redemption: 100.0,
+	}
+)
+
+func TestAgreement(t *testing.T) {
+	ts := term.Flat{R: -0.70}
+
+	agreement := repo.Agreement{
+		Type:       repo.ReverseRepo,
+		Settlement: valuation,
+		Start:      valuation,
+		End:        valuation.AddDate(0, 3, 0),
+		Collateral: collateral,
+		Nominal:    1e6,
+		Price:      106.0,
+		Haircut:    2.0,
+	}
+
+	cash := 1e6 / 100.0 * 106.0 * 0.98
+	if math.Abs(agreement.Cash()-cash) > 1e-6 {
+		t.Errorf("wrong cash amount; got: %v, expected: %v", agreement.Cash(), cash)
+	}
+
+	// forward-starting repo at the rate implied by the term structure has zero value
+	agreement.Start = valuation.AddDate(0, 0, 2)
+	agreement.End = agreement.Start.AddDate(0, 3, 0)
+	rate, err := repo.TermRate(&ts, valuation, agreement.Start, agreement.End)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agreement.Rate = rate
+	if value := agreement.PresentValue(&ts); math.Abs(value) > 1e-6 {
+		t.Errorf("wrong value of reverse repo; got: %v, expected: %v", value, 0.0)
+	}
+
+	// repo is the opposite position
+	agreement.Type = repo.Repo
+	agreement.Rate = rate + 0.1
+	if value := agreement.PresentValue(&ts); value >= 0.0 {
+		t.Errorf("repo at higher rate should have negative value for cash taker; got: %v", value)
+	}
+
+	// open repo terminates the next day
+	agreement.End = time.Time{}
+	if !agreement.Open() || !agreement.Termination().Equal(agreement.Start.AddDate(0, 0, 1)) {
+		t.Errorf("wrong termination of open repo; got: %v", agreement.Termination())
+	}
+}
+
+func TestCarryAndRollDown(t *testing.T) {
+	// flat term structure: roll-down comes from pull to par only
+	ts := term.Flat{R: 0.5}
+	horizon := valuation.AddDate(0, 6, 0) // includes coupon on 2022-05-28
+
+	c, err := repo.CarryAndRollDown(collateral, 0.0, horizon, -0.70, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// carry is income minus financing (up to reinvestment of coupons)
+	if math.Abs(c.Carry-(c.Income-c.Financing)) > 0.001 {
+		t.Errorf("carry is not income minus financing; got: %v, expected: %v", c.Carry, c.Income-c.Financing)
+	}
+	if math.Abs(c.Total-(c.HorizonClean-c.ForwardClean)) > 1e-9 {
+		t.Errorf("total is not horizon minus forward price; got: %v", c.Total)
+	}
+	if c.Carry <= 0.0 {
+		t.Errorf("positive coupon with negative repo rate should have positive carry; got: %v", c.Carry)
+	}
+
+	// forward price from financing at repo rate
+	dirty := c.Clean + collateral.Accrued()
+	n := horizon.Sub(valuation).Hours() / 24.0
+	m := horizon.Sub(time.Date(2022, 5, 28, 0, 0, 0, 0, time.UTC)).Hours() / 24.0
+	expected := dirty*(1.0-0.007*n/360.0) - 1.25*(1.0-0.007*m/360.0)
+	if got := repo.ForwardPrice(collateral, dirty, horizon, -0.70); math.Abs(got-expected) > 1e-9 {
+		t.Errorf("wrong forward price; got: %v, expected: %v", got, expected)
+	}
+
+	if _, err := repo.CarryAndRollDown(collateral, 0.0, valuation, -0.70, &ts); err == nil {
+		t.Errorf("expected error for horizon at valuation date")
+	}
+}
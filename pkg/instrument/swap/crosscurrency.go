@@ -0,0 +1,151 @@
+package swap
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/forward"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// CrossCurrencySwap implements a cross-currency swap where the cash flows of
+// a domestic leg are exchanged against the cash flows of a foreign leg. The
+// domestic leg is discounted with the domestic term structure and the foreign
+// leg with the foreign term structure (including the cross-currency basis,
+// see term.BasisSpread). The legs usually exchange their notionals.
+type CrossCurrencySwap struct {
+	// Settlement is the valuation date
+	Settlement time.Time
+	// Domestic is the leg paying in domestic currency
+	Domestic Leg
+	// Foreign is the leg paying in foreign currency
+	Foreign Leg
+	// Spot is the spot exchange rate (domestic per foreign)
+	Spot float64
+	// ForeignCurve is the term structure to discount the foreign cash flows
+	ForeignCurve term.Structure
+	// MarkToMarket resets the domestic notional at the start of each period
+	// to the foreign notional converted at the forward exchange rate
+	// (constant notional if false)
+	MarkToMarket bool
+}
+
+// resetNotionals returns the domestic notionals of a mark-to-market swap
+func (s *CrossCurrencySwap) resetNotionals(ts term.Structure) ([]float64, error) {
+	domestic := s.Domestic.Schedule()
+	foreign := s.Foreign.Schedule()
+	periods := domestic.Periods()
+	if len(periods) != len(foreign.Periods()) {
+		return nil, fmt.Errorf("domestic and foreign legs need the same number of periods for notional resets")
+	}
+	notionals := make([]float64, len(periods))
+	for i, p := range periods {
+		// notional has been reset already
+		if !p.Start.After(s.Settlement) {
+			n, err := domestic.NotionalAt(i)
+			if err != nil {
+				return nil, err
+			}
+			notionals[i] = n
+			continue
+		}
+		n, err := foreign.NotionalAt(i)
+		if err != nil {
+			return nil, err
+		}
+		fx, err := forward.Fx(s.Spot, maturity.DifferenceInYears(s.Settlement, p.Start), ts, s.ForeignCurve)
+		if err != nil {
+			return nil, err
+		}
+		notionals[i] = fx * n
+	}
+	return notionals, nil
+}
+
+// DomesticValue returns the value of the domestic leg in domestic currency
+func (s *CrossCurrencySwap) DomesticValue(ts term.Structure) (float64, error) {
+	if !s.MarkToMarket {
+		return legValue(s.Domestic, s.Settlement, ts)
+	}
+
+	notionals, err := s.resetNotionals(ts)
+	if err != nil {
+		return 0.0, err
+	}
+
+	// value a copy of the domestic leg with the notional resets
+	domestic, err := withNotionals(s.Domestic, notionals)
+	if err != nil {
+		return 0.0, err
+	}
+	return legValue(domestic, s.Settlement, ts)
+}
+
+// withNotionals returns a copy of the leg that exchanges the given notionals
+func withNotionals(leg Leg, notionals []float64) (Leg, error) {
+	var schedule *LegSchedule
+	switch l := leg.(type) {
+	case *FixedLeg:
+		c := *l
+		leg, schedule = &c, &c.LegSchedule
+	case *FloatingLeg:
+		c := *l
+		leg, schedule = &c, &c.LegSchedule
+	case *OvernightLeg:
+		c := *l
+		leg, schedule = &c, &c.LegSchedule
+	default:
+		return nil, fmt.Errorf("notional resets not supported for %T", leg)
+	}
+	schedule.Notionals, schedule.NotionalExchange = notionals, true
+	return leg, nil
+}
+
+// ForeignValue returns the value of the foreign leg in foreign currency
+func (s *CrossCurrencySwap) ForeignValue() (float64, error) {
+	return legValue(s.Foreign, s.Settlement, s.ForeignCurve)
+}
+
+// Value returns the value of the swap in domestic currency
+func (s *CrossCurrencySwap) Value(ts term.Structure) (float64, error) {
+	domestic, err := s.DomesticValue(ts)
+	if err != nil {
+		return 0.0, err
+	}
+	foreign, err := s.ForeignValue()
+	if err != nil {
+		return 0.0, err
+	}
+	return domestic + s.Spot*foreign, nil
+}
+
+// ValueIn returns the value of the swap in the currency of either leg
+func (s *CrossCurrencySwap) ValueIn(currency string, ts term.Structure) (float64, error) {
+	value, err := s.Value(ts)
+	if err != nil {
+		return 0.0, err
+	}
+	switch currency {
+	case s.Domestic.Schedule().Currency:
+		return value, nil
+	case s.Foreign.Schedule().Currency:
+		if s.Spot <= 0.0 {
+			return 0.0, fmt.Errorf("spot exchange rate %v is not positive", s.Spot)
+		}
+		return value / s.Spot, nil
+	}
+	return 0.0, fmt.Errorf("currency %s is not paid by any leg of the swap", currency)
+}
+
+// PresentValue returns the value of the swap in domestic currency where ts
+// is the domestic term structure or NaN if the swap cannot be valued (see
+// Value)
+func (s *CrossCurrencySwap) PresentValue(ts term.Structure) float64 {
+	value, err := s.Value(ts)
+	if err != nil {
+		return math.NaN()
+	}
+	return value
+}
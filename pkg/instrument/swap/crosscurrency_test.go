@@ -0,0 +1,96 @@
+package swap_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestCrossCurrencySwap(t *testing.T) {
+	date := time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+	chf := term.Flat{R: -0.75}
+	eur := term.Flat{R: -0.50}
+	spot := 1.05 // CHF per EUR
+
+	newLeg := func(direction int, notional float64, currency string) *swap.FloatingLeg {
+		return &swap.FloatingLeg{
+			LegSchedule: swap.LegSchedule{
+				Direction:        direction,
+				Effective:        date.AddDate(0, 0, 2),
+				Maturity:         date.AddDate(5, 0, 2),
+				Frequency:        4,
+				Basis:            "ACT360",
+				Notional:         notional,
+				NotionalExchange: true,
+				Currency:         currency,
+			},
+		}
+	}
+
+	for _, mtm := range []bool{false, true} {
+		xccy := swap.CrossCurrencySwap{
+			Settlement:   date,
+			Domestic:     newLeg(swap.Receive, spot*1e6, "CHF"),
+			Foreign:      newLeg(swap.Pay, 1e6, "EUR"),
+			Spot:         spot,
+			ForeignCurve: &eur,
+			MarkToMarket: mtm,
+		}
+
+		// floating legs with notional exchange are valued at par
+		value, err := xccy.Value(&chf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(value) > 1e-6 {
+			t.Errorf("mtm %v: wrong value; got: %v, expected: %v", mtm, value, 0.0)
+		}
+
+		// negative cross-currency basis on EUR discounting
+		xccy.ForeignCurve = &term.BasisSpread{
+			Base:       &eur,
+			Maturities: []float64{1.0, 5.0},
+			Spreads:    []float64{-10.0, -20.0},
+		}
+		xccy.Foreign.(*swap.FloatingLeg).Projection = &eur
+
+		value, err = xccy.Value(&chf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value >= 0.0 {
+			t.Errorf("mtm %v: paying EUR with negative basis should have negative value; got: %v", mtm, value)
+		}
+
+		eurValue, err := xccy.ValueIn("EUR", &chf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(eurValue*spot-value) > 1e-6 {
+			t.Errorf("mtm %v: wrong value in EUR; got: %v, expected: %v", mtm, eurValue, value/spot)
+		}
+		if _, err := xccy.ValueIn("USD", &chf); err == nil {
+			t.Errorf("mtm %v: expected error for unknown currency", mtm)
+		}
+
+		// concurrent valuations leave the legs unchanged
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if v, err := xccy.Value(&chf); err != nil || math.Abs(v-value) > 1e-6 {
+					t.Errorf("mtm %v: got %v and error %v, expected %v", mtm, v, err, value)
+				}
+			}()
+		}
+		wg.Wait()
+		if domestic := xccy.Domestic.Schedule(); domestic.Notionals != nil || !domestic.NotionalExchange {
+			t.Errorf("mtm %v: domestic leg modified by valuation", mtm)
+		}
+	}
+}
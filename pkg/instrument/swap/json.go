@@ -0,0 +1,300 @@
+package swap
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// The legs are marshalled to json with a type tag ({"type":"fixed",...}),
+// dates as strings (format: 2006-01-02) and the projection curves tagged with
+// their type (see term.Marshal). The fixings are not marshalled and have to
+// be set after unmarshalling.
+
+// legScheduleJSON is the json representation of a leg schedule
+type legScheduleJSON struct {
+	Direction        int           `json:"direction"`
+	Effective        maturity.Date `json:"effective"`
+	Maturity         maturity.Date `json:"maturity"`
+	Frequency        int           `json:"frequency"`
+	Basis            string        `json:"basis"`
+	Notional         float64       `json:"notional"`
+	Notionals        []float64     `json:"notionals,omitempty"`
+	NotionalExchange bool          `json:"notionalexchange"`
+	Currency         string        `json:"currency"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (l LegSchedule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(legScheduleJSON{
+		Direction:        l.Direction,
+		Effective:        maturity.Date(l.Effective),
+		Maturity:         maturity.Date(l.Maturity),
+		Frequency:        l.Frequency,
+		Basis:            l.Basis,
+		Notional:         l.Notional,
+		Notionals:        l.Notionals,
+		NotionalExchange: l.NotionalExchange,
+		Currency:         l.Currency,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (l *LegSchedule) UnmarshalJSON(data []byte) error {
+	var s legScheduleJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = LegSchedule{
+		Direction:        s.Direction,
+		Effective:        time.Time(s.Effective),
+		Maturity:         time.Time(s.Maturity),
+		Frequency:        s.Frequency,
+		Basis:            s.Basis,
+		Notional:         s.Notional,
+		Notionals:        s.Notionals,
+		NotionalExchange: s.NotionalExchange,
+		Currency:         s.Currency,
+	}
+	return nil
+}
+
+// fixedLegJSON is the json representation of a fixed leg
+type fixedLegJSON struct {
+	Schedule LegSchedule `json:"schedule"`
+	Rate     float64     `json:"rate"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (l FixedLeg) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fixedLegJSON{l.LegSchedule, l.Rate})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (l *FixedLeg) UnmarshalJSON(data []byte) error {
+	var s fixedLegJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = FixedLeg{LegSchedule: s.Schedule, Rate: s.Rate}
+	return nil
+}
+
+// floatingLegJSON is the json representation of a floating leg
+type floatingLegJSON struct {
+	Schedule   LegSchedule `json:"schedule"`
+	Spread     float64     `json:"spread"`
+	Index      string      `json:"index"`
+	Projection term.Tagged `json:"projection"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (l FloatingLeg) MarshalJSON() ([]byte, error) {
+	return json.Marshal(floatingLegJSON{l.LegSchedule, l.Spread, l.Index, term.Tagged{Structure: l.Projection}})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (l *FloatingLeg) UnmarshalJSON(data []byte) error {
+	var s floatingLegJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = FloatingLeg{LegSchedule: s.Schedule, Spread: s.Spread, Index: s.Index, Projection: s.Projection.Structure}
+	return nil
+}
+
+// overnightLegJSON is the json representation of an overnight leg
+type overnightLegJSON struct {
+	Schedule   LegSchedule `json:"schedule"`
+	Spread     float64     `json:"spread"`
+	Overnight  Overnight   `json:"overnight"`
+	Projection term.Tagged `json:"projection"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (l OvernightLeg) MarshalJSON() ([]byte, error) {
+	return json.Marshal(overnightLegJSON{l.LegSchedule, l.Spread, l.Overnight, term.Tagged{Structure: l.Projection}})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (l *OvernightLeg) UnmarshalJSON(data []byte) error {
+	var s overnightLegJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = OvernightLeg{LegSchedule: s.Schedule, Spread: s.Spread, Overnight: s.Overnight, Projection: s.Projection.Structure}
+	return nil
+}
+
+// legTypes maps the type tags to the legs
+var legTypes = map[string]func() Leg{
+	"fixed":     func() Leg { return &FixedLeg{} },
+	"floating":  func() Leg { return &FloatingLeg{} },
+	"overnight": func() Leg { return &OvernightLeg{} },
+}
+
+// TaggedLeg wraps a leg so that it is marshalled to json with its type tag
+type TaggedLeg struct {
+	Leg
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (t TaggedLeg) MarshalJSON() ([]byte, error) {
+	var name string
+	switch t.Leg.(type) {
+	case *FixedLeg:
+		name = "fixed"
+	case *FloatingLeg:
+		name = "floating"
+	case *OvernightLeg:
+		name = "overnight"
+	case nil:
+		return []byte("null"), nil
+	default:
+		return nil, fmt.Errorf("unknown leg %T", t.Leg)
+	}
+	data, err := json.Marshal(t.Leg)
+	if err != nil {
+		return nil, err
+	}
+	// merge the type tag into the object of the leg
+	return append([]byte(fmt.Sprintf(`{"type":%q,`, name)), data[1:]...), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (t *TaggedLeg) UnmarshalJSON(data []byte) error {
+	var tag struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &tag); err != nil {
+		return err
+	}
+	newLeg, ok := legTypes[tag.Type]
+	if !ok {
+		return fmt.Errorf("unknown leg type %q", tag.Type)
+	}
+	leg := newLeg()
+	if err := json.Unmarshal(data, leg); err != nil {
+		return err
+	}
+	t.Leg = leg
+	return nil
+}
+
+// swapJSON is the json representation of a swap
+type swapJSON struct {
+	Settlement maturity.Date `json:"settlement"`
+	Legs       []TaggedLeg   `json:"legs"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (s Swap) MarshalJSON() ([]byte, error) {
+	legs := []TaggedLeg{}
+	for _, leg := range s.Legs {
+		legs = append(legs, TaggedLeg{leg})
+	}
+	return json.Marshal(swapJSON{maturity.Date(s.Settlement), legs})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (s *Swap) UnmarshalJSON(data []byte) error {
+	var v swapJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = Swap{Settlement: time.Time(v.Settlement)}
+	for _, leg := range v.Legs {
+		s.Legs = append(s.Legs, leg.Leg)
+	}
+	return nil
+}
+
+// crossCurrencySwapJSON is the json representation of a cross-currency swap
+type crossCurrencySwapJSON struct {
+	Settlement   maturity.Date `json:"settlement"`
+	Domestic     TaggedLeg     `json:"domestic"`
+	Foreign      TaggedLeg     `json:"foreign"`
+	Spot         float64       `json:"spot"`
+	ForeignCurve term.Tagged   `json:"foreigncurve"`
+	MarkToMarket bool          `json:"marktomarket"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (s CrossCurrencySwap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(crossCurrencySwapJSON{
+		Settlement:   maturity.Date(s.Settlement),
+		Domestic:     TaggedLeg{s.Domestic},
+		Foreign:      TaggedLeg{s.Foreign},
+		Spot:         s.Spot,
+		ForeignCurve: term.Tagged{Structure: s.ForeignCurve},
+		MarkToMarket: s.MarkToMarket,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (s *CrossCurrencySwap) UnmarshalJSON(data []byte) error {
+	var v crossCurrencySwapJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = CrossCurrencySwap{
+		Settlement:   time.Time(v.Settlement),
+		Domestic:     v.Domestic.Leg,
+		Foreign:      v.Foreign.Leg,
+		Spot:         v.Spot,
+		ForeignCurve: v.ForeignCurve.Structure,
+		MarkToMarket: v.MarkToMarket,
+	}
+	return nil
+}
+
+// overnightIndexSwapJSON is the json representation of an overnight-index swap
+type overnightIndexSwapJSON struct {
+	Settlement maturity.Date `json:"settlement"`
+	Effective  maturity.Date `json:"effective"`
+	Maturity   maturity.Date `json:"maturity"`
+	Frequency  int           `json:"frequency"`
+	FixedRate  float64       `json:"fixedrate"`
+	FixedBasis string        `json:"fixedbasis"`
+	Spread     float64       `json:"spread"`
+	Notional   float64       `json:"notional"`
+	Overnight  Overnight     `json:"overnight"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (s OvernightIndexSwap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(overnightIndexSwapJSON{
+		Settlement: maturity.Date(s.Settlement),
+		Effective:  maturity.Date(s.Effective),
+		Maturity:   maturity.Date(s.Maturity),
+		Frequency:  s.Frequency,
+		FixedRate:  s.FixedRate,
+		FixedBasis: s.FixedBasis,
+		Spread:     s.Spread,
+		Notional:   s.Notional,
+		Overnight:  s.Overnight,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (s *OvernightIndexSwap) UnmarshalJSON(data []byte) error {
+	var v overnightIndexSwapJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = OvernightIndexSwap{
+		Settlement: time.Time(v.Settlement),
+		Effective:  time.Time(v.Effective),
+		Maturity:   time.Time(v.Maturity),
+		Frequency:  v.Frequency,
+		FixedRate:  v.FixedRate,
+		FixedBasis: v.FixedBasis,
+		Spread:     v.Spread,
+		Notional:   v.Notional,
+		Overnight:  v.Overnight,
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package swap_test
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestSwapJSON(t *testing.T) {
+	date := time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+	ts := &term.Flat{R: 0.5}
+	schedule := func(direction int) swap.LegSchedule {
+		return swap.LegSchedule{
+			Direction: direction,
+			Effective: date.AddDate(0, 0, 2),
+			Maturity:  date.AddDate(5, 0, 2),
+			Frequency: 2,
+			Notional:  1e6,
+			Currency:  "CHF",
+		}
+	}
+	s := swap.Swap{
+		Settlement: date,
+		Legs: []swap.Leg{
+			&swap.FixedLeg{LegSchedule: schedule(swap.Receive), Rate: 0.75},
+			&swap.FloatingLeg{LegSchedule: schedule(swap.Pay), Spread: 10.0, Index: "LIBOR6M", Projection: &term.Flat{R: 0.6}},
+			&swap.OvernightLeg{LegSchedule: schedule(swap.Pay), Overnight: swap.Overnight{Index: "SARON"}},
+		},
+	}
+	s.Legs[0].Schedule().Notionals = []float64{1e6, 1e6, 1e6, 1e6, 1e6, 8e5, 8e5, 8e5, 8e5, 8e5}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"settlement":"2021-12-03"`, `"type":"fixed"`, `"type":"floating"`, `"type":"overnight"`, `"effective":"2021-12-05"`, `"projection":{"type":"flat"`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("%s not found in %s", key, data)
+		}
+	}
+
+	var parsed swap.Swap
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Settlement.Equal(s.Settlement) || len(parsed.Legs) != len(s.Legs) {
+		t.Fatalf("got %+v", parsed)
+	}
+	for i := range s.Legs {
+		if reflect.TypeOf(parsed.Legs[i]) != reflect.TypeOf(s.Legs[i]) {
+			t.Errorf("leg %d: got %T, expected %T", i, parsed.Legs[i], s.Legs[i])
+		}
+	}
+	expected, err := s.Value(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := parsed.Value(ts); err != nil || math.Abs(value-expected) > 1e-8 {
+		t.Errorf("got value %v and error %v, expected %v", value, err, expected)
+	}
+
+	if err := json.Unmarshal([]byte(`{"settlement":"2021-12-03","legs":[{"type":"cap"}]}`), &parsed); err == nil {
+		t.Errorf("expected error for unknown leg type")
+	}
+}
+
+func TestCrossCurrencySwapJSON(t *testing.T) {
+	date := time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+	eur := &term.Flat{R: -0.50}
+	leg := func(direction int, notional float64, currency string) *swap.FloatingLeg {
+		return &swap.FloatingLeg{LegSchedule: swap.LegSchedule{
+			Direction:        direction,
+			Effective:        date.AddDate(0, 0, 2),
+			Maturity:         date.AddDate(5, 0, 2),
+			Frequency:        4,
+			Basis:            "ACT360",
+			Notional:         notional,
+			NotionalExchange: true,
+			Currency:         currency,
+		}}
+	}
+	xccy := swap.CrossCurrencySwap{
+		Settlement:   date,
+		Domestic:     leg(swap.Receive, 1.05e6, "CHF"),
+		Foreign:      leg(swap.Pay, 1e6, "EUR"),
+		Spot:         1.05,
+		ForeignCurve: &term.BasisSpread{Base: eur, Maturities: []float64{1.0, 5.0}, Spreads: []float64{-10.0, -20.0}},
+		MarkToMarket: true,
+	}
+	data, err := json.Marshal(xccy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed swap.CrossCurrencySwap
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	chf := &term.Flat{R: -0.75}
+	expected, err := xccy.Value(chf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := parsed.Value(chf); err != nil || math.Abs(value-expected) > 1e-6 {
+		t.Errorf("got value %v and error %v, expected %v", value, err, expected)
+	}
+}
+
+func TestOvernightIndexSwapJSON(t *testing.T) {
+	date := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	ois := swap.OvernightIndexSwap{
+		Settlement: date,
+		Effective:  date.AddDate(0, 0, 2),
+		Maturity:   date.AddDate(2, 0, 2),
+		FixedRate:  -0.5,
+		Notional:   1e6,
+		Overnight:  swap.Overnight{Index: "SARON", Convention: swap.Convention{Lockout: 2, ObservationShift: true}},
+	}
+	data, err := json.Marshal(ois)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed swap.OvernightIndexSwap
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, ois) {
+		t.Errorf("got %+v, expected %+v", parsed, ois)
+	}
+}
@@ -0,0 +1,240 @@
+package swap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/fixing"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const (
+	Receive int = iota
+	Pay
+)
+
+// Cashflow is a single payment of a swap leg
+type Cashflow struct {
+	// Date is the payment date
+	Date time.Time
+	// T is the time to payment in years from the valuation date
+	T float64
+	// Amount is the payment amount from the receiver's perspective
+	Amount float64
+}
+
+// Leg is the interface for a swap leg
+type Leg interface {
+	// Cashflows returns the outstanding cash flows of the leg after the
+	// valuation date from the receiver's perspective
+	Cashflows(valuation time.Time, ts term.Structure) ([]Cashflow, error)
+	// Annuity returns the value of receiving a rate of 1 (i.e. 100%) on the
+	// notionals of the outstanding periods
+	Annuity(valuation time.Time, ts term.Structure) (float64, error)
+	// Sign returns 1.0 for receiving and -1.0 for paying legs
+	Sign() float64
+	// Schedule returns the schedule and notionals of the leg
+	Schedule() *LegSchedule
+}
+
+// LegSchedule contains the information shared by all swap legs
+type LegSchedule struct {
+	// Direction is either Receive or Pay
+	Direction int
+	// Effective is the start date of the first interest period
+	Effective time.Time
+	// Maturity is the end date of the last interest period
+	Maturity time.Time
+	// Frequency is the number of payments per year (default: 1)
+	Frequency int
+	// Basis represents the day count convention (default: "" for 30E/360 ISDA)
+	Basis string
+	// Notional is the constant notional amount of the leg
+	Notional float64
+	// Notionals is the notional for each interest period (amortization
+	// schedule); if set, it overrides Notional
+	Notionals []float64
+	// NotionalExchange exchanges the notional at the effective date, the
+	// amortization amounts and the final notional at maturity
+	NotionalExchange bool
+	// Currency is the currency of the leg's cash flows
+	Currency string
+}
+
+// Sign returns 1.0 for receiving and -1.0 for paying legs
+func (l *LegSchedule) Sign() float64 {
+	if l.Direction == Pay {
+		return -1.0
+	}
+	return 1.0
+}
+
+// Schedule returns the schedule and notionals of the leg
+func (l *LegSchedule) Schedule() *LegSchedule {
+	return l
+}
+
+// Periods returns all interest periods of the leg
+func (l *LegSchedule) Periods() []maturity.Period {
+	return maturity.Periods(l.Effective, l.Maturity, l.Frequency)
+}
+
+// NotionalAt returns the notional for the i-th interest period
+func (l *LegSchedule) NotionalAt(i int) (float64, error) {
+	if len(l.Notionals) == 0 {
+		return l.Notional, nil
+	}
+	if i < 0 || i >= len(l.Notionals) {
+		return 0.0, fmt.Errorf("notional schedule has %d entries, but period %d requested", len(l.Notionals), i+1)
+	}
+	return l.Notionals[i], nil
+}
+
+// Annuity returns the value of receiving a rate of 1 (i.e. 100%) on the
+// notionals of the outstanding periods
+func (l *LegSchedule) Annuity(valuation time.Time, ts term.Structure) (float64, error) {
+	annuity := 0.0
+	for i, p := range l.Periods() {
+		if !p.End.After(valuation) {
+			continue
+		}
+		notional, err := l.NotionalAt(i)
+		if err != nil {
+			return 0.0, err
+		}
+		tau := maturity.YearFraction(p.Start, p.End, l.Basis)
+		annuity += notional * tau * ts.Z(maturity.DifferenceInYears(valuation, p.End))
+	}
+	return annuity, nil
+}
+
+// cashflows creates the coupon and notional exchange cash flows of a leg for
+// the annual coupon rates (in percent) returned by rate
+func (l *LegSchedule) cashflows(valuation time.Time, rate func(p maturity.Period) (float64, error)) ([]Cashflow, error) {
+	flows := []Cashflow{}
+	add := func(date time.Time, amount float64) {
+		if date.After(valuation) {
+			flows = append(flows, Cashflow{
+				Date:   date,
+				T:      maturity.DifferenceInYears(valuation, date),
+				Amount: amount,
+			})
+		}
+	}
+
+	periods := l.Periods()
+	for i, p := range periods {
+		notional, err := l.NotionalAt(i)
+		if err != nil {
+			return nil, err
+		}
+
+		// initial exchange of notional
+		if l.NotionalExchange && i == 0 {
+			add(p.Start, -notional)
+		}
+
+		if p.End.After(valuation) {
+			r, err := rate(p)
+			if err != nil {
+				return nil, err
+			}
+			add(p.End, notional*r/100.0*maturity.YearFraction(p.Start, p.End, l.Basis))
+		}
+
+		// amortization and final exchange of notional
+		if l.NotionalExchange {
+			next := 0.0
+			if i+1 < len(periods) {
+				if next, err = l.NotionalAt(i + 1); err != nil {
+					return nil, err
+				}
+			}
+			add(p.End, notional-next)
+		}
+	}
+	return flows, nil
+}
+
+// FixedLeg is a swap leg paying a fixed rate
+type FixedLeg struct {
+	LegSchedule
+	// Rate is the fixed rate in percent
+	Rate float64
+}
+
+// Cashflows returns the outstanding cash flows of the fixed leg
+func (l *FixedLeg) Cashflows(valuation time.Time, ts term.Structure) ([]Cashflow, error) {
+	return l.cashflows(valuation, func(p maturity.Period) (float64, error) {
+		return l.Rate, nil
+	})
+}
+
+// FloatingLeg is a swap leg paying a term rate (e.g. 6M LIBOR) that is fixed
+// at the beginning of each interest period plus a spread
+type FloatingLeg struct {
+	LegSchedule
+	// Spread is added to the floating rate in bps
+	Spread float64
+	// Index is the name of the floating rate index in the fixings store
+	Index string
+	// Fixings contains the historical fixings in percent for the current period
+	Fixings *fixing.Store
+	// Projection is the term structure for projecting forward rates; if it
+	// is nil, the discounting term structure is used
+	Projection term.Structure
+}
+
+// Cashflows returns the outstanding cash flows of the floating leg
+func (l *FloatingLeg) Cashflows(valuation time.Time, ts term.Structure) ([]Cashflow, error) {
+	projection := ts
+	if l.Projection != nil {
+		projection = l.Projection
+	}
+	return l.cashflows(valuation, func(p maturity.Period) (float64, error) {
+		// rate has been fixed already
+		if p.Start.Before(valuation) {
+			if l.Fixings == nil {
+				return 0.0, fmt.Errorf("no fixings available for %s", l.Index)
+			}
+			r, err := l.Fixings.Fixing(l.Index, p.Start)
+			if err != nil {
+				return 0.0, err
+			}
+			return r + l.Spread*0.01, nil
+		}
+		// simple forward rate
+		t1 := maturity.DifferenceInYears(valuation, p.Start)
+		t2 := maturity.DifferenceInYears(valuation, p.End)
+		tau := maturity.YearFraction(p.Start, p.End, l.Basis)
+		return (projection.Z(t1)/projection.Z(t2)-1.0)/tau*100.0 + l.Spread*0.01, nil
+	})
+}
+
+// OvernightLeg is a swap leg paying the daily compounded overnight rate plus a spread
+type OvernightLeg struct {
+	LegSchedule
+	// Spread is added to the compounded overnight rate in bps
+	Spread float64
+	// Overnight is the floating overnight index
+	Overnight Overnight
+	// Projection is the term structure for projecting overnight rates; if
+	// it is nil, the discounting term structure is used
+	Projection term.Structure
+}
+
+// Cashflows returns the outstanding cash flows of the overnight leg
+func (l *OvernightLeg) Cashflows(valuation time.Time, ts term.Structure) ([]Cashflow, error) {
+	projection := ts
+	if l.Projection != nil {
+		projection = l.Projection
+	}
+	return l.cashflows(valuation, func(p maturity.Period) (float64, error) {
+		r, err := l.Overnight.CompoundedRate(p.Start, p.End, valuation, projection)
+		if err != nil {
+			return 0.0, err
+		}
+		return r + l.Spread*0.01, nil
+	})
+}
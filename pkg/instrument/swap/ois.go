@@ -0,0 +1,228 @@
+package swap
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/fixing"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// Convention describes how the overnight fixings are observed when they are
+// compounded in arrears over an interest period
+type Convention struct {
+	// Lookback is the number of business days the observation of each fixing
+	// is shifted backwards
+	Lookback int `json:"lookback"`
+	// Lockout is the number of business days at the end of the period for
+	// which the last observed fixing is repeated
+	Lockout int `json:"lockout"`
+	// ObservationShift weights the fixings with the days of the (shifted)
+	// observation period instead of the days of the interest period
+	ObservationShift bool `json:"observationshift"`
+}
+
+// Overnight describes an overnight index (e.g. SARON, ESTR, SOFR) whose
+// fixings are compounded daily in arrears
+type Overnight struct {
+	// Index is the name of the overnight index in the fixings store
+	Index string `json:"index"`
+	// Fixings contains the historical fixings in percent (not marshalled to
+	// json)
+	Fixings *fixing.Store `json:"-"`
+	// Basis is the day count convention for the daily accrual (default: "ACT360")
+	Basis string `json:"basis"`
+	// Convention contains the lookback, lockout and observation shift
+	Convention Convention `json:"convention"`
+}
+
+func (o *Overnight) basis() string {
+	if o.Basis == "" {
+		return "ACT360"
+	}
+	return o.Basis
+}
+
+// fixing returns the fixing for the observation date; historical fixings
+// before the valuation date are read from the store, while future fixings
+// are projected from the term structure
+func (o *Overnight) fixing(date, next, valuation time.Time, ts term.Structure) (float64, error) {
+	if date.Before(valuation) {
+		if o.Fixings == nil {
+			return 0.0, fmt.Errorf("no fixings available for %s", o.Index)
+		}
+		return o.Fixings.Fixing(o.Index, date)
+	}
+	t1 := maturity.DifferenceInYears(valuation, date)
+	t2 := maturity.DifferenceInYears(valuation, next)
+	tau := maturity.YearFraction(date, next, o.basis())
+	return (ts.Z(t1)/ts.Z(t2) - 1.0) / tau * 100.0, nil
+}
+
+// CompoundedRate returns the annualized rate in percent from compounding
+// the daily fixings in arrears over the interest period from start to end.
+// Fixings before the valuation date are taken from the fixings store, the
+// remaining fixings are projected with the forward rates of the term structure.
+func (o *Overnight) CompoundedRate(start, end, valuation time.Time, ts term.Structure) (float64, error) {
+	lookback := o.Convention.Lookback
+
+	// observation and weighting days
+	var observed, weights []time.Time
+	accrualStart, accrualEnd := start, end
+	if o.Convention.ObservationShift {
+		accrualStart = maturity.AddBusinessDays(start, -lookback)
+		accrualEnd = maturity.AddBusinessDays(end, -lookback)
+		weights = maturity.BusinessDays(accrualStart, accrualEnd)
+		observed = weights
+	} else {
+		weights = maturity.BusinessDays(start, end)
+		observed = make([]time.Time, len(weights))
+		for i, d := range weights {
+			observed[i] = maturity.AddBusinessDays(d, -lookback)
+		}
+	}
+	if len(weights) == 0 {
+		return 0.0, fmt.Errorf("no business days between %s and %s", start.Format(fixing.DateFmt), end.Format(fixing.DateFmt))
+	}
+
+	// collect fixings
+	rates := make([]float64, len(observed))
+	for i, d := range observed {
+		next := maturity.AddBusinessDays(d, 1)
+		r, err := o.fixing(d, next, valuation, ts)
+		if err != nil {
+			return 0.0, err
+		}
+		rates[i] = r
+	}
+
+	// apply lockout
+	if lockout := o.Convention.Lockout; lockout > 0 && lockout < len(rates) {
+		locked := rates[len(rates)-lockout-1]
+		for i := len(rates) - lockout; i < len(rates); i += 1 {
+			rates[i] = locked
+		}
+	}
+
+	// compound daily rates
+	factor := 1.0
+	for i, d := range weights {
+		next := accrualEnd
+		if i+1 < len(weights) {
+			next = weights[i+1]
+		}
+		factor *= 1.0 + rates[i]/100.0*maturity.YearFraction(d, next, o.basis())
+	}
+
+	tau := maturity.YearFraction(accrualStart, accrualEnd, o.basis())
+	return (factor - 1.0) / tau * 100.0, nil
+}
+
+// OvernightIndexSwap implements an overnight-index swap (OIS) where a fixed
+// rate is exchanged against the daily compounded overnight rate (e.g. SARON,
+// ESTR, SOFR). The value is given from the perspective of the counterparty
+// receiving the floating leg and paying the fixed leg.
+type OvernightIndexSwap struct {
+	// Settlement is the valuation date
+	Settlement time.Time
+	// Effective is the start date of the first interest period
+	Effective time.Time
+	// Maturity is the end date of the last interest period
+	Maturity time.Time
+	// Frequency is the number of payments per year on both legs (default: 1)
+	Frequency int
+	// FixedRate is the fixed rate in percent
+	FixedRate float64
+	// FixedBasis is the day count convention of the fixed leg (default: "ACT360")
+	FixedBasis string
+	// Spread is added to the compounded overnight rate in bps
+	Spread float64
+	// Notional is the notional amount of the swap
+	Notional float64
+	// Overnight is the floating overnight index
+	Overnight Overnight
+}
+
+func (s *OvernightIndexSwap) fixedBasis() string {
+	if s.FixedBasis == "" {
+		return "ACT360"
+	}
+	return s.FixedBasis
+}
+
+// periods returns the interest periods that have not been paid yet
+func (s *OvernightIndexSwap) periods() []maturity.Period {
+	periods := []maturity.Period{}
+	for _, p := range maturity.Periods(s.Effective, s.Maturity, s.Frequency) {
+		if p.End.After(s.Settlement) {
+			periods = append(periods, p)
+		}
+	}
+	return periods
+}
+
+// FloatingLeg returns the present value of the floating leg
+func (s *OvernightIndexSwap) FloatingLeg(ts term.Structure) (float64, error) {
+	pv := 0.0
+	for _, p := range s.periods() {
+		r, err := s.Overnight.CompoundedRate(p.Start, p.End, s.Settlement, ts)
+		if err != nil {
+			return 0.0, err
+		}
+		tau := maturity.YearFraction(p.Start, p.End, s.Overnight.basis())
+		t := maturity.DifferenceInYears(s.Settlement, p.End)
+		pv += s.Notional * (r/100.0 + s.Spread/10000.0) * tau * ts.Z(t)
+	}
+	return pv, nil
+}
+
+// Annuity returns the present value of receiving 1 unit of rate (i.e. 100%)
+// on the notional of the fixed leg
+func (s *OvernightIndexSwap) Annuity(ts term.Structure) float64 {
+	annuity := 0.0
+	for _, p := range s.periods() {
+		tau := maturity.YearFraction(p.Start, p.End, s.fixedBasis())
+		t := maturity.DifferenceInYears(s.Settlement, p.End)
+		annuity += s.Notional * tau * ts.Z(t)
+	}
+	return annuity
+}
+
+// FixedLeg returns the present value of the fixed leg
+func (s *OvernightIndexSwap) FixedLeg(ts term.Structure) float64 {
+	return s.FixedRate / 100.0 * s.Annuity(ts)
+}
+
+// ParRate returns the fixed rate in percent that sets the value of the swap to zero
+func (s *OvernightIndexSwap) ParRate(ts term.Structure) (float64, error) {
+	floating, err := s.FloatingLeg(ts)
+	if err != nil {
+		return 0.0, err
+	}
+	annuity := s.Annuity(ts)
+	if annuity == 0.0 {
+		return 0.0, fmt.Errorf("annuity of fixed leg is zero")
+	}
+	return floating / annuity * 100.0, nil
+}
+
+// Value returns the value of the swap (receive floating, pay fixed)
+func (s *OvernightIndexSwap) Value(ts term.Structure) (float64, error) {
+	floating, err := s.FloatingLeg(ts)
+	if err != nil {
+		return 0.0, err
+	}
+	return floating - s.FixedLeg(ts), nil
+}
+
+// PresentValue returns the value of the swap (receive floating, pay fixed)
+// or NaN if fixings are missing (see Value)
+func (s *OvernightIndexSwap) PresentValue(ts term.Structure) float64 {
+	pv, err := s.Value(ts)
+	if err != nil {
+		return math.NaN()
+	}
+	return pv
+}
@@ -0,0 +1,122 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/fixing"
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestOvernight_CompoundedRate(t *testing.T) {
+	valuation := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	// constant fixings of 1.0%
+	store := fixing.NewStore()
+	for d := start.AddDate(0, 0, -10); d.Before(valuation); d = d.AddDate(0, 0, 1) {
+		if maturity.IsBusinessDay(d) {
+			store.Add("SARON", d, 1.0)
+		}
+	}
+
+	// compounding 1.0% per calendar day over the period
+	days := end.Sub(start).Hours() / 24.0
+	factor := 1.0
+	for _, d := range maturity.BusinessDays(start, end) {
+		n := maturity.AddBusinessDays(d, 1)
+		if n.After(end) {
+			n = end
+		}
+		factor *= 1.0 + 0.01*n.Sub(d).Hours()/24.0/360.0
+	}
+	expected := (factor - 1.0) / (days / 360.0) * 100.0
+
+	conventions := []swap.Convention{
+		{},
+		{Lookback: 2},
+		{Lookback: 2, Lockout: 2},
+		{Lookback: 5, ObservationShift: true},
+	}
+
+	ts := term.Flat{R: 0.5}
+	for i, conv := range conventions {
+		o := swap.Overnight{
+			Index:      "SARON",
+			Fixings:    store,
+			Convention: conv,
+		}
+		r, err := o.CompoundedRate(start, end, valuation, &ts)
+		if err != nil {
+			t.Fatalf("test nr %d: %v", i, err)
+		}
+		if math.Abs(r-expected) > 0.0005 {
+			t.Errorf("test nr %d: wrong compounded rate; got: %v, expected: %v", i, r, expected)
+		}
+	}
+
+	// gaps in the fixings are not bridged with stale rates
+	gap := fixing.NewStore()
+	for _, d := range store.Dates("SARON") {
+		if d.Month() != time.October {
+			rate, _ := store.Get("SARON", d)
+			gap.Add("SARON", d, rate)
+		}
+	}
+	o := swap.Overnight{Index: "SARON", Fixings: gap}
+	if _, err := o.CompoundedRate(start, end, valuation, &ts); err == nil {
+		t.Errorf("expected error for missing fixings")
+	}
+}
+
+func TestOvernightIndexSwap(t *testing.T) {
+	ts := term.Flat{R: 1.0}
+	valuation := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	// forward-starting OIS
+	ois := swap.OvernightIndexSwap{
+		Settlement: valuation,
+		Effective:  valuation.AddDate(1, 0, 0),
+		Maturity:   valuation.AddDate(3, 0, 0),
+		Frequency:  1,
+		Notional:   100.0,
+		Overnight: swap.Overnight{
+			Index:      "SARON",
+			Convention: swap.Convention{Lookback: 2, ObservationShift: true},
+		},
+	}
+
+	parRate, err := ois.ParRate(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the compounded overnight rate is the simple forward rate (act/360)
+	z := func(d time.Time) float64 {
+		return ts.Z(maturity.DifferenceInYears(valuation, d))
+	}
+	start := ois.Effective
+	end := ois.Effective.AddDate(1, 0, 0)
+	expected := (z(start)/z(end) - 1.0) / (end.Sub(start).Hours() / 24.0 / 360.0) * 100.0
+	if math.Abs(parRate-expected) > 0.01 {
+		t.Errorf("wrong par rate; got: %v, expected: %v", parRate, expected)
+	}
+
+	ois.FixedRate = parRate
+	if value := ois.PresentValue(&ts); math.Abs(value) > 1e-6 {
+		t.Errorf("OIS at par rate should have zero value; got: %v", value)
+	}
+
+	// seasoned OIS without fixings returns an error
+	ois.Effective = valuation.AddDate(0, -3, 0)
+	if _, err := ois.FloatingLeg(&ts); err == nil {
+		t.Errorf("expected error for missing fixings")
+	}
+	if _, err := ois.Value(&ts); err == nil || !math.IsNaN(ois.PresentValue(&ts)) {
+		t.Errorf("expected error and NaN for missing fixings")
+	}
+}
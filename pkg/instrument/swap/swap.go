@@ -1,6 +1,10 @@
 package swap
 
 import (
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/konimarti/fixedincome/pkg/instrument/bond"
 	"github.com/konimarti/fixedincome/pkg/term"
 )
@@ -13,12 +17,153 @@ import (
 // linked to a floating rate index.
 type InterestRateSwap struct {
 	// Floating rate bond (long position)
-	Floating bond.Floating
+	Floating bond.Floating `json:"floating"`
 	// Fixed rate bond (short position) with swap rate as coupon
-	Fixed bond.Straight
+	Fixed bond.Straight `json:"fixed"`
 }
 
 // PresentValue returns the value of the forward contract
 func (s *InterestRateSwap) PresentValue(ts term.Structure) float64 {
 	return s.Floating.PresentValue(ts) - s.Fixed.PresentValue(ts)
 }
+
+// Swap implements a general swap contract between two (or more) legs, e.g.
+// fixed-for-floating, basis, amortizing, forward-starting and off-market
+// swaps. All legs must pay in the same currency (see Currency) and are
+// discounted with the same term structure.
+type Swap struct {
+	// Settlement is the valuation date
+	Settlement time.Time
+	// Legs contains the receiving and paying legs of the swap
+	Legs []Leg
+}
+
+// LegValue returns the present value of the i-th leg including its sign
+// (positive for receiving, negative for paying legs)
+func (s *Swap) LegValue(i int, ts term.Structure) (float64, error) {
+	if i < 0 || i >= len(s.Legs) {
+		return 0.0, fmt.Errorf("swap has no leg %d", i)
+	}
+	return legValue(s.Legs[i], s.Settlement, ts)
+}
+
+// legValue returns the present value of the leg including its sign
+func legValue(leg Leg, valuation time.Time, ts term.Structure) (float64, error) {
+	flows, err := leg.Cashflows(valuation, ts)
+	if err != nil {
+		return 0.0, err
+	}
+	pv := 0.0
+	for _, cf := range flows {
+		pv += cf.Amount * ts.Z(cf.T)
+	}
+	return leg.Sign() * pv, nil
+}
+
+// Currency returns the currency of the legs; it returns an error if the legs
+// pay in different currencies (legs without currency are ignored)
+func (s *Swap) Currency() (string, error) {
+	currency := ""
+	for i, leg := range s.Legs {
+		c := leg.Schedule().Currency
+		if c == "" {
+			continue
+		}
+		if currency != "" && c != currency {
+			return "", fmt.Errorf("leg %d pays in %s instead of %s", i, c, currency)
+		}
+		currency = c
+	}
+	return currency, nil
+}
+
+// Value returns the present value of the swap; it returns an error if the
+// legs pay in different currencies
+func (s *Swap) Value(ts term.Structure) (float64, error) {
+	if _, err := s.Currency(); err != nil {
+		return 0.0, err
+	}
+	pv := 0.0
+	for i := range s.Legs {
+		value, err := s.LegValue(i, ts)
+		if err != nil {
+			return 0.0, err
+		}
+		pv += value
+	}
+	return pv, nil
+}
+
+// PresentValue returns the present value of the swap or NaN if the swap
+// cannot be valued (see Value)
+func (s *Swap) PresentValue(ts term.Structure) float64 {
+	pv, err := s.Value(ts)
+	if err != nil {
+		return math.NaN()
+	}
+	return pv
+}
+
+// Annuity returns the annuity of the i-th leg
+func (s *Swap) Annuity(i int, ts term.Structure) (float64, error) {
+	if i < 0 || i >= len(s.Legs) {
+		return 0.0, fmt.Errorf("swap has no leg %d", i)
+	}
+	return s.Legs[i].Annuity(s.Settlement, ts)
+}
+
+// breakEven returns the change of the coupon rate (as a decimal) on the i-th
+// leg that sets the value of the swap to zero
+func (s *Swap) breakEven(i int, ts term.Structure) (float64, error) {
+	pv, err := s.Value(ts)
+	if err != nil {
+		return 0.0, err
+	}
+	annuity, err := s.Annuity(i, ts)
+	if err != nil {
+		return 0.0, err
+	}
+	if annuity == 0.0 {
+		return 0.0, fmt.Errorf("annuity of leg %d is zero", i)
+	}
+	return -pv / (s.Legs[i].Sign() * annuity), nil
+}
+
+// ParRate returns the fixed rate in percent of the i-th leg that sets the
+// value of the swap to zero
+func (s *Swap) ParRate(i int, ts term.Structure) (float64, error) {
+	if i < 0 || i >= len(s.Legs) {
+		return 0.0, fmt.Errorf("swap has no leg %d", i)
+	}
+	fixed, ok := s.Legs[i].(*FixedLeg)
+	if !ok {
+		return 0.0, fmt.Errorf("leg %d is not a fixed leg", i)
+	}
+	x, err := s.breakEven(i, ts)
+	if err != nil {
+		return 0.0, err
+	}
+	return fixed.Rate + x*100.0, nil
+}
+
+// ParSpread returns the spread in bps on the floating rate of the i-th leg
+// that sets the value of the swap to zero
+func (s *Swap) ParSpread(i int, ts term.Structure) (float64, error) {
+	if i < 0 || i >= len(s.Legs) {
+		return 0.0, fmt.Errorf("swap has no leg %d", i)
+	}
+	var spread float64
+	switch leg := s.Legs[i].(type) {
+	case *FloatingLeg:
+		spread = leg.Spread
+	case *OvernightLeg:
+		spread = leg.Spread
+	default:
+		return 0.0, fmt.Errorf("leg %d is not a floating leg", i)
+	}
+	x, err := s.breakEven(i, ts)
+	if err != nil {
+		return 0.0, err
+	}
+	return spread + x*10000.0, nil
+}
@@ -72,3 +72,139 @@ func TestInterestRateSwap(t *testing.T) {
 	}
 
 }
+
+func TestSwap(t *testing.T) {
+	ts := term.Flat{R: 1.5}
+	date := time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+
+	// forward-starting fixed-for-floating swap
+	fixedLeg := &swap.FixedLeg{
+		LegSchedule: swap.LegSchedule{
+			Direction: swap.Pay,
+			Effective: date.AddDate(1, 0, 0),
+			Maturity:  date.AddDate(6, 0, 0),
+			Frequency: 1,
+			Basis:     "30E360",
+			Notional:  100.0,
+		},
+		Rate: 1.0,
+	}
+	floatingLeg := &swap.FloatingLeg{
+		LegSchedule: swap.LegSchedule{
+			Direction: swap.Receive,
+			Effective: date.AddDate(1, 0, 0),
+			Maturity:  date.AddDate(6, 0, 0),
+			Frequency: 2,
+			Basis:     "ACT360",
+			Notional:  100.0,
+		},
+	}
+	irs := swap.Swap{
+		Settlement: date,
+		Legs:       []swap.Leg{floatingLeg, fixedLeg},
+	}
+
+	parRate, err := irs.ParRate(1, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedLeg.Rate = parRate
+	if value := irs.PresentValue(&ts); math.Abs(value) > 1e-8 {
+		t.Errorf("swap at par rate should have zero value; got: %v", value)
+	}
+
+	// par rate of fixed leg: (Z(T0) - Z(Tn)) / sum(tau_i * Z(T_i))
+	annuity, err := irs.Annuity(1, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := func(d time.Time) float64 { return ts.Z(maturity.DifferenceInYears(date, d)) }
+	expected := (z(fixedLeg.Effective) - z(fixedLeg.Maturity)) * 100.0 / annuity * 100.0
+	if math.Abs(parRate-expected) > 1e-6 {
+		t.Errorf("wrong par rate; got: %v, expected: %v", parRate, expected)
+	}
+
+	// off-market swap: value is the annuity times the rate difference
+	fixedLeg.Rate = parRate - 0.5
+	if value := irs.PresentValue(&ts); math.Abs(value-0.005*annuity) > 1e-8 {
+		t.Errorf("wrong value of off-market swap; got: %v, expected: %v", value, 0.005*annuity)
+	}
+
+	// basis swap with single curve has zero par spread
+	basis := swap.Swap{
+		Settlement: date,
+		Legs: []swap.Leg{
+			floatingLeg,
+			&swap.FloatingLeg{
+				LegSchedule: swap.LegSchedule{
+					Direction: swap.Pay,
+					Effective: date.AddDate(1, 0, 0),
+					Maturity:  date.AddDate(6, 0, 0),
+					Frequency: 4,
+					Basis:     "ACT360",
+					Notional:  100.0,
+				},
+			},
+		},
+	}
+	spread, err := basis.ParSpread(0, &ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(spread) > 1e-6 {
+		t.Errorf("wrong par spread of basis swap; got: %v, expected: %v", spread, 0.0)
+	}
+	if _, err := basis.ParRate(0, &ts); err == nil {
+		t.Errorf("expected error for par rate of floating leg")
+	}
+
+	// legs in different currencies
+	fixedLeg.Currency, floatingLeg.Currency = "EUR", "EUR"
+	if currency, err := irs.Currency(); err != nil || currency != "EUR" {
+		t.Errorf("got currency %s and error %v", currency, err)
+	}
+	floatingLeg.Currency = "USD"
+	if _, err := irs.Value(&ts); err == nil {
+		t.Errorf("expected error for legs in EUR and USD")
+	}
+	if _, err := irs.ParRate(1, &ts); err == nil {
+		t.Errorf("expected error for par rate of legs in EUR and USD")
+	}
+}
+
+func TestSwap_Amortizing(t *testing.T) {
+	ts := term.Flat{R: 2.0}
+	date := time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC)
+
+	// floating leg with exchange of amortizing notionals is valued at par
+	leg := &swap.FloatingLeg{
+		LegSchedule: swap.LegSchedule{
+			Effective:        date,
+			Maturity:         date.AddDate(4, 0, 0),
+			Frequency:        1,
+			Basis:            "ACT360",
+			Notionals:        []float64{100.0, 75.0, 50.0, 25.0},
+			NotionalExchange: true,
+		},
+	}
+	amortizing := swap.Swap{
+		Settlement: date.AddDate(0, 0, -1),
+		Legs:       []swap.Leg{leg},
+	}
+	value, err := amortizing.Value(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(value) > 1e-8 {
+		t.Errorf("amortizing floating leg should be valued at par; got: %v", value)
+	}
+
+	// wrong length of notional schedule
+	leg.Notionals = leg.Notionals[:2]
+	if _, err := amortizing.Value(&ts); err == nil {
+		t.Errorf("expected error for incomplete notional schedule")
+	}
+	if _, err := amortizing.Annuity(0, &ts); err == nil {
+		t.Errorf("expected error for incomplete notional schedule")
+	}
+}
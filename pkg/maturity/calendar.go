@@ -0,0 +1,104 @@
+package maturity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konimarti/daycount"
+)
+
+// Period represents an accrual period from Start to End
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Periods returns the accrual periods between the effective date and the
+// maturity date with the given payment frequency per year. The periods are
+// generated backwards from the maturity date (short stub at the front).
+func Periods(effective, maturity time.Time, frequency int) []Period {
+	if frequency <= 0 {
+		frequency = 1
+	}
+	if frequency > 12 {
+		panic("more than 12 compounding periods not implemented yet")
+	}
+	step := 12 / frequency
+
+	dates := []time.Time{}
+	for current := maturity; current.After(effective); current = current.AddDate(0, -step, 0) {
+		dates = append([]time.Time{current}, dates...)
+	}
+	if len(dates) == 0 {
+		return []Period{}
+	}
+
+	periods := make([]Period, len(dates))
+	start := effective
+	for i, end := range dates {
+		periods[i] = Period{Start: start, End: end}
+		start = end
+	}
+	return periods
+}
+
+// YearFraction returns the year fraction between two dates for the given day
+// count convention (default: "" for 30E/360 ISDA)
+func YearFraction(start, end time.Time, basis string) float64 {
+	frac, err := daycount.Fraction(start, end, start.AddDate(1, 0, 0), basis)
+	if err != nil {
+		panic(err)
+	}
+	return frac
+}
+
+// CheckBasis returns an error if the day count convention is not implemented
+// (default: "" for 30E/360 ISDA)
+func CheckBasis(basis string) error {
+	if basis == "" {
+		return nil
+	}
+	implemented := daycount.Implemented()
+	for _, b := range implemented {
+		if b == basis {
+			return nil
+		}
+	}
+	sort.Strings(implemented)
+	return fmt.Errorf("day count convention %s not implemented (available: %s)", basis, strings.Join(implemented, ", "))
+}
+
+// IsBusinessDay returns true if the date is not on a weekend
+func IsBusinessDay(date time.Time) bool {
+	wd := date.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// AddBusinessDays moves the date by n business days (weekends are skipped);
+// negative values of n move the date backwards
+func AddBusinessDays(date time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if IsBusinessDay(date) {
+			n -= 1
+		}
+	}
+	return date
+}
+
+// BusinessDays returns all business days in the interval [start, end)
+func BusinessDays(start, end time.Time) []time.Time {
+	days := []time.Time{}
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if IsBusinessDay(d) {
+			days = append(days, d)
+		}
+	}
+	return days
+}
@@ -0,0 +1,77 @@
+package maturity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const DateFmt = "2006-01-02"
+
+// Date is a date marshalled to json as string (format: 2006-01-02); the zero
+// time is marshalled as empty string
+type Date time.Time
+
+// MarshalJSON implements the json.Marshaler interface
+func (d Date) MarshalJSON() ([]byte, error) {
+	t := time.Time(d)
+	if t.IsZero() {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(t.Format(DateFmt))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("date: %v", err)
+	}
+	if s == "" {
+		*d = Date{}
+		return nil
+	}
+	t, err := time.Parse(DateFmt, s)
+	if err != nil {
+		return err
+	}
+	*d = Date(t)
+	return nil
+}
+
+// scheduleJSON is the json representation of a schedule
+type scheduleJSON struct {
+	Settlement  Date   `json:"settlement"`
+	Maturity    Date   `json:"maturity"`
+	Frequency   int    `json:"frequency"`
+	Basis       string `json:"basis"`
+	FirstCoupon Date   `json:"firstcoupon"`
+}
+
+// MarshalJSON implements the json.Marshaler interface with the dates as
+// strings
+func (m Schedule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(scheduleJSON{
+		Settlement:  Date(m.Settlement),
+		Maturity:    Date(m.Maturity),
+		Frequency:   m.Frequency,
+		Basis:       m.Basis,
+		FirstCoupon: Date(m.FirstCoupon),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (m *Schedule) UnmarshalJSON(data []byte) error {
+	var s scheduleJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*m = Schedule{
+		Settlement:  time.Time(s.Settlement),
+		Maturity:    time.Time(s.Maturity),
+		Frequency:   s.Frequency,
+		Basis:       s.Basis,
+		FirstCoupon: time.Time(s.FirstCoupon),
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package maturity_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/maturity"
+)
+
+func TestScheduleJSON(t *testing.T) {
+	s := maturity.Schedule{
+		Settlement:  time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+		Maturity:    time.Date(2026, 5, 28, 0, 0, 0, 0, time.UTC),
+		Frequency:   2,
+		Basis:       "ACT365F",
+		FirstCoupon: time.Date(2021, 11, 28, 0, 0, 0, 0, time.UTC),
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"settlement":"2021-04-01","maturity":"2026-05-28","frequency":2,"basis":"ACT365F","firstcoupon":"2021-11-28"}`
+	if string(data) != expected {
+		t.Errorf("got %s, expected %s", data, expected)
+	}
+
+	var parsed maturity.Schedule
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed != s {
+		t.Errorf("got %+v, expected %+v", parsed, s)
+	}
+
+	// without first coupon
+	if err := json.Unmarshal([]byte(`{"settlement":"2021-04-01","maturity":"2026-05-28","firstcoupon":""}`), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.FirstCoupon.IsZero() || parsed.Frequency != 0 {
+		t.Errorf("got %+v", parsed)
+	}
+
+	for _, invalid := range []string{`{"maturity":"28.05.2026"}`, `{"maturity":20260528}`} {
+		if err := json.Unmarshal([]byte(invalid), &parsed); err == nil {
+			t.Errorf("%s: expected error", invalid)
+		}
+	}
+}
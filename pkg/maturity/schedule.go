@@ -17,6 +17,10 @@ type Schedule struct {
 	Frequency int
 	// Basis represents the day count convention (default: "" for 30E/360 ISDA)
 	Basis string
+	// FirstCoupon is the date of the first coupon payment (optional); there are
+	// no coupon payments before this date except at the maturity date
+	// (irregular first coupon amounts are not taken into account)
+	FirstCoupon time.Time
 }
 
 //Compounding returns the annual compounding frequency
@@ -46,6 +50,9 @@ func (m *Schedule) M() []float64 {
 	// walk back from maturity date to quote date
 	quote := m.Settlement
 	for current := m.Maturity; current.Sub(quote) > 0; current = current.AddDate(0, -step, 0) {
+		if current.Before(m.FirstCoupon) && !current.Equal(m.Maturity) {
+			break
+		}
 		frac, err := daycount.Fraction(quote, current, quote.AddDate(1, 0, 0), m.Basis)
 		if err != nil {
 			panic(err)
@@ -56,6 +63,26 @@ func (m *Schedule) M() []float64 {
 	return maturities
 }
 
+//Dates returns the dates of the cash flows after the settlement date in increasing order
+func (m *Schedule) Dates() []time.Time {
+	dates := []time.Time{}
+
+	if m.Compounding() > 12 {
+		panic("more than 12 compounding periods not implemented yet")
+	}
+	step := 12 / m.Compounding()
+
+	// walk back from maturity date to quote date
+	for current := m.Maturity; current.Sub(m.Settlement) > 0; current = current.AddDate(0, -step, 0) {
+		if current.Before(m.FirstCoupon) && !current.Equal(m.Maturity) {
+			break
+		}
+		dates = append([]time.Time{current}, dates...)
+	}
+
+	return dates
+}
+
 //Last returns the latest maturity value in years (i.e. the years to maturity)
 func (m *Schedule) Last() float64 {
 	t := m.M()
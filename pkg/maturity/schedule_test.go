@@ -185,3 +185,58 @@ func TestSchedule_Last(t *testing.T) {
 
 	}
 }
+
+func TestSchedule_Dates(t *testing.T) {
+	m := maturity.Schedule{
+		Settlement: time.Date(2021, 4, 16, 0, 0, 0, 0, time.UTC),
+		Maturity:   time.Date(2023, 10, 16, 0, 0, 0, 0, time.UTC),
+		Frequency:  2,
+	}
+	expected := []time.Time{
+		time.Date(2021, 10, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 4, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 10, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 4, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 10, 16, 0, 0, 0, 0, time.UTC),
+	}
+	dates := m.Dates()
+	if len(dates) != len(expected) {
+		t.Fatalf("wrong number of dates; got: %d, expected: %d", len(dates), len(expected))
+	}
+	for i, d := range dates {
+		if !d.Equal(expected[i]) {
+			t.Errorf("wrong date nr %d; got: %v, expected: %v", i, d, expected[i])
+		}
+	}
+}
+
+func TestSchedule_FirstCoupon(t *testing.T) {
+	m := maturity.Schedule{
+		Settlement:  time.Date(2021, 4, 16, 0, 0, 0, 0, time.UTC),
+		Maturity:    time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC),
+		Frequency:   1,
+		FirstCoupon: time.Date(2022, 10, 16, 0, 0, 0, 0, time.UTC),
+	}
+	expected := []float64{4.5, 3.5, 2.5, 1.5}
+	got := m.M()
+	if len(got) != len(expected) {
+		t.Fatalf("wrong number of maturities; got: %v, expected: %v", got, expected)
+	}
+	for i, v := range got {
+		if math.Abs(v-expected[i]) > 1e-9 {
+			t.Errorf("wrong maturity nr %d; got: %v, expected: %v", i, v, expected[i])
+		}
+	}
+	if dates := m.Dates(); len(dates) != 4 || !dates[0].Equal(m.FirstCoupon) {
+		t.Errorf("wrong dates: %v", dates)
+	}
+
+	// maturity date is kept for a first coupon after maturity
+	m.FirstCoupon = time.Date(2030, 10, 16, 0, 0, 0, 0, time.UTC)
+	if got := m.M(); len(got) != 1 || math.Abs(got[0]-4.5) > 1e-9 {
+		t.Errorf("wrong maturities: %v", got)
+	}
+	if dates := m.Dates(); len(dates) != 1 || !dates[0].Equal(m.Maturity) {
+		t.Errorf("wrong dates: %v", dates)
+	}
+}
@@ -0,0 +1,437 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats
+const (
+	Table int = iota
+	CSV
+	JSON
+)
+
+// Formats maps the names of the output formats
+var Formats = map[string]int{
+	"table": Table,
+	"csv":   CSV,
+	"json":  JSON,
+}
+
+// Usage is the help text of the output flag of the commands
+const Usage = "output format: table, csv or json"
+
+// FloatFmt is the format of floats in tables without a format tag
+var FloatFmt = "%.4f"
+
+// Parse returns the output format by name
+func Parse(name string) (int, error) {
+	format, ok := Formats[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Table, fmt.Errorf("unknown output format %s", name)
+	}
+	return format, nil
+}
+
+// Section is a named part of the results of a command
+type Section struct {
+	Name string
+	// Records is a struct or a slice of structs
+	Records interface{}
+}
+
+// Sections are the results of commands with several parts. They are written
+// as a json object with the names of the sections as keys, as tables with a
+// title or as CSV blocks starting with a comment line "# name" and separated
+// by an empty line.
+type Sections []Section
+
+// MarshalJSON encodes the sections as an object in the order of the sections
+func (s Sections) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, section := range s {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(section.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(finite(normalize(section.Records)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", section.Name, err)
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// Write writes the records (a struct, a slice of structs or Sections) in the
+// output format. The columns are the exported fields named by their json keys
+// (fields with the key "-" are skipped) and the elements of slices are
+// separated by spaces; tables format the values with the format tag of the
+// field (e.g. `format:"%.2f"`). Floats which are not finite are written as
+// null in json and as NaN, +Inf or -Inf in CSV files and tables.
+func Write(w io.Writer, format int, records interface{}) error {
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(finite(normalize(records)))
+	case CSV:
+		if sections, ok := records.(Sections); ok {
+			for i, section := range sections {
+				if i > 0 {
+					fmt.Fprintln(w, "")
+				}
+				fmt.Fprintf(w, "# %s\n", section.Name)
+				if err := writeCSV(w, section.Records); err != nil {
+					return fmt.Errorf("%s: %v", section.Name, err)
+				}
+			}
+			return nil
+		}
+		return writeCSV(w, records)
+	case Table:
+		if sections, ok := records.(Sections); ok {
+			for i, section := range sections {
+				if i > 0 {
+					fmt.Fprintln(w, "")
+				}
+				fmt.Fprintf(w, "%s:\n", section.Name)
+				if err := writeTable(w, section.Records); err != nil {
+					return fmt.Errorf("%s: %v", section.Name, err)
+				}
+			}
+			return nil
+		}
+		return writeTable(w, records)
+	}
+	return fmt.Errorf("unknown output format %d", format)
+}
+
+// column is a field of the records
+type column struct {
+	name   string
+	index  []int
+	format string
+}
+
+// columns returns the columns of the struct type; the fields of embedded
+// structs are included
+func columns(t reflect.Type) []column {
+	cols := []column{}
+	for i := 0; i < t.NumField(); i += 1 {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for _, c := range columns(field.Type) {
+				c.index = append([]int{i}, c.index...)
+				cols = append(cols, c)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		cols = append(cols, column{name: name, index: []int{i}, format: field.Tag.Get("format")})
+	}
+	return cols
+}
+
+// rows returns the columns and the values of the records
+func rows(records interface{}) ([]column, []reflect.Value, bool, error) {
+	v := reflect.ValueOf(records)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return columns(v.Type()), []reflect.Value{v}, false, nil
+	case reflect.Slice, reflect.Array:
+		t := v.Type().Elem()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, nil, false, fmt.Errorf("records of type %v are not structs", t)
+		}
+		values := []reflect.Value{}
+		for i := 0; i < v.Len(); i += 1 {
+			values = append(values, reflect.Indirect(v.Index(i)))
+		}
+		return columns(t), values, true, nil
+	}
+	return nil, nil, false, fmt.Errorf("records of type %v are not structs", v.Type())
+}
+
+// normalize replaces nil slices by empty slices to encode them as []
+func normalize(records interface{}) interface{} {
+	v := reflect.ValueOf(records)
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return records
+}
+
+// member is a key and value of a json object
+type member struct {
+	key   string
+	value interface{}
+}
+
+// object is a json object with the keys in the order of the struct fields
+type object []member
+
+// MarshalJSON encodes the object with the keys in order
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, kv := range o {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(kv.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(kv.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", kv.key, err)
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+var marshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// finite returns the records for the json encoder with floats which are not
+// finite (NaN, +Inf, -Inf) replaced by null
+func finite(records interface{}) interface{} {
+	if records == nil {
+		return nil
+	}
+	return finiteValue(reflect.ValueOf(records))
+}
+
+func finiteValue(v reflect.Value) interface{} {
+	if v.Type().Implements(marshaler) {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil
+		}
+		return v.Interface()
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return finiteValue(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			values[i] = finiteValue(v.Index(i))
+		}
+		return values
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		values := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			values[iter.Key().String()] = finiteValue(iter.Value())
+		}
+		return values
+	case reflect.Struct:
+		return finiteStruct(v)
+	}
+	return v.Interface()
+}
+
+// finiteStruct returns the exported fields of the struct by their json keys;
+// the fields of embedded structs are included
+func finiteStruct(v reflect.Value) object {
+	obj := object{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i += 1 {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			obj = append(obj, finiteStruct(v.Field(i))...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		value := v.Field(i)
+		if omitEmpty(tag[1:]) && isEmpty(value) {
+			continue
+		}
+		obj = append(obj, member{name, finiteValue(value)})
+	}
+	return obj
+}
+
+// omitEmpty returns true for the json tag option omitempty
+func omitEmpty(options []string) bool {
+	for _, option := range options {
+		if option == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty returns true for the values omitted by the json encoder with the
+// omitempty option
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0.0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// text returns the value as text; floats are formatted with the format or,
+// if empty, with the shortest exact representation, and the elements of
+// slices are separated by spaces
+func text(v reflect.Value, format string) string {
+	if k := v.Kind(); k == reflect.Slice || k == reflect.Array {
+		return join(v, func(e reflect.Value) string { return text(e, format) })
+	}
+	if format != "" {
+		return fmt.Sprintf(format, v.Interface())
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.String:
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// join returns the space separated texts of the elements
+func join(v reflect.Value, text func(reflect.Value) string) string {
+	parts := []string{}
+	for i := 0; i < v.Len(); i += 1 {
+		parts = append(parts, text(v.Index(i)))
+	}
+	return strings.Join(parts, " ")
+}
+
+func writeCSV(w io.Writer, records interface{}) error {
+	cols, values, _, err := rows(records)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	header := []string{}
+	for _, c := range cols {
+		header = append(header, c.name)
+	}
+	cw.Write(header)
+	for _, v := range values {
+		record := []string{}
+		for _, c := range cols {
+			record = append(record, text(v.FieldByIndex(c.index), ""))
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// cell returns the value formatted for a table
+func cell(v reflect.Value, c column) string {
+	if c.format != "" {
+		return text(v, c.format)
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf(FloatFmt, v.Float())
+	case reflect.Slice, reflect.Array:
+		return join(v, func(e reflect.Value) string { return cell(e, c) })
+	}
+	return text(v, "")
+}
+
+// writeTable writes a slice of records as aligned columns and a single record
+// as lines of names and values
+func writeTable(w io.Writer, records interface{}) error {
+	cols, values, list, err := rows(records)
+	if err != nil {
+		return err
+	}
+	if !list {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, c := range cols {
+			fmt.Fprintf(tw, "%s\t%s\n", c.name, cell(values[0].FieldByIndex(c.index), c))
+		}
+		return tw.Flush()
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, c := range cols {
+		fmt.Fprintf(tw, "%s\t", c.name)
+	}
+	fmt.Fprintln(tw, "")
+	for _, v := range values {
+		for _, c := range cols {
+			fmt.Fprintf(tw, "%s\t", cell(v.FieldByIndex(c.index), c))
+		}
+		fmt.Fprintln(tw, "")
+	}
+	return tw.Flush()
+}
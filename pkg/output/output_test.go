@@ -0,0 +1,202 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/output"
+)
+
+type key struct {
+	ID string `json:"id"`
+}
+
+type record struct {
+	key
+	Rate    float64 `json:"rate" format:"%.2f"`
+	Days    int     `json:"days"`
+	Ignored string  `json:"-"`
+	Quoted  bool
+}
+
+var records = []record{
+	{key{"A"}, 1.2345, 30, "x", true},
+	{key{"B"}, -0.5, 360, "y", false},
+}
+
+func TestParse(t *testing.T) {
+	for name, expected := range output.Formats {
+		format, err := output.Parse(strings.ToUpper(name))
+		if err != nil || format != expected {
+			t.Errorf("got format %d (%v) for %s, expected %d", format, err, name, expected)
+		}
+	}
+	if _, err := output.Parse("xml"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
+
+func TestWrite_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := output.Write(&buf, output.CSV, records); err != nil {
+		t.Fatal(err)
+	}
+	expected := "id,rate,days,Quoted\nA,1.2345,30,true\nB,-0.5,360,false\n"
+	if buf.String() != expected {
+		t.Errorf("got %q, expected %q", buf.String(), expected)
+	}
+
+	// single record
+	buf.Reset()
+	if err := output.Write(&buf, output.CSV, records[0]); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "id,rate,days,Quoted\nA,1.2345,30,true\n"; buf.String() != expected {
+		t.Errorf("got %q, expected %q", buf.String(), expected)
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := output.Write(&buf, output.JSON, records); err != nil {
+		t.Fatal(err)
+	}
+	decoded := []map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[1]["id"] != "B" || decoded[1]["rate"] != -0.5 {
+		t.Errorf("got %v", decoded)
+	}
+
+	// empty list
+	buf.Reset()
+	var empty []record
+	if err := output.Write(&buf, output.JSON, empty); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("got %q for empty list", buf.String())
+	}
+}
+
+func TestWrite_Table(t *testing.T) {
+	var buf bytes.Buffer
+	if err := output.Write(&buf, output.Table, records); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "1.23") || strings.Contains(lines[1], "1.2345") {
+		t.Errorf("got table %q", buf.String())
+	}
+
+	// single record as names and values
+	buf.Reset()
+	if err := output.Write(&buf, output.Table, &records[1]); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 4 || !strings.HasPrefix(lines[2], "days") {
+		t.Errorf("got table %q", buf.String())
+	}
+}
+
+func TestWrite_Sections(t *testing.T) {
+	sections := output.Sections{
+		{Name: "first", Records: records[0]},
+		{Name: "second", Records: records},
+	}
+	var buf bytes.Buffer
+	if err := output.Write(&buf, output.JSON, sections); err != nil {
+		t.Fatal(err)
+	}
+	decoded := struct {
+		First  map[string]interface{}   `json:"first"`
+		Second []map[string]interface{} `json:"second"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.First["id"] != "A" || len(decoded.Second) != 2 {
+		t.Errorf("got %v", decoded)
+	}
+	if strings.Index(buf.String(), "first") > strings.Index(buf.String(), "second") {
+		t.Errorf("sections not in order: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := output.Write(&buf, output.CSV, sections); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "# first\nid,") || !strings.Contains(buf.String(), "\n\n# second\nid,") {
+		t.Errorf("got CSV %q", buf.String())
+	}
+}
+
+func TestWrite_Slices(t *testing.T) {
+	nodes := struct {
+		Maturities []float64 `json:"maturities"`
+		Rates      []float64 `json:"rates" format:"%.1f"`
+	}{[]float64{0.5, 1.0}, []float64{1.25, -0.5}}
+	var buf bytes.Buffer
+	if err := output.Write(&buf, output.CSV, nodes); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "maturities,rates\n0.5 1,1.25 -0.5\n"; buf.String() != expected {
+		t.Errorf("got %q, expected %q", buf.String(), expected)
+	}
+	buf.Reset()
+	if err := output.Write(&buf, output.Table, nodes); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "0.5000 1.0000") || !strings.Contains(buf.String(), "  1.2 -0.5\n") {
+		t.Errorf("got table %q", buf.String())
+	}
+}
+
+func TestWrite_NotStruct(t *testing.T) {
+	var buf bytes.Buffer
+	if err := output.Write(&buf, output.CSV, []float64{1.0}); err == nil {
+		t.Errorf("expected error for records which are not structs")
+	}
+}
+
+func TestWrite_NotFinite(t *testing.T) {
+	type result struct {
+		ID     string    `json:"id"`
+		Yield  float64   `json:"yield" format:"%.2f"`
+		Errors []float64 `json:"errors"`
+		Kappa  float64   `json:"kappa,omitempty"`
+	}
+	results := []result{
+		{"A", math.NaN(), []float64{1.0, math.Inf(1)}, math.Inf(-1)},
+		{"B", 1.5, nil, 0.0},
+	}
+
+	var buf bytes.Buffer
+	if err := output.Write(&buf, output.JSON, output.Sections{{Name: "results", Records: results}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"results":[{"id":"A","yield":null,"errors":[1,null],"kappa":null},{"id":"B","yield":1.5,"errors":null}]}`
+	if got := strings.Join(strings.Fields(buf.String()), ""); got != expected {
+		t.Errorf("got %s, expected %s", got, expected)
+	}
+
+	buf.Reset()
+	if err := output.Write(&buf, output.CSV, results); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "id,yield,errors,kappa\nA,NaN,1 +Inf,-Inf\nB,1.5,,0\n"; buf.String() != expected {
+		t.Errorf("got %q, expected %q", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if err := output.Write(&buf, output.Table, results); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "NaN") || !strings.Contains(buf.String(), "+Inf") {
+		t.Errorf("got %q", buf.String())
+	}
+}
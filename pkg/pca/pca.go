@@ -0,0 +1,139 @@
+package pca
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/konimarti/fixedincome/pkg/risk"
+	"github.com/konimarti/fixedincome/pkg/term"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// Level is the first principal component (parallel shift)
+	Level int = iota
+	// Slope is the second principal component (steepening)
+	Slope
+	// Curvature is the third principal component (butterfly)
+	Curvature
+)
+
+// Analysis contains the principal components of the daily changes of the
+// spot rates at the tenors
+type Analysis struct {
+	// Tenors are the maturities in years
+	Tenors []float64
+	// Mean is the average daily change in bps per tenor
+	Mean []float64
+	// Variances are the variances of the components in bps^2 (descending)
+	Variances []float64
+	// Loadings[k][j] is the loading of component k at tenor j. The loadings
+	// have unit length; the level loadings sum to a positive number and the
+	// loadings of the other components are positive at the longest tenor.
+	Loadings [][]float64
+}
+
+// Analyze runs a principal component analysis of the daily changes of the
+// spot rates at the tenors for the history of term structures (ordered by
+// date). If no tenors are given, risk.Tenors are used.
+func Analyze(history []term.Structure, tenors []float64) (*Analysis, error) {
+	if len(tenors) == 0 {
+		tenors = risk.Tenors
+	}
+	changes, err := risk.Changes(history, tenors, 1)
+	if err != nil {
+		return nil, err
+	}
+	cov, err := risk.Covariance(history, tenors)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(tenors)
+	data := make([]float64, 0, n*n)
+	for _, row := range cov {
+		data = append(data, row...)
+	}
+	var eig mat.EigenSym
+	if ok := eig.Factorize(mat.NewSymDense(n, data), true); !ok {
+		return nil, fmt.Errorf("eigendecomposition of covariance matrix failed")
+	}
+	values := eig.Values(nil)
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+
+	a := &Analysis{
+		Tenors:    tenors,
+		Mean:      make([]float64, n),
+		Variances: make([]float64, n),
+		Loadings:  make([][]float64, n),
+	}
+	for _, change := range changes {
+		for j := range change {
+			a.Mean[j] += change[j] / float64(len(changes))
+		}
+	}
+
+	// eigenvalues are in ascending order
+	for k := 0; k < n; k += 1 {
+		col := n - 1 - k
+		a.Variances[k] = math.Max(values[col], 0.0)
+		loading := make([]float64, n)
+		sum := 0.0
+		for j := 0; j < n; j += 1 {
+			loading[j] = vectors.At(j, col)
+			sum += loading[j]
+		}
+		if (k == Level && sum < 0.0) || (k != Level && loading[n-1] < 0.0) {
+			for j := range loading {
+				loading[j] = -loading[j]
+			}
+		}
+		a.Loadings[k] = loading
+	}
+	return a, nil
+}
+
+// ExplainedVariance returns the share of the total variance explained by
+// each component in percent
+func (a *Analysis) ExplainedVariance() []float64 {
+	total := 0.0
+	for _, v := range a.Variances {
+		total += v
+	}
+	explained := make([]float64, len(a.Variances))
+	if total == 0.0 {
+		return explained
+	}
+	for k, v := range a.Variances {
+		explained[k] = v / total * 100.0
+	}
+	return explained
+}
+
+// Shock returns the changes of the spot rates in bps at the tenors for a
+// move of the given components by the number of (daily) standard deviations
+func (a *Analysis) Shock(sigmas []float64) ([]float64, error) {
+	if len(sigmas) > len(a.Variances) {
+		return nil, fmt.Errorf("only %d components available", len(a.Variances))
+	}
+	changes := make([]float64, len(a.Tenors))
+	for k, s := range sigmas {
+		scale := s * math.Sqrt(a.Variances[k])
+		for j := range changes {
+			changes[j] += scale * a.Loadings[k][j]
+		}
+	}
+	return changes, nil
+}
+
+// Scenario returns the term structure shifted by the PCA shock for the given
+// numbers of standard deviations of the components (e.g. {2.0, -1.0} for a
+// two standard deviation level shift and a one standard deviation flattening)
+func (a *Analysis) Scenario(ts term.Structure, sigmas []float64) (term.Structure, error) {
+	changes, err := a.Shock(sigmas)
+	if err != nil {
+		return nil, err
+	}
+	return &term.BasisSpread{Base: ts, Maturities: a.Tenors, Spreads: changes}, nil
+}
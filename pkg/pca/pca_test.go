@@ -0,0 +1,78 @@
+package pca_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/pca"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func history(n int) []term.Structure {
+	rng := rand.New(rand.NewSource(1))
+	b0, b1 := 1.0, -1.0
+	h := []term.Structure{}
+	for i := 0; i < n; i += 1 {
+		h = append(h, &term.NelsonSiegelSvensson{B0: b0, B1: b1, B2: 0.5, B3: 0.2, T1: 2.0, T2: 5.0})
+		b0 += 0.05 * rng.NormFloat64()
+		b1 += 0.02 * rng.NormFloat64()
+	}
+	return h
+}
+
+func TestAnalyze(t *testing.T) {
+	tenors := []float64{1, 2, 3, 5, 7, 10, 20}
+	a, err := pca.Analyze(history(250), tenors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	explained := a.ExplainedVariance()
+	if explained[pca.Level] < 80.0 {
+		t.Errorf("level explains only %v%%", explained[pca.Level])
+	}
+	if math.Abs(explained[pca.Level]+explained[pca.Slope]-100.0) > 1e-6 {
+		t.Errorf("level and slope explain %v%%, expected 100%%", explained[pca.Level]+explained[pca.Slope])
+	}
+	for k := 1; k < len(a.Variances); k += 1 {
+		if a.Variances[k] > a.Variances[k-1] {
+			t.Errorf("variances not in descending order: %v", a.Variances)
+		}
+	}
+
+	// level loadings are positive, slope loadings increase with the tenor
+	for j, l := range a.Loadings[pca.Level] {
+		if l <= 0.0 {
+			t.Errorf("level loading at tenor %v is %v", tenors[j], l)
+		}
+	}
+	slope := a.Loadings[pca.Slope]
+	if slope[len(slope)-1] <= slope[0] {
+		t.Errorf("slope loadings %v do not increase", slope)
+	}
+}
+
+func TestScenario(t *testing.T) {
+	h := history(100)
+	a, err := pca.Analyze(h, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := h[len(h)-1]
+	scenario, err := a.Scenario(ts, []float64{2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j, tenor := range a.Tenors {
+		got := (scenario.Rate(tenor) - ts.Rate(tenor)) * 100.0
+		expected := 2.0 * math.Sqrt(a.Variances[pca.Level]) * a.Loadings[pca.Level][j]
+		if math.Abs(got-expected) > 1e-9 {
+			t.Errorf("tenor %v: got %v bps, expected %v bps", tenor, got, expected)
+		}
+	}
+
+	if _, err := a.Shock(make([]float64, len(a.Tenors)+1)); err == nil {
+		t.Errorf("expected error for too many components")
+	}
+}
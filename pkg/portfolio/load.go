@@ -0,0 +1,180 @@
+package portfolio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+)
+
+const DateFmt = "2006-01-02"
+
+// Record describes a position in a positions file
+type Record struct {
+	ID       string `json:"id"`
+	Book     string `json:"book"`
+	Currency string `json:"currency"`
+	// Type of the security: "straight" (default) or "floating"
+	Type string `json:"type"`
+	// Maturity date (format: 2006-01-02)
+	Maturity string `json:"maturity"`
+	// Coupon in percent; current rate for floating-rate bonds
+	Coupon     float64 `json:"coupon"`
+	Frequency  int     `json:"frequency"`
+	Basis      string  `json:"basis"`
+	Redemption float64 `json:"redemption"`
+	Quantity   float64 `json:"quantity"`
+}
+
+// Position creates the position for the given settlement date
+func (r Record) Position(settlement time.Time) (Position, error) {
+	maturityDate, err := time.Parse(DateFmt, strings.TrimSpace(r.Maturity))
+	if err != nil {
+		return Position{}, err
+	}
+	redemption := r.Redemption
+	if redemption == 0.0 {
+		redemption = 100.0
+	}
+	schedule := maturity.Schedule{
+		Settlement: settlement,
+		Maturity:   maturityDate,
+		Frequency:  r.Frequency,
+		Basis:      r.Basis,
+	}
+	pos := Position{
+		ID:       r.ID,
+		Quantity: r.Quantity,
+		Book:     r.Book,
+		Currency: r.Currency,
+	}
+	switch strings.ToLower(strings.TrimSpace(r.Type)) {
+	case "", "straight":
+		pos.Security = &bond.Straight{Schedule: schedule, Coupon: r.Coupon, Redemption: redemption}
+	case "floating":
+		pos.Security = &bond.Floating{Schedule: schedule, Rate: r.Coupon, Redemption: redemption}
+	default:
+		return Position{}, fmt.Errorf("unknown security type %s", r.Type)
+	}
+	return pos, nil
+}
+
+// fromRecords creates a portfolio from the records
+func fromRecords(records []Record, settlement time.Time) (*Portfolio, error) {
+	p := &Portfolio{}
+	for i, r := range records {
+		pos, err := r.Position(settlement)
+		if err != nil {
+			return nil, fmt.Errorf("position %d: %v", i+1, err)
+		}
+		p.Add(pos)
+	}
+	return p, nil
+}
+
+// ReadJSON reads the positions from a json array of records
+func ReadJSON(r io.Reader, settlement time.Time) (*Portfolio, error) {
+	records := []Record{}
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, err
+	}
+	return fromRecords(records, settlement)
+}
+
+// ReadCSV reads the positions from comma or semicolon separated values. The
+// header line names the columns after the json keys of Record.
+func ReadCSV(r io.Reader, settlement time.Time) (*Portfolio, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if strings.Count(string(data), ";") > strings.Count(string(data), ",") {
+		reader.Comma = ';'
+	}
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no header line")
+	}
+
+	header := lines[0]
+	records := []Record{}
+	for i, line := range lines[1:] {
+		r := Record{}
+		for j, value := range line {
+			if j >= len(header) {
+				break
+			}
+			if err := r.set(header[j], strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+2, err)
+			}
+		}
+		records = append(records, r)
+	}
+	return fromRecords(records, settlement)
+}
+
+// set assigns the value to the field of the record with the json key
+func (r *Record) set(key, value string) error {
+	var err error
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "id":
+		r.ID = value
+	case "book":
+		r.Book = value
+	case "currency":
+		r.Currency = value
+	case "type":
+		r.Type = value
+	case "maturity":
+		r.Maturity = value
+	case "basis":
+		r.Basis = value
+	case "coupon":
+		r.Coupon, err = parseFloat(value)
+	case "redemption":
+		r.Redemption, err = parseFloat(value)
+	case "quantity":
+		r.Quantity, err = parseFloat(value)
+	case "frequency":
+		if value != "" {
+			r.Frequency, err = strconv.Atoi(value)
+		}
+	default:
+		return fmt.Errorf("unknown column %s", key)
+	}
+	return err
+}
+
+func parseFloat(value string) (float64, error) {
+	if value == "" {
+		return 0.0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// Load reads the positions from a json (extension .json) or CSV file
+func Load(name string, settlement time.Time) (*Portfolio, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if strings.ToLower(filepath.Ext(name)) == ".json" {
+		return ReadJSON(f, settlement)
+	}
+	return ReadCSV(f, settlement)
+}
@@ -0,0 +1,62 @@
+package portfolio_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/portfolio"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestReadCSV(t *testing.T) {
+	data := `id;book;currency;type;maturity;coupon;frequency;basis;quantity
+CH0224396983;banking;CHF;straight;2026-05-28;1.25;1;30E360;10000
+FRN1;trading;CHF;floating;2024-06-30;0.2;4;ACT360;-5000
+`
+	p, err := portfolio.ReadCSV(strings.NewReader(data), settlement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Positions) != 2 {
+		t.Fatalf("got %d positions, expected 2", len(p.Positions))
+	}
+	b, ok := p.Positions[0].Security.(*bond.Straight)
+	if !ok {
+		t.Fatalf("expected straight bond, got %T", p.Positions[0].Security)
+	}
+	if b.Coupon != 1.25 || b.Redemption != 100.0 || b.Frequency != 1 || p.Positions[0].Quantity != 10000.0 {
+		t.Errorf("wrong bond %+v", b)
+	}
+	if _, ok := p.Positions[1].Security.(*bond.Floating); !ok {
+		t.Errorf("expected floating-rate bond, got %T", p.Positions[1].Security)
+	}
+	if p.Positions[1].Book != "trading" || p.Positions[1].Quantity != -5000.0 {
+		t.Errorf("wrong position %+v", p.Positions[1])
+	}
+
+	if _, err := portfolio.ReadCSV(strings.NewReader("id,unknown\nA,1\n"), settlement); err == nil {
+		t.Errorf("expected error for unknown column")
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	data := `[
+	{"id": "A", "book": "banking", "currency": "CHF", "maturity": "2023-04-01", "coupon": 1.0, "quantity": 1000},
+	{"id": "B", "book": "trading", "currency": "CHF", "type": "straight", "maturity": "2031-04-01", "coupon": 2.5, "quantity": 500}
+]`
+	p, err := portfolio.ReadJSON(strings.NewReader(data), settlement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := &term.Flat{R: 1.0}
+	expected := 1000.0*straight(2, 1.0).PresentValue(ts) + 500.0*straight(10, 2.5).PresentValue(ts)
+	if pv := p.PresentValue(ts); math.Abs(pv-expected) > 1e-8 {
+		t.Errorf("got %v, expected %v", pv, expected)
+	}
+
+	if _, err := portfolio.ReadJSON(strings.NewReader(`[{"type": "swap", "maturity": "2030-01-01"}]`), settlement); err == nil {
+		t.Errorf("expected error for unknown type")
+	}
+}
@@ -0,0 +1,268 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+var (
+	// Shift is the parallel shift of the term structure in bps used for the
+	// numerical sensitivities
+	Shift = 1.0
+)
+
+// Position is a holding of a security
+type Position struct {
+	// ID identifies the position (e.g. ISIN or trade id)
+	ID string
+	// Security is the held instrument
+	Security fixedincome.Security
+	// Quantity multiplies the present value of the security, e.g. the
+	// nominal divided by 100 for bonds quoted in percent of par
+	Quantity float64
+	// Book is the trading or banking book of the position
+	Book string
+	// Currency is the currency of the position
+	Currency string
+}
+
+// Portfolio is a collection of positions. The term structure passed to the
+// methods is the curve of the base currency; positions in other currencies
+// are valued on their curve in Curves and converted with the exchange rates
+// in Fx (units of base currency per unit of foreign currency). Without a base
+// currency all positions have to be in the same currency.
+type Portfolio struct {
+	Positions []Position
+	Base      string
+	Fx        map[string]float64
+	// Curves contains the term structures of the other currencies; the
+	// sensitivities shift them together with the base curve, while
+	// scenarios on the base curve (e.g. of the risk package) leave them
+	// unchanged
+	Curves map[string]term.Structure
+}
+
+// Add appends a position to the portfolio
+func (p *Portfolio) Add(pos Position) {
+	p.Positions = append(p.Positions, pos)
+}
+
+// SetFx sets the base currency and the exchange rates; it returns an error if
+// the currency of a position cannot be converted into the base currency
+func (p *Portfolio) SetFx(base string, fx map[string]float64) error {
+	p.Base, p.Fx = base, fx
+	return p.CheckFx()
+}
+
+// CheckFx returns an error if the currency of a position has no exchange rate
+// into the base currency
+func (p *Portfolio) CheckFx() error {
+	if p.Base == "" {
+		return nil
+	}
+	for _, pos := range p.Positions {
+		if pos.Currency == "" || pos.Currency == p.Base {
+			continue
+		}
+		if _, ok := p.Fx[pos.Currency]; !ok {
+			return fmt.Errorf("position %s: no exchange rate for %s/%s", pos.ID, pos.Currency, p.Base)
+		}
+	}
+	return nil
+}
+
+// domestic returns true if the position is valued on the term structure of
+// the base currency
+func (p *Portfolio) domestic(currency string) bool {
+	return currency == "" || p.Base == "" || currency == p.Base
+}
+
+// fx returns the exchange rate from the currency into the base currency
+func (p *Portfolio) fx(currency string) (float64, error) {
+	if p.domestic(currency) {
+		return 1.0, nil
+	}
+	rate, ok := p.Fx[currency]
+	if !ok {
+		return 0.0, fmt.Errorf("no exchange rate for %s/%s", currency, p.Base)
+	}
+	return rate, nil
+}
+
+// curve returns the term structure of the currency where ts is the term
+// structure of the base currency
+func (p *Portfolio) curve(currency string, ts term.Structure) (term.Structure, error) {
+	if p.domestic(currency) {
+		return ts, nil
+	}
+	curve, ok := p.Curves[currency]
+	if !ok || curve == nil {
+		return nil, fmt.Errorf("no term structure for %s", currency)
+	}
+	return curve, nil
+}
+
+// checkCurrencies returns an error if the positions are in different
+// currencies without base currency
+func (p *Portfolio) checkCurrencies() error {
+	if p.Base != "" {
+		return nil
+	}
+	currency := ""
+	for _, pos := range p.Positions {
+		if pos.Currency == "" || pos.Currency == currency {
+			continue
+		}
+		if currency != "" {
+			return fmt.Errorf("positions in %s and %s without base currency", currency, pos.Currency)
+		}
+		currency = pos.Currency
+	}
+	return nil
+}
+
+// value returns the value of the position in the base currency where move
+// (if not nil) changes the term structures of all currencies
+func (p *Portfolio) value(pos Position, ts term.Structure, move func(term.Structure) term.Structure) (float64, error) {
+	curve, err := p.curve(pos.Currency, ts)
+	if err != nil {
+		return 0.0, fmt.Errorf("position %s: %v", pos.ID, err)
+	}
+	rate, err := p.fx(pos.Currency)
+	if err != nil {
+		return 0.0, fmt.Errorf("position %s: %v", pos.ID, err)
+	}
+	if move != nil {
+		curve = move(curve)
+	}
+	value, err := fixedincome.Value(pos.Security, curve)
+	if err != nil {
+		return 0.0, fmt.Errorf("position %s: %v", pos.ID, err)
+	}
+	return pos.Quantity * rate * value, nil
+}
+
+// valueWith returns the value of the portfolio in the base currency where
+// move (if not nil) changes the term structures of all currencies
+func (p *Portfolio) valueWith(ts term.Structure, move func(term.Structure) term.Structure) (float64, error) {
+	if err := p.checkCurrencies(); err != nil {
+		return 0.0, err
+	}
+	pv := 0.0
+	for _, pos := range p.Positions {
+		value, err := p.value(pos, ts, move)
+		if err != nil {
+			return 0.0, err
+		}
+		pv += value
+	}
+	return pv, nil
+}
+
+// Value returns the value of the portfolio in the base currency or the error
+// of the first position that cannot be valued
+func (p *Portfolio) Value(ts term.Structure) (float64, error) {
+	return p.valueWith(ts, nil)
+}
+
+// PresentValue returns the value of the portfolio in the base currency or
+// NaN if a position cannot be valued (see Value)
+func (p *Portfolio) PresentValue(ts term.Structure) float64 {
+	pv, err := p.Value(ts)
+	if err != nil {
+		return math.NaN()
+	}
+	return pv
+}
+
+// shift returns the term structure shifted in parallel by bps
+func shift(ts term.Structure, bps float64) term.Structure {
+	return &term.BasisSpread{Base: ts, Spread: bps}
+}
+
+// values returns the values of the portfolio for the term structures shifted
+// by each of the bps (unshifted for zero)
+func (p *Portfolio) values(ts term.Structure, bps ...float64) ([]float64, error) {
+	values := make([]float64, len(bps))
+	for i, b := range bps {
+		var move func(term.Structure) term.Structure
+		if b != 0.0 {
+			b := b
+			move = func(ts term.Structure) term.Structure { return shift(ts, b) }
+		}
+		var err error
+		if values[i], err = p.valueWith(ts, move); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// PVBP returns the change in value of the portfolio for a parallel increase
+// of the term structures by one basis point
+func (p *Portfolio) PVBP(ts term.Structure) (float64, error) {
+	v, err := p.values(ts, 0.0, 1.0)
+	if err != nil {
+		return 0.0, err
+	}
+	return v[1] - v[0], nil
+}
+
+// Duration calculates the duration of the portfolio
+// dP/P = -D * dr
+func (p *Portfolio) Duration(ts term.Structure) (float64, error) {
+	v, err := p.values(ts, 0.0, Shift, -Shift)
+	if err != nil || v[0] == 0.0 {
+		return 0.0, err
+	}
+	dr := Shift * 0.0001
+	return (v[1] - v[2]) / (2.0 * dr * v[0]), nil
+}
+
+// Convexity calculates the convexity of the portfolio
+// dP/P = -D * dr + 1/2 * C * dr^2
+func (p *Portfolio) Convexity(ts term.Structure) (float64, error) {
+	v, err := p.values(ts, 0.0, Shift, -Shift)
+	if err != nil || v[0] == 0.0 {
+		return 0.0, err
+	}
+	dr := Shift * 0.0001
+	return (v[1] + v[2] - 2.0*v[0]) / (dr * dr * v[0]), nil
+}
+
+// GroupBy splits the portfolio into sub-portfolios by the given key
+func (p *Portfolio) GroupBy(key func(Position) string) map[string]*Portfolio {
+	groups := make(map[string]*Portfolio)
+	for _, pos := range p.Positions {
+		k := key(pos)
+		if _, ok := groups[k]; !ok {
+			groups[k] = &Portfolio{Base: p.Base, Fx: p.Fx, Curves: p.Curves}
+		}
+		groups[k].Add(pos)
+	}
+	return groups
+}
+
+// Books returns the sub-portfolios per book
+func (p *Portfolio) Books() map[string]*Portfolio {
+	return p.GroupBy(func(pos Position) string { return pos.Book })
+}
+
+// Currencies returns the sub-portfolios per currency
+func (p *Portfolio) Currencies() map[string]*Portfolio {
+	return p.GroupBy(func(pos Position) string { return pos.Currency })
+}
+
+// Keys returns the sorted keys of the groups
+func Keys(groups map[string]*Portfolio) []string {
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,232 @@
+package portfolio_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/portfolio"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+var settlement = time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+func straight(years int, coupon float64) *bond.Straight {
+	return &bond.Straight{
+		Schedule: maturity.Schedule{
+			Settlement: settlement,
+			Maturity:   settlement.AddDate(years, 0, 0),
+			Frequency:  1,
+		},
+		Coupon:     coupon,
+		Redemption: 100.0,
+	}
+}
+
+var eur = &term.Flat{R: 0.5}
+
+func testPortfolio() *portfolio.Portfolio {
+	return &portfolio.Portfolio{
+		Base:   "CHF",
+		Fx:     map[string]float64{"EUR": 1.1},
+		Curves: map[string]term.Structure{"EUR": eur},
+		Positions: []portfolio.Position{
+			{ID: "A", Security: straight(2, 1.0), Quantity: 1000.0, Book: "banking", Currency: "CHF"},
+			{ID: "B", Security: straight(10, 2.5), Quantity: 500.0, Book: "trading", Currency: "CHF"},
+			{ID: "C", Security: straight(5, 0.5), Quantity: -200.0, Book: "trading", Currency: "EUR"},
+		},
+	}
+}
+
+func TestPortfolio_PresentValue(t *testing.T) {
+	p := testPortfolio()
+	ts := &term.Flat{R: 1.0}
+
+	expected := 1000.0*p.Positions[0].Security.PresentValue(ts) +
+		500.0*p.Positions[1].Security.PresentValue(ts) -
+		200.0*1.1*p.Positions[2].Security.PresentValue(eur)
+	if pv, err := p.Value(ts); err != nil || math.Abs(pv-expected) > 1e-8 {
+		t.Errorf("got %v and error %v, expected %v", pv, err, expected)
+	}
+	if pv := p.PresentValue(ts); math.Abs(pv-expected) > 1e-8 {
+		t.Errorf("got present value %v, expected %v", pv, expected)
+	}
+
+	books := p.Books()
+	if keys := portfolio.Keys(books); len(keys) != 2 || keys[0] != "banking" || keys[1] != "trading" {
+		t.Errorf("got books %v", keys)
+	}
+	sum := books["banking"].PresentValue(ts) + books["trading"].PresentValue(ts)
+	if math.IsNaN(sum) || math.Abs(sum-expected) > 1e-8 {
+		t.Errorf("sum of books %v, expected %v", sum, expected)
+	}
+}
+
+func TestPortfolio_SetFx(t *testing.T) {
+	p := testPortfolio()
+	if err := p.SetFx("CHF", map[string]float64{"EUR": 1.1}); err != nil {
+		t.Errorf("got error %v", err)
+	}
+	if err := p.SetFx("CHF", nil); err == nil {
+		t.Errorf("expected error for missing EUR/CHF rate")
+	}
+	if err := p.SetFx("", nil); err != nil {
+		t.Errorf("got error %v without base currency", err)
+	}
+}
+
+func TestPortfolio_Currencies(t *testing.T) {
+	ts := &term.Flat{R: 1.0}
+
+	// exchange rates set directly
+	p := testPortfolio()
+	p.Fx = nil
+	if _, err := p.Value(ts); err == nil || !strings.Contains(err.Error(), "no exchange rate") {
+		t.Errorf("got error %v, expected missing exchange rate", err)
+	}
+	if _, err := p.Duration(ts); err == nil {
+		t.Errorf("expected error for duration")
+	}
+
+	// EUR position without EUR curve
+	p = testPortfolio()
+	p.Curves = nil
+	if _, err := p.Value(ts); err == nil || !strings.Contains(err.Error(), "no term structure") {
+		t.Errorf("got error %v, expected missing term structure", err)
+	}
+
+	// mixed currencies without base currency
+	p = testPortfolio()
+	p.Base = ""
+	if _, err := p.Value(ts); err == nil {
+		t.Errorf("expected error for CHF and EUR positions without base currency")
+	}
+	p.Positions = p.Positions[:2]
+	if _, err := p.Value(ts); err != nil {
+		t.Errorf("got error %v for CHF positions without base currency", err)
+	}
+
+	// shifts move the EUR curve as well
+	p = testPortfolio()
+	eurOnly := &portfolio.Portfolio{Base: "CHF", Fx: p.Fx, Curves: p.Curves, Positions: p.Positions[2:]}
+	b := p.Positions[2].Security
+	shifted := *eur
+	expected := -200.0 * 1.1 * (b.PresentValue(shifted.SetSpread(1.0)) - b.PresentValue(eur))
+	if pvbp, err := eurOnly.PVBP(ts); err != nil || math.Abs(pvbp-expected) > 1e-6 {
+		t.Errorf("got pvbp %v and error %v, expected %v", pvbp, err, expected)
+	}
+}
+
+func TestPortfolio_Risk(t *testing.T) {
+	b := straight(10, 2.5)
+	p := &portfolio.Portfolio{Positions: []portfolio.Position{{ID: "B", Security: b, Quantity: 1.0}}}
+	ts := &term.Flat{R: 1.0}
+
+	if d, err := p.Duration(ts); err != nil || math.Abs(d-b.Duration(ts)) > 1e-4 {
+		t.Errorf("got duration %v and error %v, expected %v", d, err, b.Duration(ts))
+	}
+	if c, err := p.Convexity(ts); err != nil || math.Abs(c-b.Convexity(ts)) > 1e-2 {
+		t.Errorf("got convexity %v and error %v, expected %v", c, err, b.Convexity(ts))
+	}
+
+	ts2 := *ts
+	expected := b.PresentValue(ts2.SetSpread(1.0)) - b.PresentValue(ts)
+	if pvbp, err := p.PVBP(ts); err != nil || math.Abs(pvbp-expected) > 1e-8 {
+		t.Errorf("got pvbp %v and error %v, expected %v", pvbp, err, expected)
+	}
+	if ts.Spread != 0.0 {
+		t.Errorf("term structure was modified")
+	}
+}
+
+func TestPortfolio_KeyRateExposures(t *testing.T) {
+	p := testPortfolio()
+	ts := &term.NelsonSiegelSvensson{B0: 2.0, B1: -1.0, B2: 0.5, B3: 0.1, T1: 2.0, T2: 5.0}
+
+	exposures, err := p.KeyRateExposures(ts, []float64{1, 2, 5, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exposures) != 4 {
+		t.Fatalf("got %d exposures, expected 4", len(exposures))
+	}
+	sum := 0.0
+	for _, e := range exposures {
+		sum += e
+	}
+	if pvbp, _ := p.PVBP(ts); math.Abs(sum-pvbp) > 1e-3*math.Abs(pvbp) {
+		t.Errorf("sum of key rate exposures %v, expected %v", sum, pvbp)
+	}
+	// short position in the 5-year EUR bond
+	if exposures[2] <= 0.0 {
+		t.Errorf("expected positive exposure at 5 years, got %v", exposures[2])
+	}
+}
+
+func TestPortfolio_Contributions(t *testing.T) {
+	p := testPortfolio()
+	ts := &term.Flat{R: 1.0}
+
+	contributions, err := p.Contributions(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := p.Summary(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pv, pvbp, weight, duration float64
+	for _, c := range contributions {
+		pv += c.PresentValue
+		pvbp += c.PVBP
+		weight += c.Weight
+		duration += c.Duration
+	}
+	if math.Abs(pv-summary.PresentValue) > 1e-8 {
+		t.Errorf("got present value %v, expected %v", pv, summary.PresentValue)
+	}
+	if math.Abs(pvbp-summary.PVBP) > 1e-8 {
+		t.Errorf("got pvbp %v, expected %v", pvbp, summary.PVBP)
+	}
+	if math.Abs(weight-100.0) > 1e-8 {
+		t.Errorf("got weights %v, expected 100", weight)
+	}
+	if math.Abs(duration-summary.Duration) > 1e-6 {
+		t.Errorf("got duration %v, expected %v", duration, summary.Duration)
+	}
+}
+
+// unpriced is a security without market data, e.g. a swap without fixings
+type unpriced struct{}
+
+func (unpriced) PresentValue(ts term.Structure) float64 { return math.NaN() }
+
+func (unpriced) Value(ts term.Structure) (float64, error) {
+	return 0.0, fmt.Errorf("fixing missing")
+}
+
+func TestPortfolio_ValueError(t *testing.T) {
+	p := testPortfolio()
+	p.Add(portfolio.Position{ID: "D", Security: unpriced{}, Quantity: 1.0, Currency: "CHF"})
+	ts := &term.Flat{R: 1.0}
+
+	if _, err := p.Value(ts); err == nil || !strings.Contains(err.Error(), "position D") {
+		t.Errorf("got error %v", err)
+	}
+	if pv := p.PresentValue(ts); !math.IsNaN(pv) {
+		t.Errorf("got present value %v, expected NaN", pv)
+	}
+	if _, err := p.Summary(ts); err == nil {
+		t.Errorf("expected error for summary")
+	}
+	if _, err := p.KeyRateExposures(ts, nil); err == nil {
+		t.Errorf("expected error for key rate exposures")
+	}
+	if _, err := p.Contributions(ts); err == nil {
+		t.Errorf("expected error for contributions")
+	}
+}
@@ -0,0 +1,124 @@
+package portfolio
+
+import (
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+var (
+	// KeyRates are the default key rate maturities in years
+	KeyRates = []float64{0.25, 0.5, 1, 2, 3, 5, 7, 10, 15, 20, 30}
+)
+
+// Summary contains the aggregated valuation and risk figures
+type Summary struct {
+	PresentValue float64
+	PVBP         float64
+	Duration     float64
+	Convexity    float64
+}
+
+// Summary returns the aggregated valuation and risk figures of the portfolio
+func (p *Portfolio) Summary(ts term.Structure) (Summary, error) {
+	var s Summary
+	var err error
+	if s.PresentValue, err = p.Value(ts); err != nil {
+		return s, err
+	}
+	if s.PVBP, err = p.PVBP(ts); err != nil {
+		return s, err
+	}
+	if s.Duration, err = p.Duration(ts); err != nil {
+		return s, err
+	}
+	s.Convexity, err = p.Convexity(ts)
+	return s, err
+}
+
+// keyRateShift returns the term structure with a triangular shift of bps at
+// key rate i which decreases linearly to zero at the neighbouring key rates.
+// The shifts of the first and last key rates are extended flat, so that the
+// sum of all key rate shifts equals a parallel shift.
+func keyRateShift(ts term.Structure, keys []float64, i int, bps float64) term.Structure {
+	maturities := []float64{keys[i]}
+	spreads := []float64{bps}
+	if i > 0 {
+		maturities = append([]float64{keys[i-1]}, maturities...)
+		spreads = append([]float64{0.0}, spreads...)
+	}
+	if i < len(keys)-1 {
+		maturities = append(maturities, keys[i+1])
+		spreads = append(spreads, 0.0)
+	}
+	return &term.BasisSpread{Base: ts, Maturities: maturities, Spreads: spreads}
+}
+
+// KeyRateExposures returns the change in value of the portfolio for an
+// increase of each key rate (in years, increasing) by one basis point. If no key
+// rates are given, the default KeyRates are used.
+func (p *Portfolio) KeyRateExposures(ts term.Structure, keys []float64) ([]float64, error) {
+	if len(keys) == 0 {
+		keys = KeyRates
+	}
+	pv, err := p.Value(ts)
+	if err != nil {
+		return nil, err
+	}
+	exposures := make([]float64, len(keys))
+	for i := range keys {
+		value, err := p.valueWith(ts, func(ts term.Structure) term.Structure {
+			return keyRateShift(ts, keys, i, 1.0)
+		})
+		if err != nil {
+			return nil, err
+		}
+		exposures[i] = value - pv
+	}
+	return exposures, nil
+}
+
+// Contribution is the share of a position in the portfolio's value and risk
+type Contribution struct {
+	ID           string
+	Book         string
+	Currency     string
+	PresentValue float64
+	PVBP         float64
+	// Weight is the share of the position in the portfolio value in percent
+	Weight float64
+	// Duration is the contribution to the portfolio duration, i.e. the
+	// weighted duration of the position
+	Duration float64
+}
+
+// Contributions breaks down the value and risk of the portfolio by position
+func (p *Portfolio) Contributions(ts term.Structure) ([]Contribution, error) {
+	total, err := p.Value(ts)
+	if err != nil {
+		return nil, err
+	}
+	contributions := make([]Contribution, len(p.Positions))
+	for i, pos := range p.Positions {
+		single := Portfolio{Positions: []Position{pos}, Base: p.Base, Fx: p.Fx, Curves: p.Curves}
+		c := Contribution{
+			ID:       pos.ID,
+			Book:     pos.Book,
+			Currency: pos.Currency,
+		}
+		if c.PresentValue, err = single.Value(ts); err != nil {
+			return nil, err
+		}
+		if c.PVBP, err = single.PVBP(ts); err != nil {
+			return nil, err
+		}
+		if total != 0.0 {
+			duration, err := single.Duration(ts)
+			if err != nil {
+				return nil, err
+			}
+			c.Weight = c.PresentValue / total * 100.0
+			c.Duration = c.Weight / 100.0 * duration
+		}
+		contributions[i] = c
+	}
+	return contributions, nil
+}
@@ -0,0 +1,154 @@
+package pricing
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/refdata"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// Valuation contains the prices and risk figures of a bond
+type Valuation struct {
+	ID       string  `json:"id"`
+	Issuer   string  `json:"issuer"`
+	Currency string  `json:"currency"`
+	Maturity string  `json:"maturity"`
+	Coupon   float64 `json:"coupon" format:"%.3f"`
+	// Spread in bps over the term structure used for the valuation
+	Spread float64 `json:"spread" format:"%.1f"`
+	// Dirty, Clean and Accrued are the prices from the term structure
+	Dirty   float64 `json:"dirty"`
+	Clean   float64 `json:"clean"`
+	Accrued float64 `json:"accrued"`
+	// Quote is the quoted clean price (0.0 if not quoted)
+	Quote float64 `json:"quote"`
+	// Yield is the yield to maturity in percent (continuously compounded) for
+	// the quoted price or, if not quoted, for the clean price
+	Yield float64 `json:"yield"`
+	// ZSpread is the static spread in bps over the term structure for the
+	// quoted price (0.0 if not quoted)
+	ZSpread   float64 `json:"zspread" format:"%.1f"`
+	Duration  float64 `json:"duration"`
+	Convexity float64 `json:"convexity"`
+	// PVBP is the change of the dirty price for an increase of the rates by
+	// one bp
+	PVBP float64 `json:"pvbp" format:"%.5f"`
+}
+
+// Price values the bond at the settlement date with the term structure plus
+// the spread in bps. The term structure is not modified; bonds maturing on or
+// before the settlement date are rejected.
+func Price(b refdata.Bond, settlement time.Time, ts term.Structure, spread float64) (Valuation, error) {
+	v := Valuation{
+		ID:       b.ID(),
+		Issuer:   b.Issuer,
+		Currency: b.Currency,
+		Maturity: b.Maturity,
+		Coupon:   b.Coupon,
+		Spread:   spread,
+		Quote:    b.Price,
+	}
+	straight, err := b.Straight(settlement)
+	if err != nil {
+		return v, err
+	}
+	if !straight.Maturity.After(settlement) {
+		return v, fmt.Errorf("%s: matured on %s", b.ID(), straight.Maturity.Format(refdata.DateFmt))
+	}
+
+	shifted := &term.Shifted{Base: ts, Spread: spread}
+	v.Dirty = straight.PresentValue(shifted)
+	v.Accrued = straight.Accrued()
+	v.Clean = v.Dirty - v.Accrued
+	v.Duration = straight.Duration(shifted)
+	v.Convexity = straight.Convexity(shifted)
+	v.PVBP = fixedincome.PVBP(straight, shifted)
+
+	invoice := v.Dirty
+	if b.Price > 0.0 {
+		invoice = b.Price + v.Accrued
+		if v.ZSpread, err = fixedincome.Spread(invoice, straight, &term.Shifted{Base: ts}); err != nil {
+			return v, err
+		}
+	}
+	v.Yield, err = fixedincome.Irr(invoice, straight)
+	return v, err
+}
+
+// Spreads contains the spreads in bps by ISIN or issuer of the bonds
+type Spreads map[string]float64
+
+// ParseSpreads parses comma separated spreads by ISIN or issuer, e.g.
+// GKB=35,CH0224396983=20
+func ParseSpreads(s string) (Spreads, error) {
+	spreads := make(Spreads)
+	if strings.TrimSpace(s) == "" {
+		return spreads, nil
+	}
+	for _, field := range strings.Split(s, ",") {
+		pair := strings.SplitN(field, "=", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("invalid spread %s", field)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(pair[1]), 64)
+		if err != nil {
+			return nil, err
+		}
+		spreads[strings.TrimSpace(pair[0])] = value
+	}
+	return spreads, nil
+}
+
+// String returns the spreads in the format of ParseSpreads (flag.Value)
+func (s *Spreads) String() string {
+	if s == nil {
+		return ""
+	}
+	values := []string{}
+	for key, value := range *s {
+		values = append(values, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
+
+// Set replaces the spreads by the parsed value (flag.Value)
+func (s *Spreads) Set(value string) error {
+	spreads, err := ParseSpreads(value)
+	if err != nil {
+		return err
+	}
+	*s = spreads
+	return nil
+}
+
+// Of returns the spread of the bond by ISIN, by issuer or the default spread
+func (s Spreads) Of(b refdata.Bond, spread float64) float64 {
+	if v, ok := s[b.ISIN]; ok && b.ISIN != "" {
+		return v
+	}
+	if v, ok := s[b.Issuer]; ok && b.Issuer != "" {
+		return v
+	}
+	return spread
+}
+
+// Header contains the column names of the valuations in CSV files
+var Header = []string{"id", "issuer", "currency", "maturity", "coupon", "spread", "dirty", "clean", "accrued", "quote", "yield", "zspread", "duration", "convexity", "pvbp"}
+
+// Record returns the fields of the valuation in the order of Header
+func (v Valuation) Record() []string {
+	format := func(x float64) string {
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	return []string{
+		v.ID, v.Issuer, v.Currency, v.Maturity, format(v.Coupon), format(v.Spread),
+		format(v.Dirty), format(v.Clean), format(v.Accrued), format(v.Quote),
+		format(v.Yield), format(v.ZSpread), format(v.Duration), format(v.Convexity), format(v.PVBP),
+	}
+}
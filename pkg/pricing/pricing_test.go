@@ -0,0 +1,115 @@
+package pricing_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/pricing"
+	"github.com/konimarti/fixedincome/pkg/refdata"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+var settlement = time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+func TestPrice(t *testing.T) {
+	ts := &term.Flat{R: 1.0}
+	b := refdata.Bond{ISIN: "CH0224396983", Issuer: "GKB", Coupon: 1.25, Maturity: "2026-05-28"}
+	straight, err := b.Straight(settlement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// without quote
+	v, err := pricing.Price(b, settlement, ts, 50.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spreaded := &term.Flat{R: 1.5}
+	if expected := straight.PresentValue(spreaded); math.Abs(v.Dirty-expected) > 1e-10 {
+		t.Errorf("got dirty price %v, expected %v", v.Dirty, expected)
+	}
+	if math.Abs(v.Clean+v.Accrued-v.Dirty) > 1e-12 || v.Accrued <= 0.0 {
+		t.Errorf("got clean price %v and accrued %v", v.Clean, v.Accrued)
+	}
+	if math.Abs(v.Yield-1.5) > 1e-4 || v.ZSpread != 0.0 {
+		t.Errorf("got yield %v and z-spread %v", v.Yield, v.ZSpread)
+	}
+	if expected := fixedincome.PVBP(straight, spreaded); math.Abs(v.PVBP-expected) > 1e-12 || v.PVBP >= 0.0 {
+		t.Errorf("got PVBP %v, expected %v", v.PVBP, expected)
+	}
+	if v.Duration >= 0.0 || v.Convexity <= 0.0 {
+		t.Errorf("got duration %v and convexity %v", v.Duration, v.Convexity)
+	}
+	if ts.Spread != 0.0 {
+		t.Errorf("spread of term structure changed: %v", ts.Spread)
+	}
+
+	// spread on top of the spread of the term structure
+	ts.SetSpread(20.0)
+	if v, err = pricing.Price(b, settlement, ts, 30.0); err != nil {
+		t.Fatal(err)
+	}
+	if expected := straight.PresentValue(spreaded); math.Abs(v.Dirty-expected) > 1e-10 || ts.Spread != 20.0 {
+		t.Errorf("got dirty price %v and curve spread %v, expected %v and 20", v.Dirty, ts.Spread, expected)
+	}
+	ts.SetSpread(0.0)
+
+	// quoted price
+	b.Price = v.Clean
+	v, err = pricing.Price(b, settlement, ts, 0.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(v.ZSpread-50.0) > 0.01 || math.Abs(v.Yield-1.5) > 1e-4 {
+		t.Errorf("got z-spread %v and yield %v, expected 50 bps and 1.5%%", v.ZSpread, v.Yield)
+	}
+	if len(v.Record()) != len(pricing.Header) {
+		t.Errorf("got %d fields for %d columns", len(v.Record()), len(pricing.Header))
+	}
+
+	for _, b := range []refdata.Bond{{Maturity: "x"}, {Maturity: "2021-04-01"}, {Maturity: "2026-05-28", Basis: "BOGUS"}} {
+		if _, err := pricing.Price(b, settlement, ts, 0.0); err == nil {
+			t.Errorf("expected error for %+v", b)
+		}
+	}
+}
+
+func TestSpreads(t *testing.T) {
+	s := pricing.Spreads{"CH0224396983": 20.0, "GKB": 35.0}
+	testData := []struct {
+		Bond     refdata.Bond
+		Expected float64
+	}{
+		{refdata.Bond{ISIN: "CH0224396983", Issuer: "GKB"}, 20.0},
+		{refdata.Bond{ISIN: "CH0419041165", Issuer: "GKB"}, 35.0},
+		{refdata.Bond{ISIN: "CH0419041165", Issuer: "ZKB"}, 10.0},
+	}
+	for i, test := range testData {
+		if got := s.Of(test.Bond, 10.0); got != test.Expected {
+			t.Errorf("test nr %d: got %v, expected %v", i, got, test.Expected)
+		}
+	}
+}
+
+func TestParseSpreads(t *testing.T) {
+	s, err := pricing.ParseSpreads("GKB=35, CH0224396983 = 20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != 2 || s["GKB"] != 35.0 || s["CH0224396983"] != 20.0 {
+		t.Errorf("got %v", s)
+	}
+	if got := s.String(); got != "CH0224396983=20,GKB=35" {
+		t.Errorf("got %s", got)
+	}
+	if s, err := pricing.ParseSpreads(" "); err != nil || len(s) != 0 {
+		t.Errorf("got %v and error %v for empty spreads", s, err)
+	}
+	for _, value := range []string{"GKB", "GKB=x"} {
+		if err := s.Set(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package refdata
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+)
+
+const DateFmt = "2006-01-02"
+
+// Call is a date at which the issuer can redeem the bond at the call price
+type Call struct {
+	// Date of the call (format: 2006-01-02)
+	Date string `json:"date"`
+	// Price in percent of the face value
+	Price float64 `json:"price"`
+}
+
+// Bond is the reference data of a fixed-coupon bond
+type Bond struct {
+	ISIN     string `json:"isin"`
+	Name     string `json:"name"`
+	Issuer   string `json:"issuer"`
+	Currency string `json:"currency"`
+	// Coupon in percent of the face value
+	Coupon float64 `json:"coupon"`
+	// Frequency of the coupon payments per year (default: 1)
+	Frequency int `json:"frequency"`
+	// Basis is the day count convention (default: 30E360)
+	Basis string `json:"basis"`
+	// FirstCoupon is the date of the first coupon payment (optional, format:
+	// 2006-01-02)
+	FirstCoupon string `json:"firstcoupon,omitempty"`
+	// Maturity date (format: 2006-01-02)
+	Maturity string `json:"maturity"`
+	// Redemption in percent of the face value (default: 100)
+	Redemption float64 `json:"redemption"`
+	// Calls is the call schedule of a callable bond
+	Calls []Call `json:"calls,omitempty"`
+	// AmountIssued is the nominal amount in issue
+	AmountIssued float64 `json:"amountissued"`
+	// Price is the quoted clean price (optional)
+	Price float64 `json:"price,omitempty"`
+}
+
+// ID returns the ISIN or, if missing, the name of the bond
+func (b Bond) ID() string {
+	if b.ISIN != "" {
+		return b.ISIN
+	}
+	return b.Name
+}
+
+// schedule returns the schedule of the bond with the given redemption date
+func (b Bond) schedule(settlement, redemption time.Time) (maturity.Schedule, error) {
+	s := maturity.Schedule{
+		Settlement: settlement,
+		Maturity:   redemption,
+		Frequency:  b.Frequency,
+		Basis:      b.Basis,
+	}
+	if err := maturity.CheckBasis(b.Basis); err != nil {
+		return s, fmt.Errorf("%s: %v", b.ID(), err)
+	}
+	if b.FirstCoupon != "" {
+		first, err := time.Parse(DateFmt, strings.TrimSpace(b.FirstCoupon))
+		if err != nil {
+			return s, fmt.Errorf("%s: first coupon date: %v", b.ID(), err)
+		}
+		s.FirstCoupon = first
+	}
+	return s, nil
+}
+
+// Straight returns the straight bond for the given settlement date
+func (b Bond) Straight(settlement time.Time) (*bond.Straight, error) {
+	maturityDate, err := time.Parse(DateFmt, strings.TrimSpace(b.Maturity))
+	if err != nil {
+		return nil, fmt.Errorf("%s: maturity date: %v", b.ID(), err)
+	}
+	schedule, err := b.schedule(settlement, maturityDate)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.FirstCoupon.After(maturityDate) {
+		return nil, fmt.Errorf("%s: first coupon date %s after maturity date", b.ID(), b.FirstCoupon)
+	}
+	redemption := b.Redemption
+	if redemption == 0.0 {
+		redemption = 100.0
+	}
+	return &bond.Straight{Schedule: schedule, Coupon: b.Coupon, Redemption: redemption}, nil
+}
+
+// ToCalls returns the bond redeemed at each call date after the settlement
+// date as straight bonds with the call price as redemption (e.g. for the
+// yield to worst); the coupon dates are rolled back from the call dates
+func (b Bond) ToCalls(settlement time.Time) ([]*bond.Straight, error) {
+	bonds := []*bond.Straight{}
+	for _, call := range b.Calls {
+		date, err := time.Parse(DateFmt, strings.TrimSpace(call.Date))
+		if err != nil {
+			return nil, fmt.Errorf("%s: call date: %v", b.ID(), err)
+		}
+		if !date.After(settlement) {
+			continue
+		}
+		schedule, err := b.schedule(settlement, date)
+		if err != nil {
+			return nil, err
+		}
+		bonds = append(bonds, &bond.Straight{Schedule: schedule, Coupon: b.Coupon, Redemption: call.Price})
+	}
+	return bonds, nil
+}
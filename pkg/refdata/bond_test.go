@@ -0,0 +1,80 @@
+package refdata_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/refdata"
+)
+
+var settlement = time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+func TestBond_Straight(t *testing.T) {
+	b := refdata.Bond{ISIN: "CH0224396983", Coupon: 1.25, Maturity: "2026-05-28"}
+	s, err := b.Straight(settlement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Coupon != 1.25 || s.Redemption != 100.0 || s.Compounding() != 1 || len(s.M()) != 6 {
+		t.Errorf("wrong bond %+v", s)
+	}
+
+	// first coupon date
+	b = refdata.Bond{Coupon: 3.0, Frequency: 2, Maturity: "2031-06-15", FirstCoupon: "2021-12-15", Redemption: 101.0}
+	s, err = b.Straight(settlement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dates := s.Dates(); len(dates) != 20 || !dates[0].Equal(time.Date(2021, 12, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("wrong coupon dates %v", dates)
+	}
+	if s.Redemption != 101.0 {
+		t.Errorf("got redemption %v, expected 101", s.Redemption)
+	}
+
+	// errors
+	for _, b := range []refdata.Bond{{Maturity: "28.05.2026"}, {Maturity: "2026-05-28", FirstCoupon: "x"}, {Maturity: "2026-05-28", FirstCoupon: "2030-05-28"}, {Maturity: "2026-05-28", Basis: "BOGUS"}} {
+		if _, err := b.Straight(settlement); err == nil {
+			t.Errorf("expected error for %+v", b)
+		}
+	}
+}
+
+func TestBond_ToCalls(t *testing.T) {
+	b := refdata.Bond{
+		ISIN:     "XS0000000001",
+		Coupon:   3.0,
+		Maturity: "2031-06-15",
+		Calls: []refdata.Call{
+			{Date: "2021-03-15", Price: 102.0},
+			{Date: "2026-06-15", Price: 101.0},
+			{Date: "2028-06-15", Price: 100.0},
+		},
+	}
+	calls, err := b.ToCalls(settlement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("got %d calls, expected 2", len(calls))
+	}
+	if calls[0].Redemption != 101.0 || math.Abs(calls[0].Last()-5.2) > 0.01 {
+		t.Errorf("wrong bond to call %+v with maturity %v", calls[0], calls[0].Last())
+	}
+
+	// call before the first coupon is redeemed at the call date
+	b.FirstCoupon = "2027-06-15"
+	b.Calls = []refdata.Call{{Date: "2026-06-15", Price: 101.0}}
+	if calls, err = b.ToCalls(settlement); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || math.Abs(calls[0].Last()-5.2) > 0.01 {
+		t.Errorf("wrong bond to call %+v", calls)
+	}
+
+	b.Calls = []refdata.Call{{Date: "2026/06/15"}}
+	if _, err := b.ToCalls(settlement); err == nil {
+		t.Errorf("expected error for wrong call date")
+	}
+}
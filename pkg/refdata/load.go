@@ -0,0 +1,248 @@
+package refdata
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadJSON reads the bonds from a json array
+func ReadJSON(r io.Reader) ([]Bond, error) {
+	bonds := []Bond{}
+	if err := json.NewDecoder(r).Decode(&bonds); err != nil {
+		return nil, err
+	}
+	return bonds, nil
+}
+
+// readLines reads comma or semicolon separated values
+func readLines(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if strings.Count(string(data), ";") > strings.Count(string(data), ",") {
+		reader.Comma = ';'
+	}
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no header line")
+	}
+	return lines, nil
+}
+
+// ReadCSV reads the bonds from comma or semicolon separated values. The header
+// line names the columns after the json keys of Bond; the calls are given as
+// space separated pairs of date and price (e.g. "2026-05-28:101.0").
+func ReadCSV(r io.Reader) ([]Bond, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	header := lines[0]
+	bonds := []Bond{}
+	for i, line := range lines[1:] {
+		b := Bond{}
+		for j, value := range line {
+			if j >= len(header) {
+				break
+			}
+			if err := b.set(header[j], strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+2, err)
+			}
+		}
+		bonds = append(bonds, b)
+	}
+	return bonds, nil
+}
+
+// set assigns the value to the field of the bond with the json key
+func (b *Bond) set(key, value string) error {
+	var err error
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "isin":
+		b.ISIN = value
+	case "name":
+		b.Name = value
+	case "issuer":
+		b.Issuer = value
+	case "currency":
+		b.Currency = value
+	case "basis":
+		b.Basis = value
+	case "firstcoupon":
+		b.FirstCoupon = value
+	case "maturity":
+		b.Maturity = value
+	case "coupon":
+		b.Coupon, err = parseFloat(value)
+	case "redemption":
+		b.Redemption, err = parseFloat(value)
+	case "amountissued":
+		b.AmountIssued, err = parseFloat(value)
+	case "price":
+		b.Price, err = parseFloat(value)
+	case "frequency":
+		if value != "" {
+			b.Frequency, err = strconv.Atoi(value)
+		}
+	case "calls":
+		b.Calls, err = parseCalls(value)
+	default:
+		return fmt.Errorf("unknown column %s", key)
+	}
+	return err
+}
+
+// parseCalls parses the space separated pairs of call date and price
+func parseCalls(value string) ([]Call, error) {
+	calls := []Call{}
+	for _, field := range strings.Fields(value) {
+		parts := strings.Split(field, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("call %s: expected date:price", field)
+		}
+		price, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("call %s: %v", field, err)
+		}
+		calls = append(calls, Call{Date: parts[0], Price: price})
+	}
+	return calls, nil
+}
+
+func parseFloat(value string) (float64, error) {
+	if value == "" {
+		return 0.0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// SIX contains the columns of the reference data of the SIX Swiss Exchange
+// (fqs/ref.csv) which are read into the fields of the bonds
+var SIX = map[string]string{
+	"ShortName":           "name",
+	"ISIN":                "isin",
+	"IssuerNameShort":     "issuer",
+	"TradingBaseCurrency": "currency",
+	"CouponRate":          "coupon",
+	"MaturityDate":        "maturity",
+	"AmountInIssue":       "amountissued",
+	"ClosingPrice":        "price",
+}
+
+// ReadSIX reads the bonds from the semicolon separated reference data of the
+// SIX Swiss Exchange (fqs/ref.csv) with the header line of the selected
+// columns. The other columns are ignored. The bonds pay annual coupons with
+// the 30E/360 day count convention; bonds without a maturity date (perpetual
+// bonds) are skipped.
+func ReadSIX(r io.Reader) ([]Bond, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	header := lines[0]
+	bonds := []Bond{}
+	for i, line := range lines[1:] {
+		b := Bond{Frequency: 1, Basis: "30E360"}
+		for j, value := range line {
+			if j >= len(header) {
+				break
+			}
+			key, ok := SIX[strings.TrimSpace(header[j])]
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			if key == "maturity" && value != "" {
+				date, err := time.Parse("20060102", value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %v", i+2, err)
+				}
+				value = date.Format(DateFmt)
+			}
+			if err := b.set(key, value); err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+2, err)
+			}
+		}
+		if b.Maturity == "" {
+			continue
+		}
+		bonds = append(bonds, b)
+	}
+	return bonds, nil
+}
+
+// ReadQuotes reads bonds as comma separated values with the maturity date
+// (format: 2006-01-02), coupon, quoted clean price and optionally the issuer
+// without a header line. The bonds pay annual coupons with the 30E/360 day
+// count convention.
+func ReadQuotes(r io.Reader) ([]Bond, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	bonds := []Bond{}
+	for i, line := range records {
+		if len(line) < 3 {
+			return nil, fmt.Errorf("line %d: expected maturity, coupon and price", i+1)
+		}
+		coupon, err := strconv.ParseFloat(strings.TrimSpace(line[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(line[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		b := Bond{
+			Name:      fmt.Sprintf("%s %v", strings.TrimSpace(line[0]), coupon),
+			Coupon:    coupon,
+			Frequency: 1,
+			Basis:     "30E360",
+			Maturity:  strings.TrimSpace(line[0]),
+			Price:     price,
+		}
+		if len(line) > 3 {
+			b.Issuer = strings.TrimSpace(line[3])
+		}
+		bonds = append(bonds, b)
+	}
+	return bonds, nil
+}
+
+// Load reads the bonds from a json (extension .json) or CSV file; CSV files
+// with the MaturityDate column are read as SIX reference data
+func Load(name string) ([]Bond, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if strings.ToLower(filepath.Ext(name)) == ".json" {
+		return ReadJSON(f)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	header := strings.SplitN(string(data), "\n", 2)[0]
+	if strings.Contains(header, "MaturityDate") {
+		return ReadSIX(strings.NewReader(string(data)))
+	}
+	return ReadCSV(strings.NewReader(string(data)))
+}
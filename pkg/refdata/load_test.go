@@ -0,0 +1,107 @@
+package refdata_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/refdata"
+)
+
+func TestReadCSV(t *testing.T) {
+	data := `isin;issuer;currency;coupon;frequency;basis;maturity;calls;amountissued;price
+CH0224396983;GKB;CHF;1.25;1;30E360;2026-05-28;;250000000;104.5
+XS0000000001;Corp;EUR;3.0;2;ACT360;2031-06-15;2026-06-15:101.0 2028-06-15:100.0;;
+`
+	bonds, err := refdata.ReadCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bonds) != 2 {
+		t.Fatalf("got %d bonds, expected 2", len(bonds))
+	}
+	if b := bonds[0]; b.ISIN != "CH0224396983" || b.Coupon != 1.25 || b.AmountIssued != 250000000 || b.Price != 104.5 || len(b.Calls) != 0 {
+		t.Errorf("wrong bond %+v", b)
+	}
+	if b := bonds[1]; b.Frequency != 2 || b.Basis != "ACT360" || len(b.Calls) != 2 || b.Calls[1].Price != 100.0 {
+		t.Errorf("wrong bond %+v", b)
+	}
+
+	for _, data := range []string{"isin,unknown\nA,1\n", "isin,calls\nA,2026-06-15\n", "isin,coupon\nA,x\n", ""} {
+		if _, err := refdata.ReadCSV(strings.NewReader(data)); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+}
+
+func TestReadSIX(t *testing.T) {
+	data := `MaturityDate;CouponRate;ClosingPrice;IssuerNameShort;SpecialFlagDesc
+20260528;1.25;104.5;Graubuendner KB;
+20291115;0;99.1;Graubuendner KB;
+`
+	bonds, err := refdata.ReadSIX(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bonds) != 2 {
+		t.Fatalf("got %d bonds, expected 2", len(bonds))
+	}
+	b := bonds[0]
+	if b.Maturity != "2026-05-28" || b.Coupon != 1.25 || b.Price != 104.5 || b.Issuer != "Graubuendner KB" || b.Frequency != 1 || b.Basis != "30E360" {
+		t.Errorf("wrong bond %+v", b)
+	}
+	if _, err := refdata.ReadSIX(strings.NewReader("MaturityDate\n2026-05-28\n")); err == nil {
+		t.Errorf("expected error for wrong date format")
+	}
+}
+
+func TestReadQuotes(t *testing.T) {
+	data := "2026-05-28,1.25,104.5,GKB\n2029-11-15, 0, 99.1\n"
+	bonds, err := refdata.ReadQuotes(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bonds) != 2 {
+		t.Fatalf("got %d bonds, expected 2", len(bonds))
+	}
+	b := bonds[0]
+	if b.Maturity != "2026-05-28" || b.Coupon != 1.25 || b.Price != 104.5 || b.Issuer != "GKB" || b.Frequency != 1 || b.Basis != "30E360" {
+		t.Errorf("wrong bond %+v", b)
+	}
+	if bonds[1].Name != "2029-11-15 0" || bonds[1].Price != 99.1 {
+		t.Errorf("wrong bond %+v", bonds[1])
+	}
+	for _, data := range []string{"2026-05-28,1.25\n", "2026-05-28,x,104.5\n", "2026-05-28,1.25,x\n"} {
+		if _, err := refdata.ReadQuotes(strings.NewReader(data)); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	testData := []struct {
+		File     string
+		Expected int
+		ISIN     string
+	}{
+		{"testdata/ref.csv", 2, "CH0224396983"},
+		{"testdata/bonds.json", 2, "CH0224396983"},
+	}
+	for _, test := range testData {
+		bonds, err := refdata.Load(test.File)
+		if err != nil {
+			t.Fatalf("%s: %v", test.File, err)
+		}
+		if len(bonds) != test.Expected {
+			t.Fatalf("%s: got %d bonds, expected %d", test.File, len(bonds), test.Expected)
+		}
+		if bonds[0].ISIN != test.ISIN || bonds[0].Currency != "CHF" || bonds[0].AmountIssued != 250000000 {
+			t.Errorf("%s: wrong bond %+v", test.File, bonds[0])
+		}
+		if _, err := bonds[0].Straight(settlement); err != nil {
+			t.Errorf("%s: %v", test.File, err)
+		}
+	}
+	if _, err := refdata.Load("testdata/missing.csv"); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
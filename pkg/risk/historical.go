@@ -0,0 +1,33 @@
+package risk
+
+import (
+	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// Historical calculates the value-at-risk and expected shortfall by
+// historical simulation. The history of term structures (e.g. daily
+// Nelson-Siegel-Svensson fits) is ordered by date and the last entry is the
+// current term structure. The changes of the spot rates over the horizon (in
+// observations) are applied to the current term structure to revalue the
+// security.
+func Historical(s fixedincome.Security, history []term.Structure, horizon int, levels []float64) ([]Result, error) {
+	changes, err := Changes(history, Tenors, horizon)
+	if err != nil {
+		return nil, err
+	}
+	ts := history[len(history)-1]
+	pv, err := fixedincome.Value(s, ts)
+	if err != nil {
+		return nil, err
+	}
+	pnl := make([]float64, len(changes))
+	for i, change := range changes {
+		value, err := fixedincome.Value(s, shifted(ts, Tenors, change))
+		if err != nil {
+			return nil, err
+		}
+		pnl[i] = value - pv
+	}
+	return results(pnl, horizon, levels)
+}
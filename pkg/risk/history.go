@@ -0,0 +1,64 @@
+package risk
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/history"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// LoadHistory reads the term structures from the json files matching the
+// glob pattern (e.g. "history/*.json"). The files are sorted by name, so
+// that names starting with the date (e.g. 2021-04-01.json) give a history
+// ordered by date.
+func LoadHistory(pattern string) ([]term.Structure, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no term structures found for %s", pattern)
+	}
+	sort.Strings(files)
+	history := make([]term.Structure, len(files))
+	for i, name := range files {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if history[i], err = term.Parse(data); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return history, nil
+}
+
+// ReadHistory reads the term structures of the curve name between the dates
+// from and to (format: 2006-01-02) from the curve store in dir or, if dir is
+// empty, from the json files matching the glob pattern (see LoadHistory)
+func ReadHistory(pattern, dir, name, from, to string) ([]term.Structure, error) {
+	if dir == "" {
+		return LoadHistory(pattern)
+	}
+	start, err := time.Parse(history.DateFmt, from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(history.DateFmt, to)
+	if err != nil {
+		return nil, err
+	}
+	store, err := history.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	_, curves := store.Range(name, start, end)
+	if len(curves) == 0 {
+		return nil, fmt.Errorf("no curves %s between %s and %s", name, from, to)
+	}
+	return curves, nil
+}
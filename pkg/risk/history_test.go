@@ -0,0 +1,71 @@
+package risk_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/history"
+	"github.com/konimarti/fixedincome/pkg/risk"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestLoadHistory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"2021-04-02.json": `{"type": "flat", "r": 1.1, "spread": 0}`,
+		"2021-04-01.json": `{"type": "flat", "r": 1.0, "spread": 0}`,
+		"2021-04-06.json": `{"type": "nss", "b0": 1.0, "b1": -1.0, "b2": 0.5, "b3": 0.1, "t1": 2.0, "t2": 5.0, "spread": 0}`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := risk.LoadHistory(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("got %d term structures, expected 3", len(history))
+	}
+	if history[0].Rate(1.0) != 1.0 || history[1].Rate(1.0) != 1.1 {
+		t.Errorf("history not ordered by file name")
+	}
+
+	if _, err := risk.LoadHistory(filepath.Join(dir, "*.csv")); err == nil {
+		t.Errorf("expected error for missing files")
+	}
+}
+
+func TestReadHistory(t *testing.T) {
+	dir := t.TempDir()
+	store, err := history.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range []float64{1.0, 1.1, 1.2} {
+		if err := store.Add("CHF", time.Date(2021, 4, 1+i, 0, 0, 0, 0, time.UTC), &term.Flat{R: r}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	curves, err := risk.ReadHistory("", dir, "CHF", "2021-04-02", "2021-04-30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curves) != 2 || curves[0].Rate(1.0) != 1.1 || curves[1].Rate(1.0) != 1.2 {
+		t.Errorf("got %d curves", len(curves))
+	}
+	if _, err := risk.ReadHistory("", dir, "EUR", "2021-04-01", "2021-04-30"); err == nil {
+		t.Errorf("expected error for unknown curve")
+	}
+	if _, err := risk.ReadHistory("", dir, "CHF", "01.04.2021", "2021-04-30"); err == nil {
+		t.Errorf("expected error for invalid date")
+	}
+	if _, err := risk.ReadHistory(filepath.Join(dir, "CHF", "*.json"), "", "", "", ""); err != nil {
+		t.Errorf("got error %v for json files", err)
+	}
+}
@@ -0,0 +1,105 @@
+package risk
+
+import (
+	"math"
+
+	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/mc"
+	"github.com/konimarti/fixedincome/pkg/mc/model/holee"
+	"github.com/konimarti/fixedincome/pkg/mc/model/vasicek"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// Simulator simulates the changes of the spot rates in bps at the tenors
+// over the horizon in years
+type Simulator interface {
+	Simulate(horizon float64, tenors []float64) []float64
+}
+
+// Vasicek simulates the short rate with the Vasicek model; the changes of
+// the spot rates follow from the model's discount factors
+type Vasicek struct {
+	Model *vasicek.Vasicek
+}
+
+// Simulate implements the Simulator interface
+func (v Vasicek) Simulate(horizon float64, tenors []float64) []float64 {
+	m := v.Model
+	n := int(math.Max(1.0, math.Round(horizon*DaysPerYear)))
+	dt := horizon / float64(n)
+	r := m.R0
+	for i := 0; i < n; i += 1 {
+		r += m.Gamma*(m.Rbar-r)*dt + m.Sigma*math.Sqrt(dt)*m.Rng.NormFloat64()
+	}
+	changes := make([]float64, len(tenors))
+	for j, t := range tenors {
+		changes[j] = (math.Log(m.Z(m.R0, 0.0, t)) - math.Log(m.Z(r, 0.0, t))) / t * 10000.0
+	}
+	return changes
+}
+
+// HoLee simulates the short rate with the Ho-Lee model. The spot rates of all
+// maturities move in parallel with the random part of the short rate.
+type HoLee struct {
+	Model *holee.HoLee
+}
+
+// Simulate implements the Simulator interface
+func (h HoLee) Simulate(horizon float64, tenors []float64) []float64 {
+	shock := h.Model.Sigma * math.Sqrt(horizon) * h.Model.Rng.NormFloat64() * 10000.0
+	changes := make([]float64, len(tenors))
+	for j := range changes {
+		changes[j] = shock
+	}
+	return changes
+}
+
+// scenario implements the model interface for the Monte Carlo engine and
+// measures the change in value of the security for a simulated term structure
+type scenario struct {
+	security fixedincome.Security
+	ts       term.Structure
+	sim      Simulator
+	horizon  float64
+	pv       float64
+	// err is the first valuation error of the simulations
+	err error
+}
+
+// Measurement implements the model interface for the Monte Carlo engine
+func (s *scenario) Measurement() float64 {
+	changes := s.sim.Simulate(s.horizon, Tenors)
+	value, err := fixedincome.Value(s.security, shifted(s.ts, Tenors, changes))
+	if err != nil {
+		if s.err == nil {
+			s.err = err
+		}
+		return math.NaN()
+	}
+	return value - s.pv
+}
+
+// MonteCarlo calculates the value-at-risk and expected shortfall with nsim
+// simulations of the short-rate model over the horizon in trading days. The
+// simulated changes of the spot rates are applied to the term structure.
+func MonteCarlo(s fixedincome.Security, ts term.Structure, sim Simulator, horizon, nsim int, levels []float64) ([]Result, error) {
+	pv, err := fixedincome.Value(s, ts)
+	if err != nil {
+		return nil, err
+	}
+	model := &scenario{
+		security: s,
+		ts:       ts,
+		sim:      sim,
+		horizon:  float64(horizon) / DaysPerYear,
+		pv:       pv,
+	}
+	engine := mc.New(model, nsim)
+	if err := engine.Run(); err != nil {
+		return nil, err
+	}
+	if model.err != nil {
+		return nil, model.err
+	}
+	return results(engine.Estimates, horizon, levels)
+}
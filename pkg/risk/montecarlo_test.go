@@ -0,0 +1,45 @@
+package risk_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/mc/model/holee"
+	"github.com/konimarti/fixedincome/pkg/mc/model/vasicek"
+	"github.com/konimarti/fixedincome/pkg/risk"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestMonteCarlo(t *testing.T) {
+	b := zeroBond(5)
+	ts := &term.Flat{R: 1.0}
+	levels := []float64{0.99}
+
+	// Ho-Lee: parallel shifts with a volatility of 100bp per year
+	hl := risk.HoLee{Model: &holee.HoLee{Sigma: 0.01, Rng: rand.New(rand.NewSource(1))}}
+	results, err := risk.MonteCarlo(b, ts, hl, 10, 20000, levels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shock := 2.326348 * 0.01 * math.Sqrt(10.0/risk.DaysPerYear) * 10000.0
+	expected := b.PresentValue(ts) - b.PresentValue(&term.Flat{R: 1.0 + shock*0.01})
+	if math.Abs(results[0].VaR/expected-1.0) > 0.05 {
+		t.Errorf("Ho-Lee: got VaR %v, expected %v", results[0].VaR, expected)
+	}
+	if results[0].ES <= results[0].VaR {
+		t.Errorf("Ho-Lee: expected shortfall %v not above VaR %v", results[0].ES, results[0].VaR)
+	}
+
+	// Vasicek: mean reversion dampens the changes of the longer rates
+	v := risk.Vasicek{Model: &vasicek.Vasicek{R0: 0.01, Rbar: 0.01, Gamma: 0.5, Sigma: 0.01, Rng: rand.New(rand.NewSource(1))}}
+	results, err = risk.MonteCarlo(b, ts, v, 10, 20000, levels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	damping := (1.0 - math.Exp(-0.5*5.0)) / (0.5 * 5.0)
+	expected = b.PresentValue(ts) - b.PresentValue(&term.Flat{R: 1.0 + damping*shock*0.01})
+	if math.Abs(results[0].VaR/expected-1.0) > 0.05 {
+		t.Errorf("Vasicek: got VaR %v, expected %v", results[0].VaR, expected)
+	}
+}
@@ -0,0 +1,81 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Covariance returns the covariance matrix of the daily changes of the spot
+// rates in bps at the tenors
+func Covariance(history []term.Structure, tenors []float64) ([][]float64, error) {
+	changes, err := Changes(history, tenors, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) < 2 {
+		return nil, fmt.Errorf("at least two changes required")
+	}
+
+	n := len(tenors)
+	mean := make([]float64, n)
+	for _, change := range changes {
+		for j := range change {
+			mean[j] += change[j] / float64(len(changes))
+		}
+	}
+	cov := make([][]float64, n)
+	for i := range cov {
+		cov[i] = make([]float64, n)
+		for j := range cov[i] {
+			for _, change := range changes {
+				cov[i][j] += (change[i] - mean[i]) * (change[j] - mean[j])
+			}
+			cov[i][j] /= float64(len(changes) - 1)
+		}
+	}
+	return cov, nil
+}
+
+// DeltaNormal calculates the parametric value-at-risk and expected shortfall
+// for normally distributed rate changes. The exposures are the changes in
+// value for an increase of the key rates by one basis point (see
+// portfolio.KeyRateExposures) and the covariance matrix contains the daily
+// changes of the key rates in bps (see Covariance). The daily volatility is
+// scaled with the square root of the horizon in days.
+func DeltaNormal(exposures []float64, covariance [][]float64, horizon int, levels []float64) ([]Result, error) {
+	n := len(exposures)
+	if len(covariance) != n {
+		return nil, fmt.Errorf("dimensions of exposures and covariance matrix do not match")
+	}
+	variance := 0.0
+	for i := range exposures {
+		if len(covariance[i]) != n {
+			return nil, fmt.Errorf("covariance matrix is not square")
+		}
+		for j := range exposures {
+			variance += exposures[i] * covariance[i][j] * exposures[j]
+		}
+	}
+	if variance < 0.0 {
+		return nil, fmt.Errorf("covariance matrix is not positive semi-definite")
+	}
+	sigma := math.Sqrt(variance * float64(horizon))
+
+	res := make([]Result, len(levels))
+	for i, level := range levels {
+		if level <= 0.0 || level >= 1.0 {
+			return nil, fmt.Errorf("confidence level %v not in (0,1)", level)
+		}
+		z := distuv.UnitNormal.Quantile(level)
+		res[i] = Result{
+			Confidence: level,
+			Horizon:    horizon,
+			VaR:        sigma * z,
+			ES:         sigma * distuv.UnitNormal.Prob(z) / (1.0 - level),
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,57 @@
+package risk_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/risk"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestCovariance(t *testing.T) {
+	history := []term.Structure{}
+	for _, r := range []float64{1.0, 1.02, 1.0, 1.02, 1.0} {
+		history = append(history, &term.Flat{R: r})
+	}
+	cov, err := risk.Covariance(history, []float64{1, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// changes of +2, -2, +2, -2 bps
+	expected := 16.0 / 3.0
+	for i := range cov {
+		for j := range cov[i] {
+			if math.Abs(cov[i][j]-expected) > 1e-9 {
+				t.Errorf("got cov[%d][%d]=%v, expected %v", i, j, cov[i][j], expected)
+			}
+		}
+	}
+}
+
+func TestDeltaNormal(t *testing.T) {
+	exposures := []float64{-10.0, 5.0}
+	covariance := [][]float64{{4.0, 2.0}, {2.0, 9.0}}
+
+	results, err := risk.DeltaNormal(exposures, covariance, 10, []float64{0.99, 0.975})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// variance = 100*4 - 2*50*2 + 25*9 = 425
+	sigma := math.Sqrt(425.0 * 10.0)
+	testData := []struct {
+		VaR float64
+		ES  float64
+	}{
+		{VaR: 2.326348 * sigma, ES: 2.665214 * sigma},
+		{VaR: 1.959964 * sigma, ES: 2.337803 * sigma},
+	}
+	for i, test := range testData {
+		if math.Abs(results[i].VaR-test.VaR) > 1e-3 || math.Abs(results[i].ES-test.ES) > 1e-3 {
+			t.Errorf("level %v: got %+v, expected %+v", results[i].Confidence, results[i], test)
+		}
+	}
+
+	if _, err := risk.DeltaNormal(exposures, [][]float64{{1.0}}, 1, []float64{0.99}); err == nil {
+		t.Errorf("expected error for wrong dimensions")
+	}
+}
@@ -0,0 +1,91 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+var (
+	// Tenors are the maturities in years on which changes of the term
+	// structures are measured
+	Tenors = []float64{0.25, 0.5, 1, 2, 3, 5, 7, 10, 15, 20, 30}
+	// DaysPerYear is the number of trading days per year
+	DaysPerYear = 252.0
+)
+
+// Result contains the value-at-risk and expected shortfall (as positive
+// losses) for a confidence level (e.g. 0.99) and a horizon in trading days
+type Result struct {
+	Confidence float64
+	Horizon    int
+	VaR        float64
+	ES         float64
+}
+
+// Measures returns the value-at-risk and the expected shortfall of the
+// simulated profits and losses at the confidence level
+func Measures(pnl []float64, confidence float64) (float64, float64, error) {
+	if len(pnl) == 0 {
+		return 0.0, 0.0, fmt.Errorf("no scenarios")
+	}
+	if confidence <= 0.0 || confidence >= 1.0 {
+		return 0.0, 0.0, fmt.Errorf("confidence level %v not in (0,1)", confidence)
+	}
+	losses := make([]float64, len(pnl))
+	for i, value := range pnl {
+		losses[i] = -value
+	}
+	sort.Float64s(losses)
+
+	// number of scenarios in the tail
+	n := int(math.Ceil(float64(len(losses))*(1.0-confidence) - 1e-9))
+	if n < 1 {
+		n = 1
+	}
+	tail := losses[len(losses)-n:]
+	es := 0.0
+	for _, loss := range tail {
+		es += loss
+	}
+	return tail[0], es / float64(n), nil
+}
+
+// results evaluates the risk measures for all confidence levels
+func results(pnl []float64, horizon int, levels []float64) ([]Result, error) {
+	res := make([]Result, len(levels))
+	for i, level := range levels {
+		v, es, err := Measures(pnl, level)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = Result{Confidence: level, Horizon: horizon, VaR: v, ES: es}
+	}
+	return res, nil
+}
+
+// shifted returns the term structure shifted by the changes in bps at the tenors
+func shifted(ts term.Structure, tenors, changes []float64) term.Structure {
+	return &term.BasisSpread{Base: ts, Maturities: tenors, Spreads: changes}
+}
+
+// Changes returns the changes of the spot rates in bps at the tenors between
+// the term structures which are lag observations apart
+func Changes(history []term.Structure, tenors []float64, lag int) ([][]float64, error) {
+	if lag < 1 {
+		return nil, fmt.Errorf("lag must be positive")
+	}
+	if len(history) <= lag {
+		return nil, fmt.Errorf("history of %d term structures too short for lag %d", len(history), lag)
+	}
+	changes := make([][]float64, len(history)-lag)
+	for i := range changes {
+		changes[i] = make([]float64, len(tenors))
+		for j, t := range tenors {
+			changes[i][j] = (history[i+lag].Rate(t) - history[i].Rate(t)) * 100.0
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,101 @@
+package risk_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/mc/model/holee"
+	"github.com/konimarti/fixedincome/pkg/risk"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func zeroBond(years int) *bond.Straight {
+	settlement := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &bond.Straight{
+		Schedule: maturity.Schedule{
+			Settlement: settlement,
+			Maturity:   settlement.AddDate(years, 0, 0),
+			Frequency:  1,
+		},
+		Redemption: 100.0,
+	}
+}
+
+func TestMeasures(t *testing.T) {
+	pnl := make([]float64, 100)
+	for i := range pnl {
+		pnl[i] = -float64(i + 1)
+	}
+	v, es, err := risk.Measures(pnl, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 96.0 || es != 98.0 {
+		t.Errorf("got VaR %v and ES %v, expected 96 and 98", v, es)
+	}
+	if _, _, err := risk.Measures(pnl, 1.0); err == nil {
+		t.Errorf("expected error for confidence level 1.0")
+	}
+}
+
+func TestHistorical(t *testing.T) {
+	// parallel daily moves of the flat curve in bps
+	moves := []float64{5, -3, 10, -8, 2, -12, 7, 1, -4, 6}
+	history := []term.Structure{&term.Flat{R: 1.0}}
+	r := 1.0
+	for _, m := range moves {
+		r += m * 0.01
+		history = append(history, &term.Flat{R: r})
+	}
+
+	b := zeroBond(5)
+	results, err := risk.Historical(b, history, 1, []float64{0.9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// worst move of +10bp
+	ts := history[len(history)-1]
+	expected := b.PresentValue(ts) - b.PresentValue(&term.Flat{R: r + 0.1})
+	if math.Abs(results[0].VaR-expected) > 1e-8 || math.Abs(results[0].ES-expected) > 1e-8 {
+		t.Errorf("got %+v, expected VaR and ES of %v", results[0], expected)
+	}
+}
+
+// unpriced is a security without market data, e.g. a swap without fixings
+type unpriced struct{}
+
+func (unpriced) PresentValue(ts term.Structure) float64 { return math.NaN() }
+
+func (unpriced) Value(ts term.Structure) (float64, error) {
+	return 0.0, fmt.Errorf("fixing missing")
+}
+
+func TestValueError(t *testing.T) {
+	history := []term.Structure{&term.Flat{R: 1.0}, &term.Flat{R: 1.1}, &term.Flat{R: 0.9}}
+	if _, err := risk.Historical(unpriced{}, history, 1, []float64{0.9}); err == nil {
+		t.Errorf("historical: expected error")
+	}
+	hl := risk.HoLee{Model: &holee.HoLee{Sigma: 0.01, Rng: rand.New(rand.NewSource(1))}}
+	if _, err := risk.MonteCarlo(unpriced{}, history[0], hl, 10, 100, []float64{0.9}); err == nil {
+		t.Errorf("Monte Carlo: expected error")
+	}
+}
+
+func TestChanges(t *testing.T) {
+	history := []term.Structure{&term.Flat{R: 1.0}, &term.Flat{R: 1.1}, &term.Flat{R: 0.9}}
+	changes, err := risk.Changes(history, []float64{1, 5}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || math.Abs(changes[0][0]+10.0) > 1e-9 || math.Abs(changes[0][1]+10.0) > 1e-9 {
+		t.Errorf("got %v, expected [[-10 -10]]", changes)
+	}
+	if _, err := risk.Changes(history, []float64{1}, 3); err == nil {
+		t.Errorf("expected error for short history")
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// Curves is a cache of term structures by name which is safe for concurrent
+// use. The curves are kept as json so that every request gets its own term
+// structure (term structures are not safe for concurrent use).
+type Curves struct {
+	mu     sync.RWMutex
+	curves map[string][]byte
+}
+
+// NewCurves returns an empty cache
+func NewCurves() *Curves {
+	return &Curves{
+		curves: make(map[string][]byte),
+	}
+}
+
+// Maturities are the maturities in years at which uploaded curves are
+// evaluated to check them
+var Maturities = []float64{0.25, 1.0, 5.0, 10.0, 30.0}
+
+// Put parses and adds the term structure under the name; it returns true if
+// an existing curve was replaced
+func (c *Curves) Put(name string, data []byte) (bool, error) {
+	if name == "" {
+		return false, fmt.Errorf("curve name missing")
+	}
+	if err := check(data); err != nil {
+		return false, fmt.Errorf("curve %s: %v", name, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.curves[name]
+	c.curves[name] = append([]byte(nil), data...)
+	return ok, nil
+}
+
+// check returns an error if the term structure cannot be parsed or evaluated
+// at the maturities, e.g. for inconsistent parameters
+func check(data []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid term structure: %v", r)
+		}
+	}()
+	ts, err := term.Parse(data)
+	if err != nil {
+		return err
+	}
+	for _, t := range Maturities {
+		if z := ts.Z(t); math.IsNaN(z) || math.IsInf(z, 0) || z <= 0.0 {
+			return fmt.Errorf("invalid discount factor %v for maturity %v", z, t)
+		}
+	}
+	return nil
+}
+
+// Get returns a new instance of the term structure with the name
+func (c *Curves) Get(name string) (term.Structure, bool) {
+	data, ok := c.JSON(name)
+	if !ok {
+		return nil, false
+	}
+	ts, err := term.Parse(data)
+	if err != nil {
+		return nil, false
+	}
+	return ts, true
+}
+
+// JSON returns the json of the term structure with the name
+func (c *Curves) JSON(name string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	data, ok := c.curves[name]
+	return data, ok
+}
+
+// Delete removes the curve and returns false if it does not exist
+func (c *Curves) Delete(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.curves[name]
+	delete(c.curves, name)
+	return ok
+}
+
+// Names returns the sorted names of the curves
+func (c *Curves) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := []string{}
+	for name := range c.curves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
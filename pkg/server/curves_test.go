@@ -0,0 +1,74 @@
+package server_test
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/server"
+)
+
+func TestCurves(t *testing.T) {
+	curves := server.NewCurves()
+	if _, err := curves.Put("CHF", []byte(`{"unknown": 1}`)); err == nil {
+		t.Errorf("expected error for invalid curve")
+	}
+	if _, err := curves.Put("CHF", []byte(`{"type": "spline", "maturities": [1, 2], "discountfactors": [0.99]}`)); err == nil {
+		t.Errorf("expected error for inconsistent curve")
+	}
+	if _, err := curves.Put("", []byte(flat)); err == nil {
+		t.Errorf("expected error for missing name")
+	}
+	replaced, err := curves.Put("CHF", []byte(flat))
+	if err != nil || replaced {
+		t.Fatalf("got replaced %v and error %v", replaced, err)
+	}
+	if replaced, err = curves.Put("CHF", []byte(flat)); err != nil || !replaced {
+		t.Fatalf("got replaced %v and error %v", replaced, err)
+	}
+
+	// every lookup returns a new instance
+	ts, ok := curves.Get("CHF")
+	if !ok {
+		t.Fatal("curve CHF not found")
+	}
+	ts.SetSpread(100.0)
+	other, _ := curves.Get("CHF")
+	if math.Abs(other.Rate(1.0)-1.0) > 1e-12 {
+		t.Errorf("got rate %v, spread of other instance leaked", other.Rate(1.0))
+	}
+
+	if names := curves.Names(); len(names) != 1 || names[0] != "CHF" {
+		t.Errorf("got names %v", names)
+	}
+	if !curves.Delete("CHF") || curves.Delete("CHF") {
+		t.Errorf("delete failed")
+	}
+	if _, ok := curves.Get("CHF"); ok {
+		t.Errorf("deleted curve found")
+	}
+}
+
+func TestCurvesConcurrent(t *testing.T) {
+	curves := server.NewCurves()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("C%d", i%2)
+			for j := 0; j < 50; j++ {
+				curves.Put(name, []byte(flat))
+				if ts, ok := curves.Get(name); ok {
+					ts.SetSpread(float64(j))
+				}
+				curves.Names()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := len(curves.Names()); n != 2 {
+		t.Errorf("got %d curves, expected 2", n)
+	}
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/fixing"
+	"github.com/konimarti/fixedincome/pkg/instrument/option"
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/refdata"
+)
+
+const DateFmt = "2006-01-02"
+
+// BondRequest prices a fixed-coupon bond against a curve
+type BondRequest struct {
+	// Curve is the name of the cached term structure
+	Curve string `json:"curve"`
+	// Settlement is the valuation date (format: 2006-01-02)
+	Settlement string `json:"settlement"`
+	// Spread in bps over the term structure
+	Spread float64      `json:"spread"`
+	Bond   refdata.Bond `json:"bond"`
+}
+
+// Validate checks the request and returns the settlement date
+func (r BondRequest) Validate() (time.Time, error) {
+	settlement, err := validate(r.Curve, r.Settlement)
+	if err != nil {
+		return settlement, err
+	}
+	if r.Bond.Maturity == "" {
+		return settlement, fmt.Errorf("bond: maturity missing")
+	}
+	maturityDate, err := time.Parse(DateFmt, r.Bond.Maturity)
+	if err != nil {
+		return settlement, fmt.Errorf("bond: maturity date: %v", err)
+	}
+	if !maturityDate.After(settlement) {
+		return settlement, fmt.Errorf("bond: matured on %s", r.Bond.Maturity)
+	}
+	if err := maturity.CheckBasis(r.Bond.Basis); err != nil {
+		return settlement, fmt.Errorf("bond: %v", err)
+	}
+	if r.Bond.Frequency < 0 || r.Bond.Frequency > 12 {
+		return settlement, fmt.Errorf("bond: invalid frequency %d", r.Bond.Frequency)
+	}
+	if r.Bond.Price < 0.0 {
+		return settlement, fmt.Errorf("bond: invalid price %v", r.Bond.Price)
+	}
+	return settlement, nil
+}
+
+// LegRequest describes a fixed or floating leg of a swap
+type LegRequest struct {
+	// Type is either fixed or floating
+	Type string `json:"type"`
+	// Direction is either receive or pay
+	Direction string `json:"direction"`
+	// Effective and Maturity are the start and end dates of the leg (format:
+	// 2006-01-02)
+	Effective string `json:"effective"`
+	Maturity  string `json:"maturity"`
+	// Frequency is the number of payments per year (default: 1)
+	Frequency int `json:"frequency"`
+	// Basis is the day count convention (default: 30E360)
+	Basis            string    `json:"basis"`
+	Notional         float64   `json:"notional"`
+	Notionals        []float64 `json:"notionals,omitempty"`
+	NotionalExchange bool      `json:"notionalexchange"`
+	// Rate is the fixed rate in percent of a fixed leg
+	Rate float64 `json:"rate"`
+	// Spread in bps on the floating rate of a floating leg
+	Spread float64 `json:"spread"`
+	// Fixings are the floating rates in percent by fixing date (format:
+	// 2006-01-02) for the periods started before the settlement date
+	Fixings map[string]float64 `json:"fixings,omitempty"`
+}
+
+// directions maps the direction of a leg to the swap directions
+var directions = map[string]int{
+	"receive": swap.Receive,
+	"pay":     swap.Pay,
+}
+
+// Leg returns the swap leg of the request
+func (l LegRequest) Leg() (swap.Leg, error) {
+	direction, ok := directions[strings.ToLower(l.Direction)]
+	if !ok {
+		return nil, fmt.Errorf("unknown direction %q", l.Direction)
+	}
+	effective, err := time.Parse(DateFmt, l.Effective)
+	if err != nil {
+		return nil, fmt.Errorf("effective date: %v", err)
+	}
+	maturityDate, err := time.Parse(DateFmt, l.Maturity)
+	if err != nil {
+		return nil, fmt.Errorf("maturity date: %v", err)
+	}
+	if !maturityDate.After(effective) {
+		return nil, fmt.Errorf("maturity %s not after effective date %s", l.Maturity, l.Effective)
+	}
+	if l.Frequency < 0 || l.Frequency > 12 {
+		return nil, fmt.Errorf("invalid frequency %d", l.Frequency)
+	}
+	if err := maturity.CheckBasis(l.Basis); err != nil {
+		return nil, err
+	}
+	schedule := swap.LegSchedule{
+		Direction:        direction,
+		Effective:        effective,
+		Maturity:         maturityDate,
+		Frequency:        l.Frequency,
+		Basis:            l.Basis,
+		Notional:         l.Notional,
+		Notionals:        l.Notionals,
+		NotionalExchange: l.NotionalExchange,
+	}
+	if n := len(schedule.Periods()); len(l.Notionals) > 0 && len(l.Notionals) != n {
+		return nil, fmt.Errorf("%d notionals for %d periods", len(l.Notionals), n)
+	}
+
+	switch strings.ToLower(l.Type) {
+	case "fixed":
+		return &swap.FixedLeg{LegSchedule: schedule, Rate: l.Rate}, nil
+	case "floating":
+		leg := &swap.FloatingLeg{LegSchedule: schedule, Spread: l.Spread, Index: "index"}
+		if len(l.Fixings) > 0 {
+			leg.Fixings = fixing.NewStore()
+			for date, rate := range l.Fixings {
+				d, err := time.Parse(DateFmt, date)
+				if err != nil {
+					return nil, fmt.Errorf("fixing date: %v", err)
+				}
+				leg.Fixings.Add(leg.Index, d, rate)
+			}
+		}
+		return leg, nil
+	}
+	return nil, fmt.Errorf("unknown leg type %q", l.Type)
+}
+
+// SwapRequest values a swap with two or more legs against a curve
+type SwapRequest struct {
+	// Curve is the name of the cached term structure
+	Curve string `json:"curve"`
+	// Settlement is the valuation date (format: 2006-01-02)
+	Settlement string       `json:"settlement"`
+	Legs       []LegRequest `json:"legs"`
+}
+
+// Swap validates the request and returns the swap
+func (r SwapRequest) Swap() (*swap.Swap, error) {
+	settlement, err := validate(r.Curve, r.Settlement)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Legs) < 2 {
+		return nil, fmt.Errorf("swap needs at least two legs, got %d", len(r.Legs))
+	}
+	s := &swap.Swap{Settlement: settlement}
+	for i, l := range r.Legs {
+		leg, err := l.Leg()
+		if err != nil {
+			return nil, fmt.Errorf("leg %d: %v", i, err)
+		}
+		s.Legs = append(s.Legs, leg)
+	}
+	return s, nil
+}
+
+// OptionRequest prices a European option; the discount rate is taken from
+// the curve or, if no curve is given, from the flat rate
+type OptionRequest struct {
+	// Curve is the name of the cached term structure (optional)
+	Curve string `json:"curve,omitempty"`
+	// Rate is the flat discount rate in percent (continuously compounded)
+	Rate float64 `json:"r"`
+	// Type is either call or put
+	Type string  `json:"type"`
+	S    float64 `json:"s"`
+	K    float64 `json:"k"`
+	T    float64 `json:"t"`
+	Q    float64 `json:"q"`
+	Vola float64 `json:"vola"`
+}
+
+// optionTypes maps the type of the request to the option types
+var optionTypes = map[string]int{
+	"call": option.Call,
+	"put":  option.Put,
+}
+
+// Option validates the request and returns the option
+func (r OptionRequest) Option() (*option.European, error) {
+	optionType, ok := optionTypes[strings.ToLower(r.Type)]
+	if !ok {
+		return nil, fmt.Errorf("unknown option type %q", r.Type)
+	}
+	if r.S <= 0.0 || r.K <= 0.0 {
+		return nil, fmt.Errorf("stock price and strike must be positive")
+	}
+	if r.T <= 0.0 {
+		return nil, fmt.Errorf("invalid time to expiration %v", r.T)
+	}
+	if r.Vola <= 0.0 {
+		return nil, fmt.Errorf("invalid volatility %v", r.Vola)
+	}
+	return &option.European{Type: optionType, S: r.S, K: r.K, T: r.T, Q: r.Q, Vola: r.Vola}, nil
+}
+
+// validate checks the curve name and returns the settlement date
+func validate(curve, settlement string) (time.Time, error) {
+	if curve == "" {
+		return time.Time{}, fmt.Errorf("curve missing")
+	}
+	date, err := time.Parse(DateFmt, settlement)
+	if err != nil {
+		return date, fmt.Errorf("settlement date: %v", err)
+	}
+	return date, nil
+}
@@ -0,0 +1,104 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+	"github.com/konimarti/fixedincome/pkg/refdata"
+	"github.com/konimarti/fixedincome/pkg/server"
+)
+
+func TestBondRequest(t *testing.T) {
+	valid := server.BondRequest{
+		Curve:      "CHF",
+		Settlement: "2021-04-01",
+		Bond:       refdata.Bond{Coupon: 1.25, Maturity: "2026-05-28"},
+	}
+	if _, err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	invalid := []func(r *server.BondRequest){
+		func(r *server.BondRequest) { r.Curve = "" },
+		func(r *server.BondRequest) { r.Settlement = "01.04.2021" },
+		func(r *server.BondRequest) { r.Bond.Maturity = "" },
+		func(r *server.BondRequest) { r.Bond.Frequency = 24 },
+		func(r *server.BondRequest) { r.Bond.Price = -1.0 },
+	}
+	for i, change := range invalid {
+		r := valid
+		change(&r)
+		if _, err := r.Validate(); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestSwapRequest(t *testing.T) {
+	r := server.SwapRequest{
+		Curve:      "CHF",
+		Settlement: "2021-04-01",
+		Legs: []server.LegRequest{
+			{Type: "fixed", Direction: "receive", Effective: "2021-04-01", Maturity: "2026-04-01", Notional: 100.0, Rate: 1.0},
+			{Type: "Floating", Direction: "PAY", Effective: "2021-04-01", Maturity: "2026-04-01", Frequency: 2, Notional: 100.0,
+				Fixings: map[string]float64{"2021-04-01": 0.5}},
+		},
+	}
+	s, err := r.Swap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fixed, ok := s.Legs[0].(*swap.FixedLeg); !ok || fixed.Rate != 1.0 || fixed.Sign() != 1.0 {
+		t.Errorf("got first leg %+v", s.Legs[0])
+	}
+	floating, ok := s.Legs[1].(*swap.FloatingLeg)
+	if !ok || floating.Sign() != -1.0 || floating.Fixings == nil {
+		t.Fatalf("got second leg %+v", s.Legs[1])
+	}
+
+	invalid := []func(l *server.LegRequest){
+		func(l *server.LegRequest) { l.Type = "overnight" },
+		func(l *server.LegRequest) { l.Direction = "" },
+		func(l *server.LegRequest) { l.Maturity = "2020-04-01" },
+		func(l *server.LegRequest) { l.Effective = "" },
+		func(l *server.LegRequest) { l.Frequency = 13 },
+		func(l *server.LegRequest) { l.Basis = "BOGUS" },
+		func(l *server.LegRequest) { l.Notionals = []float64{100.0, 50.0} },
+		func(l *server.LegRequest) { l.Type = "floating"; l.Fixings = map[string]float64{"today": 0.5} },
+	}
+	for i, change := range invalid {
+		l := r.Legs[0]
+		change(&l)
+		if _, err := l.Leg(); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+
+	r.Legs = r.Legs[:1]
+	if _, err := r.Swap(); err == nil {
+		t.Errorf("expected error for a single leg")
+	}
+}
+
+func TestOptionRequest(t *testing.T) {
+	valid := server.OptionRequest{Type: "put", S: 100.0, K: 95.0, T: 0.5, Vola: 0.2}
+	o, err := valid.Option()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.K != 95.0 || o.T != 0.5 {
+		t.Errorf("got option %+v", o)
+	}
+	invalid := []func(r *server.OptionRequest){
+		func(r *server.OptionRequest) { r.Type = "straddle" },
+		func(r *server.OptionRequest) { r.S = 0.0 },
+		func(r *server.OptionRequest) { r.T = -1.0 },
+		func(r *server.OptionRequest) { r.Vola = 0.0 },
+	}
+	for i, change := range invalid {
+		r := valid
+		change(&r)
+		if _, err := r.Option(); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
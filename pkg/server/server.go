@@ -0,0 +1,326 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+	"github.com/konimarti/fixedincome/pkg/pricing"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// MaxBytes is the maximum size of a request body
+var MaxBytes int64 = 1 << 20
+
+// Server serves the curves and the pricing of bonds, swaps and options as
+// json over HTTP:
+//
+//	GET    /curves              names of the cached curves
+//	GET    /curves/<name>       curve as json (or rates with ?t=1,2,5)
+//	PUT    /curves/<name>       uploads the curve (json of term.Parse)
+//	DELETE /curves/<name>       removes the curve
+//	POST   /bond                BondRequest -> pricing.Valuation
+//	POST   /swap                SwapRequest -> SwapValuation
+//	POST   /option              OptionRequest -> OptionValuation
+//
+// Errors are returned as Error with the HTTP status code (500 for panics).
+type Server struct {
+	Curves *Curves
+	mux    *http.ServeMux
+}
+
+// New returns the server for the cached curves
+func New(curves *Curves) *Server {
+	s := &Server{Curves: curves, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/curves", s.names)
+	s.mux.HandleFunc("/curves/", s.curve)
+	s.mux.HandleFunc("/bond", s.bond)
+	s.mux.HandleFunc("/swap", s.swap)
+	s.mux.HandleFunc("/option", s.option)
+	return s
+}
+
+// ServeHTTP dispatches the request; panics of the handlers are logged and
+// answered with an internal server error
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if v := recover(); v != nil {
+			log.Printf("%s %s: %v", r.Method, r.URL.Path, v)
+			fail(w, http.StatusInternalServerError, fmt.Errorf("internal error: %v", v))
+		}
+	}()
+	s.mux.ServeHTTP(w, r)
+}
+
+// Error is the response of a failed request
+type Error struct {
+	Error string `json:"error"`
+}
+
+// Point contains the spot rate in percent and the discount factor of a curve
+// for the maturity in years
+type Point struct {
+	Maturity float64 `json:"maturity"`
+	Rate     float64 `json:"rate"`
+	Discount float64 `json:"discount"`
+}
+
+// SwapValuation contains the present value and the risk figures of a swap
+type SwapValuation struct {
+	PV float64 `json:"pv"`
+	// Legs contains the present values of the legs (negative for paying legs)
+	Legs []float64 `json:"legs"`
+	// ParRate is the rate in percent of the first fixed leg and ParSpread
+	// the spread in bps of the first floating leg that set the value to zero
+	ParRate   float64 `json:"parrate"`
+	ParSpread float64 `json:"parspread"`
+	// PVBP is the change of the present value for an increase of the rates
+	// by one bp
+	PVBP float64 `json:"pvbp"`
+}
+
+// OptionValuation contains the price and the 'Greeks' of an option
+type OptionValuation struct {
+	Price float64 `json:"price"`
+	Delta float64 `json:"delta"`
+	Gamma float64 `json:"gamma"`
+	Rho   float64 `json:"rho"`
+	Vega  float64 `json:"vega"`
+}
+
+// names returns the names of the curves
+func (s *Server) names(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	reply(w, http.StatusOK, s.Curves.Names())
+}
+
+// curve handles the upload, lookup and removal of a curve
+func (s *Server) curve(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/curves/")
+	if name == "" || strings.Contains(name, "/") {
+		fail(w, http.StatusNotFound, fmt.Errorf("invalid curve name %q", name))
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		s.lookup(w, r, name)
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxBytes))
+		if err != nil {
+			fail(w, http.StatusBadRequest, err)
+			return
+		}
+		replaced, err := s.Curves.Put(name, data)
+		if err != nil {
+			fail(w, http.StatusBadRequest, err)
+			return
+		}
+		status := http.StatusCreated
+		if replaced {
+			status = http.StatusOK
+		}
+		reply(w, status, map[string]string{"curve": name})
+	case http.MethodDelete:
+		if !s.Curves.Delete(name) {
+			fail(w, http.StatusNotFound, fmt.Errorf("unknown curve %s", name))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		fail(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+// lookup returns the curve as json or the rates for the maturities given by
+// the query parameter t
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request, name string) {
+	maturities := r.URL.Query().Get("t")
+	if maturities == "" {
+		data, ok := s.Curves.JSON(name)
+		if !ok {
+			fail(w, http.StatusNotFound, fmt.Errorf("unknown curve %s", name))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return
+	}
+	ts, ok := s.Curves.Get(name)
+	if !ok {
+		fail(w, http.StatusNotFound, fmt.Errorf("unknown curve %s", name))
+		return
+	}
+	points := []Point{}
+	for _, field := range strings.Split(maturities, ",") {
+		t, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || t <= 0.0 {
+			fail(w, http.StatusBadRequest, fmt.Errorf("invalid maturity %q", field))
+			return
+		}
+		points = append(points, Point{t, ts.Rate(t), ts.Z(t)})
+	}
+	reply(w, http.StatusOK, points)
+}
+
+// bond prices a bond
+func (s *Server) bond(w http.ResponseWriter, r *http.Request) {
+	var req BondRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	settlement, err := req.Validate()
+	if err != nil {
+		fail(w, http.StatusBadRequest, err)
+		return
+	}
+	ts, ok := s.lookupCurve(w, req.Curve)
+	if !ok {
+		return
+	}
+	v, err := pricing.Price(req.Bond, settlement, ts, req.Spread)
+	if err != nil {
+		fail(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	reply(w, http.StatusOK, v)
+}
+
+// swap values a swap
+func (s *Server) swap(w http.ResponseWriter, r *http.Request) {
+	var req SwapRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	sw, err := req.Swap()
+	if err != nil {
+		fail(w, http.StatusBadRequest, err)
+		return
+	}
+	ts, ok := s.lookupCurve(w, req.Curve)
+	if !ok {
+		return
+	}
+	v, err := valueSwap(sw, ts)
+	if err != nil {
+		fail(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	reply(w, http.StatusOK, v)
+}
+
+// valueSwap returns the valuation of the swap
+func valueSwap(sw *swap.Swap, ts term.Structure) (SwapValuation, error) {
+	v := SwapValuation{Legs: []float64{}}
+	fixed, floating := false, false
+	for i, leg := range sw.Legs {
+		value, err := sw.LegValue(i, ts)
+		if err != nil {
+			return v, fmt.Errorf("leg %d: %v", i, err)
+		}
+		v.Legs = append(v.Legs, value)
+		v.PV += value
+
+		switch leg.(type) {
+		case *swap.FixedLeg:
+			if !fixed {
+				if v.ParRate, err = sw.ParRate(i, ts); err != nil {
+					return v, fmt.Errorf("leg %d: %v", i, err)
+				}
+				fixed = true
+			}
+		case *swap.FloatingLeg:
+			if !floating {
+				if v.ParSpread, err = sw.ParSpread(i, ts); err != nil {
+					return v, fmt.Errorf("leg %d: %v", i, err)
+				}
+				floating = true
+			}
+		}
+	}
+	shifted, err := sw.Value(&term.Shifted{Base: ts, Spread: 1.0})
+	if err != nil {
+		return v, err
+	}
+	v.PVBP = shifted - v.PV
+	return v, nil
+}
+
+// option prices a European option
+func (s *Server) option(w http.ResponseWriter, r *http.Request) {
+	var req OptionRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	o, err := req.Option()
+	if err != nil {
+		fail(w, http.StatusBadRequest, err)
+		return
+	}
+	var ts term.Structure = &term.Flat{R: req.Rate}
+	if req.Curve != "" {
+		var ok bool
+		if ts, ok = s.lookupCurve(w, req.Curve); !ok {
+			return
+		}
+	}
+	reply(w, http.StatusOK, OptionValuation{
+		Price: o.PresentValue(ts),
+		Delta: o.Delta(ts),
+		Gamma: o.Gamma(ts),
+		Rho:   o.Rho(ts),
+		Vega:  o.Vega(ts),
+	})
+}
+
+// lookupCurve returns the curve or replies with not found
+func (s *Server) lookupCurve(w http.ResponseWriter, name string) (term.Structure, bool) {
+	ts, ok := s.Curves.Get(name)
+	if !ok {
+		fail(w, http.StatusNotFound, fmt.Errorf("unknown curve %s", name))
+	}
+	return ts, ok
+}
+
+// allow replies with method not allowed unless the request has the method
+func allow(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	fail(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+// decode reads the json request of a POST into v; unknown fields are errors
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if !allow(w, r, http.MethodPost) {
+		return false
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		fail(w, http.StatusBadRequest, fmt.Errorf("invalid request: %v", err))
+		return false
+	}
+	return true
+}
+
+// reply writes v as json with the status code
+func reply(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// fail replies with the error and the status code
+func fail(w http.ResponseWriter, status int, err error) {
+	reply(w, status, Error{err.Error()})
+}
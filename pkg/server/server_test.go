@@ -0,0 +1,301 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome/pkg/instrument/option"
+	"github.com/konimarti/fixedincome/pkg/pricing"
+	"github.com/konimarti/fixedincome/pkg/refdata"
+	"github.com/konimarti/fixedincome/pkg/server"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+const flat = `{"type": "flat", "r": 1.0, "spread": 0.0}`
+
+// client is a stand-in for the apps calling the server
+type client struct {
+	url string
+}
+
+// do sends the body as json and decodes the response into v; it returns the
+// status code
+func (c client) do(method, path string, body, v interface{}) (int, error) {
+	var data []byte
+	switch b := body.(type) {
+	case nil:
+	case string:
+		data = []byte(b)
+	default:
+		var err error
+		if data, err = json.Marshal(b); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequest(method, c.url+path, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e server.Error
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == "" {
+			return resp.StatusCode, fmt.Errorf("status %d without error message", resp.StatusCode)
+		}
+		return resp.StatusCode, nil
+	}
+	if v == nil {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(v)
+}
+
+func newClient(t *testing.T) (client, func()) {
+	ts := httptest.NewServer(server.New(server.NewCurves()))
+	c := client{ts.URL}
+	if status, err := c.do(http.MethodPut, "/curves/CHF", flat, nil); err != nil || status != http.StatusCreated {
+		t.Fatalf("upload: got status %d and error %v", status, err)
+	}
+	return c, ts.Close
+}
+
+func TestCurveEndpoints(t *testing.T) {
+	c, done := newClient(t)
+	defer done()
+
+	var names []string
+	if status, err := c.do(http.MethodGet, "/curves", nil, &names); err != nil || status != http.StatusOK || len(names) != 1 {
+		t.Fatalf("got names %v, status %d and error %v", names, status, err)
+	}
+	var curve term.Flat
+	if status, err := c.do(http.MethodGet, "/curves/CHF", nil, &curve); err != nil || status != http.StatusOK || curve.R != 1.0 {
+		t.Fatalf("got curve %+v, status %d and error %v", curve, status, err)
+	}
+	var points []server.Point
+	if status, err := c.do(http.MethodGet, "/curves/CHF?t=1,5", nil, &points); err != nil || status != http.StatusOK || len(points) != 2 {
+		t.Fatalf("got points %v, status %d and error %v", points, status, err)
+	}
+	if p := points[1]; p.Maturity != 5.0 || math.Abs(p.Rate-1.0) > 1e-12 || math.Abs(p.Discount-math.Exp(-0.05)) > 1e-12 {
+		t.Errorf("got point %+v", p)
+	}
+	if status, _ := c.do(http.MethodPut, "/curves/CHF", flat, nil); status != http.StatusOK {
+		t.Errorf("replace: got status %d", status)
+	}
+
+	errors := []struct {
+		method, path string
+		body         interface{}
+		status       int
+	}{
+		{http.MethodGet, "/curves/EUR", nil, http.StatusNotFound},
+		{http.MethodGet, "/curves/CHF?t=x", nil, http.StatusBadRequest},
+		{http.MethodPut, "/curves/EUR", `{"x": 1}`, http.StatusBadRequest},
+		{http.MethodPut, "/curves/EUR", `{"type": "spline", "maturities": [1, 2], "discountfactors": [0.99]}`, http.StatusBadRequest},
+		{http.MethodPut, "/curves/EUR", `{"type": "flat", "r": 1e6}`, http.StatusBadRequest},
+		{http.MethodPost, "/curves/CHF", flat, http.StatusMethodNotAllowed},
+		{http.MethodPost, "/curves", nil, http.StatusMethodNotAllowed},
+	}
+	for _, e := range errors {
+		status, err := c.do(e.method, e.path, e.body, nil)
+		if err != nil || status != e.status {
+			t.Errorf("%s %s: got status %d and error %v, expected %d", e.method, e.path, status, err, e.status)
+		}
+	}
+
+	if status, err := c.do(http.MethodDelete, "/curves/CHF", nil, nil); err != nil || status != http.StatusNoContent {
+		t.Errorf("delete: got status %d and error %v", status, err)
+	}
+	if status, _ := c.do(http.MethodDelete, "/curves/CHF", nil, nil); status != http.StatusNotFound {
+		t.Errorf("delete: got status %d", status)
+	}
+}
+
+func TestBondEndpoint(t *testing.T) {
+	c, done := newClient(t)
+	defer done()
+
+	req := server.BondRequest{
+		Curve:      "CHF",
+		Settlement: "2021-04-01",
+		Spread:     50.0,
+		Bond:       refdata.Bond{ISIN: "CH0224396983", Issuer: "GKB", Coupon: 1.25, Maturity: "2026-05-28"},
+	}
+	var v pricing.Valuation
+	if status, err := c.do(http.MethodPost, "/bond", req, &v); err != nil || status != http.StatusOK {
+		t.Fatalf("got status %d and error %v", status, err)
+	}
+	expected, err := pricing.Price(req.Bond, time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC), &term.Flat{R: 1.0}, 50.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.ID != "CH0224396983" || math.Abs(v.Dirty-expected.Dirty) > 1e-10 || math.Abs(v.Yield-expected.Yield) > 1e-10 {
+		t.Errorf("got valuation %+v, expected %+v", v, expected)
+	}
+	if v.Duration >= 0.0 || v.PVBP >= 0.0 {
+		t.Errorf("got duration %v and PVBP %v", v.Duration, v.PVBP)
+	}
+
+	errors := []struct {
+		body   interface{}
+		status int
+	}{
+		{`{"curve": "CHF", "settlement": "2021-04-01", "bond": {"maturity": "2026-05-28"}, "unknown": 1}`, http.StatusBadRequest},
+		{`{"curve": "CHF"`, http.StatusBadRequest},
+		{server.BondRequest{Curve: "CHF", Bond: req.Bond}, http.StatusBadRequest},
+		{server.BondRequest{Curve: "EUR", Settlement: "2021-04-01", Bond: req.Bond}, http.StatusNotFound},
+		{server.BondRequest{Curve: "CHF", Settlement: "2021-04-01", Bond: refdata.Bond{Maturity: "28.05.2026"}}, http.StatusBadRequest},
+		{server.BondRequest{Curve: "CHF", Settlement: "2021-04-01", Bond: refdata.Bond{Maturity: "2021-04-01"}}, http.StatusBadRequest},
+		{server.BondRequest{Curve: "CHF", Settlement: "2021-04-01", Bond: refdata.Bond{Maturity: "2026-05-28", Basis: "BOGUS"}}, http.StatusBadRequest},
+		{server.BondRequest{Curve: "CHF", Settlement: "2021-04-01", Bond: refdata.Bond{Maturity: "2026-05-28", FirstCoupon: "x"}}, http.StatusUnprocessableEntity},
+	}
+	for i, e := range errors {
+		if status, err := c.do(http.MethodPost, "/bond", e.body, nil); err != nil || status != e.status {
+			t.Errorf("case %d: got status %d and error %v, expected %d", i, status, err, e.status)
+		}
+	}
+	if status, _ := c.do(http.MethodGet, "/bond", nil, nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("got status %d for GET", status)
+	}
+}
+
+func TestSwapEndpoint(t *testing.T) {
+	c, done := newClient(t)
+	defer done()
+
+	req := server.SwapRequest{
+		Curve:      "CHF",
+		Settlement: "2021-04-01",
+		Legs: []server.LegRequest{
+			{Type: "fixed", Direction: "receive", Effective: "2021-04-01", Maturity: "2026-04-01", Notional: 100.0, Rate: 2.0},
+			{Type: "floating", Direction: "pay", Effective: "2021-04-01", Maturity: "2026-04-01", Frequency: 2, Notional: 100.0},
+		},
+	}
+	var v server.SwapValuation
+	if status, err := c.do(http.MethodPost, "/swap", req, &v); err != nil || status != http.StatusOK {
+		t.Fatalf("got status %d and error %v", status, err)
+	}
+	if len(v.Legs) != 2 || math.Abs(v.Legs[0]+v.Legs[1]-v.PV) > 1e-10 || v.Legs[1] >= 0.0 {
+		t.Errorf("got valuation %+v", v)
+	}
+	// receiving 2% above the par rate of about 1%
+	if v.PV <= 0.0 || v.ParRate <= 0.9 || v.ParRate >= 1.1 || v.ParSpread <= 90.0 {
+		t.Errorf("got PV %v, par rate %v and par spread %v", v.PV, v.ParRate, v.ParSpread)
+	}
+	if v.PVBP >= 0.0 {
+		t.Errorf("got PVBP %v for receiver swap", v.PVBP)
+	}
+
+	// at the par rate
+	req.Legs[0].Rate = v.ParRate
+	if _, err := c.do(http.MethodPost, "/swap", req, &v); err != nil || math.Abs(v.PV) > 1e-8 {
+		t.Errorf("got PV %v at par rate and error %v", v.PV, err)
+	}
+
+	// started floating leg without fixings
+	req.Settlement = "2021-06-01"
+	if status, _ := c.do(http.MethodPost, "/swap", req, nil); status != http.StatusUnprocessableEntity {
+		t.Errorf("got status %d without fixings", status)
+	}
+	req.Legs[1].Fixings = map[string]float64{"2021-04-01": 0.5}
+	if status, err := c.do(http.MethodPost, "/swap", req, &v); err != nil || status != http.StatusOK {
+		t.Errorf("got status %d and error %v with fixings", status, err)
+	}
+
+	req.Legs[1].Direction = "sell"
+	if status, _ := c.do(http.MethodPost, "/swap", req, nil); status != http.StatusBadRequest {
+		t.Errorf("got status %d for invalid leg", status)
+	}
+	req.Legs[1].Direction = "pay"
+	req.Legs[1].Basis = "BOGUS"
+	if status, _ := c.do(http.MethodPost, "/swap", req, nil); status != http.StatusBadRequest {
+		t.Errorf("got status %d for unknown basis", status)
+	}
+	req.Legs[1].Basis = ""
+
+	// PVBP on top of the spread of the curve
+	if status, err := c.do(http.MethodPut, "/curves/SPREAD", `{"type": "flat", "r": 1.0, "spread": 50.0}`, nil); err != nil || status != http.StatusCreated {
+		t.Fatalf("upload: got status %d and error %v", status, err)
+	}
+	req.Curve = "SPREAD"
+	if status, err := c.do(http.MethodPost, "/swap", req, &v); err != nil || status != http.StatusOK {
+		t.Fatalf("got status %d and error %v", status, err)
+	}
+	sw, err := req.Swap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := sw.Value(&term.Flat{R: 1.0, Spread: 50.0})
+	bumped, _ := sw.Value(&term.Flat{R: 1.0, Spread: 51.0})
+	if math.Abs(v.PVBP-(bumped-base)) > 1e-10 {
+		t.Errorf("got PVBP %v, expected %v", v.PVBP, bumped-base)
+	}
+}
+
+func TestOptionEndpoint(t *testing.T) {
+	c, done := newClient(t)
+	defer done()
+
+	eu := option.European{Type: option.Call, S: 100.0, K: 95.0, T: 0.5, Q: 0.0, Vola: 0.2}
+	ts := &term.Flat{R: 1.0}
+	expected := eu.PresentValue(ts)
+
+	// flat rate and curve
+	for _, req := range []server.OptionRequest{
+		{Type: "call", S: 100.0, K: 95.0, T: 0.5, Vola: 0.2, Rate: 1.0},
+		{Type: "call", S: 100.0, K: 95.0, T: 0.5, Vola: 0.2, Curve: "CHF"},
+	} {
+		var v server.OptionValuation
+		if status, err := c.do(http.MethodPost, "/option", req, &v); err != nil || status != http.StatusOK {
+			t.Fatalf("got status %d and error %v", status, err)
+		}
+		if math.Abs(v.Price-expected) > 1e-10 || math.Abs(v.Delta-eu.Delta(ts)) > 1e-10 || math.Abs(v.Vega-eu.Vega(ts)) > 1e-10 {
+			t.Errorf("got valuation %+v, expected price %v", v, expected)
+		}
+	}
+
+	if status, _ := c.do(http.MethodPost, "/option", server.OptionRequest{Type: "call", S: 100.0, K: 95.0, T: 0.5}, nil); status != http.StatusBadRequest {
+		t.Errorf("got status %d without volatility", status)
+	}
+	if status, _ := c.do(http.MethodPost, "/option", server.OptionRequest{Type: "call", S: 100.0, K: 95.0, T: 0.5, Vola: 0.2, Curve: "EUR"}, nil); status != http.StatusNotFound {
+		t.Errorf("got status %d for unknown curve", status)
+	}
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	c, done := newClient(t)
+	defer done()
+
+	bond := refdata.Bond{Coupon: 1.25, Maturity: "2026-05-28"}
+	var wg sync.WaitGroup
+	dirty := make([]float64, 16)
+	for i := range dirty {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// alternate spreads on the same curve
+			req := server.BondRequest{Curve: "CHF", Settlement: "2021-04-01", Spread: float64(i%2) * 100.0, Bond: bond}
+			var v pricing.Valuation
+			if _, err := c.do(http.MethodPost, "/bond", req, &v); err != nil {
+				t.Error(err)
+			}
+			dirty[i] = v.Dirty
+		}(i)
+	}
+	wg.Wait()
+	for i := 2; i < len(dirty); i++ {
+		if dirty[i] != dirty[i%2] {
+			t.Errorf("request %d: got dirty price %v, expected %v", i, dirty[i], dirty[i%2])
+		}
+	}
+}
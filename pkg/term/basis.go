@@ -0,0 +1,56 @@
+package term
+
+import "math"
+
+// BasisSpread represents a base term structure shifted by a term structure of
+// spreads (e.g. the cross-currency basis). The spreads in bps are given at
+// pillar maturities and linearly interpolated in between (flat extrapolation).
+type BasisSpread struct {
+	Base       Structure `json:"-"`
+	Maturities []float64 `json:"maturities"`
+	Spreads    []float64 `json:"spreads"`
+	Spread     float64   `json:"spread"`
+}
+
+// SetSpread sets the constant spread in bps on top of the basis spreads
+func (b *BasisSpread) SetSpread(spread float64) Structure {
+	b.Spread = spread
+	return b
+}
+
+// Basis returns the interpolated basis spread in bps for maturity t
+func (b *BasisSpread) Basis(t float64) float64 {
+	return interpolate(b.Maturities, b.Spreads, t)
+}
+
+// Rate returns the continuously compounded spot rate in percent
+func (b *BasisSpread) Rate(t float64) float64 {
+	return b.Base.Rate(t) + (b.Basis(t)+b.Spread)*0.01
+}
+
+// Z returns the discount factor for the given maturity t
+func (b *BasisSpread) Z(t float64) float64 {
+	return math.Exp(-b.Rate(t) * 0.01 * t)
+}
+
+// interpolate returns the linearly interpolated value at x for the nodes
+// (xs, ys) sorted by xs with flat extrapolation
+func interpolate(xs, ys []float64, x float64) float64 {
+	n := len(xs)
+	if n == 0 {
+		return 0.0
+	}
+	if x <= xs[0] {
+		return ys[0]
+	}
+	if x >= xs[n-1] {
+		return ys[n-1]
+	}
+	for i := 1; i < n; i += 1 {
+		if x <= xs[i] {
+			w := (x - xs[i-1]) / (xs[i] - xs[i-1])
+			return ys[i-1] + w*(ys[i]-ys[i-1])
+		}
+	}
+	return ys[n-1]
+}
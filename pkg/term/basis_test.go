@@ -0,0 +1,42 @@
+package term_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestBasisSpread(t *testing.T) {
+	base := term.Flat{R: 1.0}
+	b := term.BasisSpread{
+		Base:       &base,
+		Maturities: []float64{1.0, 5.0},
+		Spreads:    []float64{-10.0, -30.0},
+	}
+
+	testData := []struct {
+		T        float64
+		Expected float64
+	}{
+		{0.5, 0.9},
+		{1.0, 0.9},
+		{3.0, 0.8},
+		{5.0, 0.7},
+		{10.0, 0.7},
+	}
+
+	for i, test := range testData {
+		if got := b.Rate(test.T); math.Abs(got-test.Expected) > 1e-9 {
+			t.Errorf("test nr %d: wrong rate; got: %v, expected: %v", i, got, test.Expected)
+		}
+		if got := b.Z(test.T); math.Abs(got-math.Exp(-test.Expected*0.01*test.T)) > 1e-9 {
+			t.Errorf("test nr %d: wrong discount factor; got: %v", i, got)
+		}
+	}
+
+	b.SetSpread(10.0)
+	if got := b.Rate(3.0); math.Abs(got-0.9) > 1e-9 {
+		t.Errorf("wrong rate with spread; got: %v, expected: %v", got, 0.9)
+	}
+}
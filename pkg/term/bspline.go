@@ -0,0 +1,223 @@
+package term
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Knot placement strategies
+const (
+	// EqualSpacing places the knots at equal distances
+	EqualSpacing int = iota
+	// EqualCount places the knots such that each segment covers the same
+	// number of maturities (McCulloch)
+	EqualCount
+	// LogSpacing places the knots at equal distances of log(1+t) which
+	// yields more knots at the short end
+	LogSpacing
+)
+
+// degree of the B-splines
+const degree = 3
+
+// gauss are the nodes and weights of the two-point Gauss-Legendre quadrature
+// on [0, 1] which integrates cubic polynomials exactly
+var gauss = [2][2]float64{{0.5 - 0.5/math.Sqrt(3.0), 0.5}, {0.5 + 0.5/math.Sqrt(3.0), 0.5}}
+
+// BSpline represents the instantaneous forward rate curve as a cubic B-spline
+// with the given knots. Beyond the last knot the forward rate is extrapolated
+// flat. Call Init before use.
+type BSpline struct {
+	// Knots are the break points in years starting with 0.0
+	Knots []float64 `json:"knots"`
+	// Coefficients of the B-splines in percent (len(Knots)+2)
+	Coefficients []float64 `json:"coefficients"`
+	Spread       float64   `json:"spread"`
+	// clamped knot vector
+	u []float64
+}
+
+// SetSpread sets the spread in bps
+func (b *BSpline) SetSpread(spread float64) Structure {
+	b.Spread = spread
+	return b
+}
+
+// Init builds the clamped knot vector
+func (b *BSpline) Init() error {
+	if len(b.Knots) < 2 {
+		return fmt.Errorf("at least two knots are required")
+	}
+	if b.Knots[0] != 0.0 {
+		return fmt.Errorf("first knot must be 0.0")
+	}
+	for i := 1; i < len(b.Knots); i += 1 {
+		if b.Knots[i] <= b.Knots[i-1] {
+			return fmt.Errorf("knots must be increasing")
+		}
+	}
+	if len(b.Coefficients) != len(b.Knots)+degree-1 {
+		return fmt.Errorf("expected %d coefficients, got %d", len(b.Knots)+degree-1, len(b.Coefficients))
+	}
+	b.u = clamped(b.Knots)
+	return nil
+}
+
+// clamped returns the knot vector with the end points repeated
+func clamped(knots []float64) []float64 {
+	u := []float64{}
+	for i := 0; i < degree; i += 1 {
+		u = append(u, knots[0])
+	}
+	u = append(u, knots...)
+	for i := 0; i < degree; i += 1 {
+		u = append(u, knots[len(knots)-1])
+	}
+	return u
+}
+
+// basis returns the derivatives of the given order of all B-splines of
+// degree p for the knot vector u at x (Cox-de Boor recursion)
+func basis(u []float64, p, order int, x float64) []float64 {
+	m := len(u)
+	q := p - order
+	if q < 0 {
+		return make([]float64, m-p-1)
+	}
+	n := make([]float64, m-1)
+	if x >= u[m-1] {
+		// use the last non-degenerate interval at the right end
+		for i := m - 2; i >= 0; i -= 1 {
+			if u[i] < u[i+1] {
+				n[i] = 1.0
+				break
+			}
+		}
+	} else {
+		for i := 0; i < m-1; i += 1 {
+			if u[i] <= x && x < u[i+1] {
+				n[i] = 1.0
+			}
+		}
+	}
+	for k := 1; k <= p; k += 1 {
+		for i := 0; i < m-1-k; i += 1 {
+			v := 0.0
+			if k <= q {
+				if d := u[i+k] - u[i]; d > 0.0 {
+					v += (x - u[i]) / d * n[i]
+				}
+				if d := u[i+k+1] - u[i+1]; d > 0.0 {
+					v += (u[i+k+1] - x) / d * n[i+1]
+				}
+			} else {
+				if d := u[i+k] - u[i]; d > 0.0 {
+					v += float64(k) / d * n[i]
+				}
+				if d := u[i+k+1] - u[i+1]; d > 0.0 {
+					v -= float64(k) / d * n[i+1]
+				}
+			}
+			n[i] = v
+		}
+	}
+	return n[:m-p-1]
+}
+
+// integrals returns the integrals of the B-splines from 0 to t with a flat
+// extrapolation beyond the last knot
+func (b *BSpline) integrals(t float64) []float64 {
+	if b.u == nil {
+		panic("term structure is not properly initialized")
+	}
+	sum := make([]float64, len(b.Coefficients))
+	last := b.Knots[len(b.Knots)-1]
+	for i := 1; i < len(b.Knots) && b.Knots[i-1] < t; i += 1 {
+		lo, hi := b.Knots[i-1], math.Min(b.Knots[i], t)
+		for _, g := range gauss {
+			for k, v := range basis(b.u, degree, 0, lo+g[0]*(hi-lo)) {
+				sum[k] += g[1] * (hi - lo) * v
+			}
+		}
+	}
+	if t > last {
+		for k, v := range basis(b.u, degree, 0, last) {
+			sum[k] += v * (t - last)
+		}
+	}
+	return sum
+}
+
+// Forward returns the instantaneous forward rate in percent at maturity t
+func (b *BSpline) Forward(t float64) float64 {
+	if b.u == nil {
+		panic("term structure is not properly initialized")
+	}
+	f := 0.0
+	for k, v := range basis(b.u, degree, 0, math.Min(t, b.Knots[len(b.Knots)-1])) {
+		f += b.Coefficients[k] * v
+	}
+	return f + b.Spread*0.01
+}
+
+// Rate returns the continuously compounded spot rate in percent
+func (b *BSpline) Rate(t float64) float64 {
+	if t <= 0.0 {
+		return b.Forward(0.0)
+	}
+	y := 0.0
+	for k, v := range b.integrals(t) {
+		y += b.Coefficients[k] * v
+	}
+	return y/t + b.Spread*0.01
+}
+
+// Z returns the discount factor for the given maturity t
+func (b *BSpline) Z(t float64) float64 {
+	return math.Exp(-b.Rate(t) * 0.01 * t)
+}
+
+// Knots returns the break points from 0 to the longest maturity with n
+// interior knots placed according to the strategy (EqualSpacing, EqualCount
+// or LogSpacing)
+func Knots(maturities []float64, n, strategy int) ([]float64, error) {
+	if len(maturities) == 0 {
+		return nil, fmt.Errorf("no maturities given")
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("number of interior knots must not be negative")
+	}
+	t := make([]float64, len(maturities))
+	copy(t, maturities)
+	sort.Float64s(t)
+	last := t[len(t)-1]
+	if last <= 0.0 {
+		return nil, fmt.Errorf("maturities must be positive")
+	}
+
+	knots := []float64{0.0}
+	for i := 1; i <= n; i += 1 {
+		frac := float64(i) / float64(n+1)
+		var k float64
+		switch strategy {
+		case EqualSpacing:
+			k = frac * last
+		case EqualCount:
+			pos := frac * float64(len(t)-1)
+			j := int(pos)
+			k = t[j]
+			if j < len(t)-1 {
+				k += (pos - float64(j)) * (t[j+1] - t[j])
+			}
+		case LogSpacing:
+			k = math.Exp(frac*math.Log(1.0+last)) - 1.0
+		default:
+			return nil, fmt.Errorf("unknown knot placement strategy %d", strategy)
+		}
+		if k > knots[len(knots)-1] && k < last {
+			knots = append(knots, k)
+		}
+	}
+	return append(knots, last), nil
+}
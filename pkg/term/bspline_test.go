@@ -0,0 +1,74 @@
+package term_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestBSpline(t *testing.T) {
+	// B-splines sum up to one: constant coefficients give a flat curve
+	b := term.BSpline{
+		Knots:        []float64{0, 1, 3, 7, 15, 30},
+		Coefficients: []float64{1.5, 1.5, 1.5, 1.5, 1.5, 1.5, 1.5, 1.5},
+	}
+	if err := b.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range []float64{0.0, 0.5, 2.0, 10.0, 30.0, 50.0} {
+		if math.Abs(b.Forward(x)-1.5) > 1e-12 {
+			t.Errorf("got forward rate %v at %v, expected 1.5", b.Forward(x), x)
+		}
+		if math.Abs(b.Rate(x)-1.5) > 1e-12 {
+			t.Errorf("got rate %v at %v, expected 1.5", b.Rate(x), x)
+		}
+	}
+
+	// spot rates are the average forward rates
+	b.Coefficients = []float64{0.5, 0.8, 1.2, 1.6, 2.0, 2.2, 2.1, 2.0}
+	h := 1e-4
+	sum := 0.0
+	for x := h / 2; x < 12.0; x += h {
+		sum += b.Forward(x) * h
+	}
+	if math.Abs(b.Rate(12.0)-sum/12.0) > 1e-6 {
+		t.Errorf("got rate %v, expected %v", b.Rate(12.0), sum/12.0)
+	}
+
+	b.Coefficients = []float64{1.0}
+	if err := b.Init(); err == nil {
+		t.Errorf("expected error for wrong number of coefficients")
+	}
+}
+
+func TestKnots(t *testing.T) {
+	maturities := []float64{0.5, 1, 1.5, 2, 3, 4, 5, 7, 10, 20}
+	testData := []struct {
+		Strategy int
+		Expected []float64
+	}{
+		{Strategy: term.EqualSpacing, Expected: []float64{0, 5, 10, 15, 20}},
+		{Strategy: term.EqualCount, Expected: []float64{0, 1.625, 3.5, 6.5, 20}},
+		{Strategy: term.LogSpacing, Expected: []float64{0, math.Pow(21, 0.25) - 1, math.Sqrt(21) - 1, math.Pow(21, 0.75) - 1, 20}},
+	}
+	for _, test := range testData {
+		knots, err := term.Knots(maturities, 3, test.Strategy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(knots) != len(test.Expected) {
+			t.Fatalf("strategy %d: got %v, expected %v", test.Strategy, knots, test.Expected)
+		}
+		for i := range knots {
+			if math.Abs(knots[i]-test.Expected[i]) > 1e-9 {
+				t.Errorf("strategy %d: got %v, expected %v", test.Strategy, knots, test.Expected)
+				break
+			}
+		}
+	}
+
+	if _, err := term.Knots(maturities, 3, 99); err == nil {
+		t.Errorf("expected error for unknown strategy")
+	}
+}
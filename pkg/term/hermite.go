@@ -0,0 +1,154 @@
+package term
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Slope methods for the Hermite interpolation
+const (
+	Bessel   = "bessel"
+	Monotone = "monotone"
+)
+
+// Hermite represents the term structure as a cubic Hermite interpolation of
+// the logarithm of the discount factors. With monotone slopes
+// (Fritsch-Carlson) the interpolated log-discount factors do not overshoot
+// between the nodes and the forward rates keep the sign of the discrete
+// forward rates. Call Init before use.
+type Hermite struct {
+	Maturities      []float64 `json:"maturities"`
+	DiscountFactors []float64 `json:"discountfactors"`
+	// Slopes is the method to determine the slopes at the nodes: bessel
+	// (default) or monotone
+	Slopes string  `json:"slopes"`
+	Spread float64 `json:"spread"`
+	// nodes with (0, 0) and the slopes of the log-discount factors
+	t, y, d []float64
+}
+
+// SetSpread sets the spread in bps
+func (h *Hermite) SetSpread(spread float64) Structure {
+	h.Spread = spread
+	return h
+}
+
+// Init sorts the nodes and calculates the slopes
+func (h *Hermite) Init() error {
+	if len(h.Maturities) == 0 || len(h.Maturities) != len(h.DiscountFactors) {
+		return fmt.Errorf("number of maturities and discount factors do not match")
+	}
+	idx := make([]int, len(h.Maturities))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return h.Maturities[idx[i]] < h.Maturities[idx[j]] })
+
+	h.t, h.y = []float64{0.0}, []float64{0.0}
+	for _, i := range idx {
+		if h.Maturities[i] <= h.t[len(h.t)-1] {
+			return fmt.Errorf("maturities must be positive and distinct")
+		}
+		if h.DiscountFactors[i] <= 0.0 {
+			return fmt.Errorf("discount factors must be positive")
+		}
+		h.t = append(h.t, h.Maturities[i])
+		h.y = append(h.y, math.Log(h.DiscountFactors[i]))
+	}
+
+	n := len(h.t)
+	delta := make([]float64, n-1)
+	for i := range delta {
+		delta[i] = (h.y[i+1] - h.y[i]) / (h.t[i+1] - h.t[i])
+	}
+
+	h.d = make([]float64, n)
+	if n == 2 {
+		h.d[0], h.d[1] = delta[0], delta[0]
+		return nil
+	}
+
+	// Bessel (three-point) slopes
+	for i := 1; i < n-1; i += 1 {
+		dl, dr := h.t[i]-h.t[i-1], h.t[i+1]-h.t[i]
+		h.d[i] = (dr*delta[i-1] + dl*delta[i]) / (dl + dr)
+	}
+	h.d[0] = 2.0*delta[0] - h.d[1]
+	h.d[n-1] = 2.0*delta[n-2] - h.d[n-2]
+
+	switch strings.ToLower(h.Slopes) {
+	case "", Bessel:
+	case Monotone:
+		// Fritsch-Carlson: limit the slopes to keep the interpolation monotone
+		for i := 0; i < n; i += 1 {
+			dl, dr := delta[0], delta[n-2]
+			if i > 0 {
+				dl = delta[i-1]
+			}
+			if i < n-1 {
+				dr = delta[i]
+			}
+			if dl*dr <= 0.0 {
+				h.d[i] = 0.0
+				continue
+			}
+			if h.d[i]*dl < 0.0 {
+				h.d[i] = 0.0
+			}
+			limit := 3.0 * math.Min(math.Abs(dl), math.Abs(dr))
+			if math.Abs(h.d[i]) > limit {
+				h.d[i] = math.Copysign(limit, dl)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown slope method %s", h.Slopes)
+	}
+	return nil
+}
+
+// logZ returns the interpolated log-discount factor and its derivative
+func (h *Hermite) logZ(t float64) (float64, float64) {
+	if h.d == nil {
+		panic("term structure is not properly initialized")
+	}
+	n := len(h.t)
+	if t >= h.t[n-1] {
+		// flat forward extrapolation
+		return h.y[n-1] + h.d[n-1]*(t-h.t[n-1]), h.d[n-1]
+	}
+	i := sort.SearchFloat64s(h.t, t)
+	if i > 0 {
+		i -= 1
+	}
+	dt := h.t[i+1] - h.t[i]
+	s := (t - h.t[i]) / dt
+	h00 := 2*s*s*s - 3*s*s + 1
+	h10 := s*s*s - 2*s*s + s
+	h01 := -2*s*s*s + 3*s*s
+	h11 := s*s*s - s*s
+	value := h00*h.y[i] + h10*dt*h.d[i] + h01*h.y[i+1] + h11*dt*h.d[i+1]
+	slope := (6*s*s-6*s)/dt*h.y[i] + (3*s*s-4*s+1)*h.d[i] + (-6*s*s+6*s)/dt*h.y[i+1] + (3*s*s-2*s)*h.d[i+1]
+	return value, slope
+}
+
+// Rate returns the continuously compounded spot rate in percent
+func (h *Hermite) Rate(t float64) float64 {
+	if t <= 0.0 {
+		return h.Forward(0.0)
+	}
+	value, _ := h.logZ(t)
+	return -value/t*100.0 + h.Spread*0.01
+}
+
+// Z returns the discount factor for the given maturity t
+func (h *Hermite) Z(t float64) float64 {
+	return math.Exp(-h.Rate(t) * 0.01 * t)
+}
+
+// Forward returns the instantaneous forward rate in percent at maturity t
+func (h *Hermite) Forward(t float64) float64 {
+	_, slope := h.logZ(t)
+	return -slope*100.0 + h.Spread*0.01
+}
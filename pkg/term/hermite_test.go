@@ -0,0 +1,43 @@
+package term_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestHermite(t *testing.T) {
+	maturities := []float64{0.5, 1, 2, 3, 5, 7, 10, 20}
+	rates := []float64{0.8, 1.0, 1.5, 1.2, 1.8, 2.0, 2.1, 2.0}
+	factors := make([]float64, len(rates))
+	for i, r := range rates {
+		factors[i] = math.Exp(-r * 0.01 * maturities[i])
+	}
+
+	for _, slopes := range []string{term.Bessel, term.Monotone} {
+		h := term.Hermite{Maturities: maturities, DiscountFactors: factors, Slopes: slopes}
+		if err := h.Init(); err != nil {
+			t.Fatal(err)
+		}
+		for i, m := range maturities {
+			if math.Abs(h.Rate(m)-rates[i]) > 1e-9 {
+				t.Errorf("%s: maturity %v: got %v, expected %v", slopes, m, h.Rate(m), rates[i])
+			}
+		}
+
+		// monotone slopes keep the forward rates positive
+		if slopes == term.Monotone {
+			for x := 0.0; x < 25.0; x += 0.01 {
+				if f := h.Forward(x); f < 0.0 {
+					t.Errorf("negative forward rate %v at %v", f, x)
+				}
+			}
+		}
+	}
+
+	h := term.Hermite{Maturities: maturities, DiscountFactors: factors, Slopes: "unknown"}
+	if err := h.Init(); err == nil {
+		t.Errorf("expected error for unknown slope method")
+	}
+}
@@ -0,0 +1,57 @@
+package term
+
+import "math"
+
+// Shapes of the issuer spread curves
+const (
+	// FlatShape is a constant spread: s
+	FlatShape int = iota
+	// LinearShape is a spread linear in the maturity: s0 + s1*t
+	LinearShape
+	// NelsonSiegelShape is a Nelson-Siegel shaped spread with level, slope,
+	// curvature and decay parameter: b0, b1, b2, t1
+	NelsonSiegelShape
+)
+
+// IssuerSpread represents a base term structure (e.g. the government or swap
+// curve) shifted by a parametric term structure of issuer spreads in bps
+type IssuerSpread struct {
+	Base       Structure `json:"-"`
+	Shape      int       `json:"shape"`
+	Parameters []float64 `json:"parameters"`
+	Spread     float64   `json:"spread"`
+}
+
+// SetSpread sets the constant spread in bps on top of the issuer spreads
+func (s *IssuerSpread) SetSpread(spread float64) Structure {
+	s.Spread = spread
+	return s
+}
+
+// Credit returns the issuer spread in bps for maturity t
+func (s *IssuerSpread) Credit(t float64) float64 {
+	p := s.Parameters
+	switch s.Shape {
+	case LinearShape:
+		return p[0] + p[1]*t
+	case NelsonSiegelShape:
+		if t == 0.0 {
+			t = 1e-7
+		}
+		e := math.Exp(-t / p[3])
+		slope := (1.0 - e) * p[3] / t
+		return p[0] + p[1]*slope + p[2]*(slope-e)
+	default:
+		return p[0]
+	}
+}
+
+// Rate returns the continuously compounded spot rate in percent
+func (s *IssuerSpread) Rate(t float64) float64 {
+	return s.Base.Rate(t) + (s.Credit(t)+s.Spread)*0.01
+}
+
+// Z returns the discount factor for the given maturity t
+func (s *IssuerSpread) Z(t float64) float64 {
+	return s.Base.Z(t) * math.Exp(-(s.Credit(t)+s.Spread)*0.0001*t)
+}
@@ -0,0 +1,45 @@
+package term_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestIssuerSpread(t *testing.T) {
+	base := term.NelsonSiegelSvensson{B0: 1.5, B1: -2.0, B2: 2.0, B3: -1.0, T1: 2.0, T2: 6.0}
+
+	testData := []struct {
+		Shape      int
+		Parameters []float64
+		T          float64
+		Expected   float64
+	}{
+		{term.FlatShape, []float64{50.0}, 3.0, 50.0},
+		{term.LinearShape, []float64{20.0, 5.0}, 0.0, 20.0},
+		{term.LinearShape, []float64{20.0, 5.0}, 10.0, 70.0},
+		{term.NelsonSiegelShape, []float64{80.0, -60.0, 0.0, 2.0}, 1e-9, 20.0},
+		{term.NelsonSiegelShape, []float64{80.0, -60.0, 0.0, 2.0}, 1000.0, 79.88},
+	}
+
+	for i, test := range testData {
+		s := term.IssuerSpread{Base: &base, Shape: test.Shape, Parameters: test.Parameters}
+		if got := s.Credit(test.T); math.Abs(got-test.Expected) > 0.01 {
+			t.Errorf("test nr %d: wrong spread; got: %v, expected: %v", i, got, test.Expected)
+		}
+		m := math.Max(test.T, 0.5)
+		if got, expected := s.Rate(m), base.Rate(m)+s.Credit(m)*0.01; math.Abs(got-expected) > 1e-9 {
+			t.Errorf("test nr %d: wrong rate; got: %v, expected: %v", i, got, expected)
+		}
+		if got := s.Z(m); math.Abs(got-math.Exp(-s.Rate(m)*0.01*m)) > 1e-12 {
+			t.Errorf("test nr %d: wrong discount factor; got: %v", i, got)
+		}
+	}
+
+	s := term.IssuerSpread{Base: &base, Parameters: []float64{50.0}}
+	s.SetSpread(10.0)
+	if got, expected := s.Rate(2.0), base.Rate(2.0)+0.6; math.Abs(got-expected) > 1e-9 {
+		t.Errorf("wrong rate with spread; got: %v, expected: %v", got, expected)
+	}
+}
@@ -0,0 +1,185 @@
+package term
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MonotoneConvex represents the term structure interpolated with the
+// monotone convex method of Hagan and West (2006) which preserves the
+// discrete forward rates between the nodes and yields continuous, non-negative
+// (if Positive is set and the discrete forwards are positive) forward rates.
+// Call Init before use.
+// Source: P. Hagan and G. West, Interpolation Methods for Curve Construction,
+// Applied Mathematical Finance, 2006
+type MonotoneConvex struct {
+	// Maturities of the nodes in years in increasing order
+	Maturities []float64 `json:"maturities"`
+	// Rates are the continuously compounded spot rates in percent at the nodes
+	Rates []float64 `json:"rates"`
+	// Positive enforces non-negative instantaneous forward rates
+	Positive bool    `json:"positive"`
+	Spread   float64 `json:"spread"`
+	// discrete and instantaneous forward rates (in decimals)
+	fd, f []float64
+	// times with a leading zero
+	tau []float64
+}
+
+// SetSpread sets the spread in bps
+func (mc *MonotoneConvex) SetSpread(spread float64) Structure {
+	mc.Spread = spread
+	return mc
+}
+
+// Init calculates the discrete and instantaneous forward rates at the nodes
+func (mc *MonotoneConvex) Init() error {
+	n := len(mc.Maturities)
+	if n == 0 || n != len(mc.Rates) {
+		return fmt.Errorf("number of maturities and rates do not match")
+	}
+	mc.tau = append([]float64{0.0}, mc.Maturities...)
+	for i := 1; i <= n; i += 1 {
+		if mc.tau[i] <= mc.tau[i-1] {
+			return fmt.Errorf("maturities must be positive and increasing")
+		}
+	}
+
+	// discrete forwards
+	mc.fd = make([]float64, n+1)
+	y := func(i int) float64 {
+		if i == 0 {
+			return 0.0
+		}
+		return mc.Rates[i-1] * 0.01 * mc.tau[i]
+	}
+	for i := 1; i <= n; i += 1 {
+		mc.fd[i] = (y(i) - y(i-1)) / (mc.tau[i] - mc.tau[i-1])
+	}
+
+	// instantaneous forwards at the nodes
+	mc.f = make([]float64, n+1)
+	for i := 1; i < n; i += 1 {
+		dl, dr := mc.tau[i]-mc.tau[i-1], mc.tau[i+1]-mc.tau[i]
+		mc.f[i] = dl/(dl+dr)*mc.fd[i+1] + dr/(dl+dr)*mc.fd[i]
+	}
+	if n == 1 {
+		mc.f[0], mc.f[1] = mc.fd[1], mc.fd[1]
+	} else {
+		mc.f[0] = mc.fd[1] - 0.5*(mc.f[1]-mc.fd[1])
+		mc.f[n] = mc.fd[n] - 0.5*(mc.f[n-1]-mc.fd[n])
+	}
+
+	if mc.Positive {
+		mc.f[0] = clamp(mc.f[0], 0.0, 2.0*mc.fd[1])
+		for i := 1; i < n; i += 1 {
+			mc.f[i] = clamp(mc.f[i], 0.0, 2.0*math.Min(mc.fd[i], mc.fd[i+1]))
+		}
+		mc.f[n] = clamp(mc.f[n], 0.0, 2.0*mc.fd[n])
+	}
+	return nil
+}
+
+func clamp(x, lo, hi float64) float64 {
+	if hi < lo {
+		return lo
+	}
+	return math.Max(lo, math.Min(hi, x))
+}
+
+// segment returns the index i of the interval (tau[i-1], tau[i]] and the
+// relative position x in the interval
+func (mc *MonotoneConvex) segment(t float64) (int, float64) {
+	n := len(mc.tau) - 1
+	i := sort.SearchFloat64s(mc.tau[1:n], t) + 1
+	return i, (t - mc.tau[i-1]) / (mc.tau[i] - mc.tau[i-1])
+}
+
+// deviation returns the deviation of the instantaneous forward from the
+// discrete forward and its integral from 0 to x for the boundary values g0 and
+// g1
+func deviation(g0, g1, x float64) (float64, float64) {
+	switch {
+	case g0 == 0.0 && g1 == 0.0:
+		return 0.0, 0.0
+
+	case (g0 < 0.0 && -0.5*g0 <= g1 && g1 <= -2.0*g0) || (g0 > 0.0 && -0.5*g0 >= g1 && g1 >= -2.0*g0):
+		// sector (i)
+		value := g0*(1.0-4.0*x+3.0*x*x) + g1*(-2.0*x+3.0*x*x)
+		integral := g0*(x-2.0*x*x+x*x*x) + g1*(-x*x+x*x*x)
+		return value, integral
+
+	case (g0 < 0.0 && g1 > -2.0*g0) || (g0 > 0.0 && g1 < -2.0*g0):
+		// sector (ii)
+		eta := (g1 + 2.0*g0) / (g1 - g0)
+		if x <= eta {
+			return g0, g0 * x
+		}
+		s := (x - eta) / (1.0 - eta)
+		return g0 + (g1-g0)*s*s, g0*x + (g1-g0)*(x-eta)*s*s/3.0
+
+	case (g0 > 0.0 && 0.0 > g1 && g1 > -0.5*g0) || (g0 < 0.0 && 0.0 < g1 && g1 < -0.5*g0):
+		// sector (iii)
+		eta := 3.0 * g1 / (g1 - g0)
+		if x < eta {
+			s := (eta - x) / eta
+			return g1 + (g0-g1)*s*s, g1*x + (g0-g1)*eta/3.0*(1.0-s*s*s)
+		}
+		return g1, g1*x + (g0-g1)*eta/3.0
+
+	default:
+		// sector (iv)
+		eta := g1 / (g1 + g0)
+		a := -g0 * g1 / (g0 + g1)
+		if x <= eta {
+			s := (eta - x) / eta
+			return a + (g0-a)*s*s, a*x + (g0-a)*eta/3.0*(1.0-s*s*s)
+		}
+		s := (x - eta) / (1.0 - eta)
+		return a + (g1-a)*s*s, a*x + (g0-a)*eta/3.0 + (g1-a)*(1.0-eta)/3.0*s*s*s
+	}
+}
+
+// Forward returns the instantaneous forward rate in percent at maturity t
+func (mc *MonotoneConvex) Forward(t float64) float64 {
+	if mc.tau == nil {
+		panic("term structure is not properly initialized")
+	}
+	n := len(mc.tau) - 1
+	if t > mc.tau[n] {
+		return mc.f[n]*100.0 + mc.Spread*0.01
+	}
+	i, x := mc.segment(t)
+	value, _ := deviation(mc.f[i-1]-mc.fd[i], mc.f[i]-mc.fd[i], x)
+	return (mc.fd[i]+value)*100.0 + mc.Spread*0.01
+}
+
+// Rate returns the continuously compounded spot rate in percent
+func (mc *MonotoneConvex) Rate(t float64) float64 {
+	if mc.tau == nil {
+		panic("term structure is not properly initialized")
+	}
+	if t <= 0.0 {
+		return mc.f[0]*100.0 + mc.Spread*0.01
+	}
+	n := len(mc.tau) - 1
+	var y float64
+	if t > mc.tau[n] {
+		y = mc.Rates[n-1]*0.01*mc.tau[n] + mc.f[n]*(t-mc.tau[n])
+	} else {
+		i, x := mc.segment(t)
+		_, integral := deviation(mc.f[i-1]-mc.fd[i], mc.f[i]-mc.fd[i], x)
+		prev := 0.0
+		if i > 1 {
+			prev = mc.Rates[i-2] * 0.01 * mc.tau[i-1]
+		}
+		y = prev + mc.fd[i]*(t-mc.tau[i-1]) + (mc.tau[i]-mc.tau[i-1])*integral
+	}
+	return y/t*100.0 + mc.Spread*0.01
+}
+
+// Z returns the discount factor for the given maturity t
+func (mc *MonotoneConvex) Z(t float64) float64 {
+	return math.Exp(-mc.Rate(t) * 0.01 * t)
+}
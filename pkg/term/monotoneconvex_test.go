@@ -0,0 +1,60 @@
+package term_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestMonotoneConvex(t *testing.T) {
+	mc := term.MonotoneConvex{
+		Maturities: []float64{0.5, 1, 2, 3, 5, 7, 10, 20},
+		Rates:      []float64{0.8, 1.0, 1.5, 1.2, 1.8, 2.0, 2.1, 2.0},
+		Positive:   true,
+	}
+	if err := mc.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// node rates are matched exactly
+	for i, m := range mc.Maturities {
+		if math.Abs(mc.Rate(m)-mc.Rates[i]) > 1e-9 {
+			t.Errorf("maturity %v: got %v, expected %v", m, mc.Rate(m), mc.Rates[i])
+		}
+	}
+
+	// forward rates are positive and continuous at the nodes
+	for x := 0.0; x < 25.0; x += 0.01 {
+		if f := mc.Forward(x); f < 0.0 {
+			t.Errorf("negative forward rate %v at %v", f, x)
+		}
+	}
+	for _, m := range mc.Maturities {
+		if left, right := mc.Forward(m-1e-9), mc.Forward(m+1e-9); math.Abs(left-right) > 1e-6 {
+			t.Errorf("forward rate jumps from %v to %v at %v", left, right, m)
+		}
+	}
+
+	// the average forward rate between two nodes is the discrete forward rate
+	h := 1e-4
+	sum := 0.0
+	for x := 2.0 + h/2; x < 3.0; x += h {
+		sum += mc.Forward(x) * h
+	}
+	discrete := 1.2*3.0 - 1.5*2.0
+	if math.Abs(sum-discrete) > 1e-3 {
+		t.Errorf("got average forward rate %v, expected %v", sum, discrete)
+	}
+
+	// spread
+	mc.SetSpread(50.0)
+	if math.Abs(mc.Rate(5.0)-2.3) > 1e-9 {
+		t.Errorf("got rate %v with spread, expected 2.3", mc.Rate(5.0))
+	}
+
+	invalid := term.MonotoneConvex{Maturities: []float64{2, 1}, Rates: []float64{1.0, 1.0}}
+	if err := invalid.Init(); err == nil {
+		t.Errorf("expected error for decreasing maturities")
+	}
+}
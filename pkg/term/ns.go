@@ -0,0 +1,37 @@
+package term
+
+import "math"
+
+// NelsonSiegel represents the three-factor Nelson-Siegel spot-rate term
+// structure (level, slope and curvature with one decay parameter)
+type NelsonSiegel struct {
+	B0     float64 `json:"b0"`
+	B1     float64 `json:"b1"`
+	B2     float64 `json:"b2"`
+	T1     float64 `json:"t1"`
+	Spread float64 `json:"spread"`
+}
+
+// SetSpread sets the constant spread that is added to the continuously
+// compounded rate over all maturities
+func (ns *NelsonSiegel) SetSpread(s float64) Structure {
+	ns.Spread = s
+	return ns
+}
+
+// Rate returns the continuous compounded spot rate (in %) for a term maturity
+// of m years R_cc(0, m)
+func (ns *NelsonSiegel) Rate(m float64) float64 {
+	if m == 0.0 {
+		m = 1e-7
+	}
+	cc := ns.B0
+	cc += ns.B1 * ((1.0 - math.Exp(-m/ns.T1)) * ns.T1 / m)
+	cc += ns.B2 * (((1.0 - math.Exp(-m/ns.T1)) * ns.T1 / m) - math.Exp(-m/ns.T1))
+	return cc + ns.Spread*0.01
+}
+
+// Z return the discount factor for a term maturity of m years Z(0, m)
+func (ns *NelsonSiegel) Z(m float64) float64 {
+	return math.Exp(-ns.Rate(m) * 0.01 * m)
+}
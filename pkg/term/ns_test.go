@@ -0,0 +1,32 @@
+package term_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestNelsonSiegel(t *testing.T) {
+	ns := term.NelsonSiegel{B0: 2.5, B1: -1.5, B2: 1.0, T1: 2.0}
+	nss := term.NelsonSiegelSvensson{B0: 2.5, B1: -1.5, B2: 1.0, B3: 0.0, T1: 2.0, T2: 5.0}
+
+	for _, m := range []float64{0.0, 0.5, 1, 2, 5, 10, 30} {
+		if math.Abs(ns.Rate(m)-nss.Rate(m)) > 1e-12 {
+			t.Errorf("maturity %v: got %v, expected %v", m, ns.Rate(m), nss.Rate(m))
+		}
+	}
+
+	// short and long end
+	if math.Abs(ns.Rate(0.0)-1.0) > 1e-6 {
+		t.Errorf("got short rate %v, expected 1.0", ns.Rate(0.0))
+	}
+	if math.Abs(ns.Rate(1000.0)-2.5) > 0.01 {
+		t.Errorf("got long rate %v, expected 2.5", ns.Rate(1000.0))
+	}
+
+	ns.SetSpread(100.0)
+	if math.Abs(ns.Z(2.0)-math.Exp(-(nss.Rate(2.0)+1.0)*0.02)) > 1e-12 {
+		t.Errorf("spread not applied")
+	}
+}
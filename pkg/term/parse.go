@@ -1,15 +1,40 @@
 package term
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 )
 
 var (
+	// types maps the type tag of the json to the term structures
+	types = map[string]func() Structure{
+		"nss":            func() Structure { return &NelsonSiegelSvensson{} },
+		"ns":             func() Structure { return &NelsonSiegel{} },
+		"flat":           func() Structure { return &Flat{} },
+		"spline":         func() Structure { return &Spline{} },
+		"smithwilson":    func() Structure { return &SmithWilson{} },
+		"monotoneconvex": func() Structure { return &MonotoneConvex{} },
+		"hermite":        func() Structure { return &Hermite{} },
+		"bspline":        func() Structure { return &BSpline{} },
+		"issuer":         func() Structure { return &IssuerSpread{} },
+		"basis":          func() Structure { return &BasisSpread{} },
+	}
+
+	// registered contains the keys of the term structures to recognize json
+	// without type tag for ParseLegacy
 	registered = map[Structure][]string{
 		&NelsonSiegelSvensson{}: []string{"b0", "b1", "b2", "b3", "t1", "t2", "spread"},
 		&Flat{}:                 []string{"r", "spread"},
 		&Spline{}:               []string{"maturities", "discountfactors", "spread"},
+		&NelsonSiegel{}:         []string{"b0", "b1", "b2", "t1", "spread"},
+		&SmithWilson{}:          []string{"maturities", "rates", "ufr", "alpha", "spread"},
+		&MonotoneConvex{}:       []string{"maturities", "rates", "positive", "spread"},
+		&Hermite{}:              []string{"maturities", "discountfactors", "slopes", "spread"},
+		&BSpline{}:              []string{"knots", "coefficients", "spread"},
 	}
 )
 
@@ -17,31 +42,215 @@ type Initer interface {
 	Init() error
 }
 
+// Types returns the sorted type tags of the term structures
+func Types() []string {
+	names := []string{}
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TypeOf returns the type tag of the term structure
+func TypeOf(ts Structure) (string, error) {
+	for name, newStructure := range types {
+		if reflect.TypeOf(newStructure()) == reflect.TypeOf(ts) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown term structure %T", ts)
+}
+
+// envelope contains the type tag and, for spread curves, the base curve
+type envelope struct {
+	Type string          `json:"type"`
+	Base json.RawMessage `json:"base,omitempty"`
+}
+
+// Marshal returns the json of the term structure with its type tag, e.g.
+// {"type":"nss","b0":...}; the base curve of issuer and basis spreads is
+// included as "base"
+func Marshal(ts Structure) ([]byte, error) {
+	name, err := TypeOf(ts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(ts)
+	if err != nil {
+		return nil, err
+	}
+	env := envelope{Type: name}
+	if base := baseOf(ts); base != nil {
+		if env.Base, err = Marshal(base); err != nil {
+			return nil, err
+		}
+	}
+	tag, err := json.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+	// merge the type tag into the object of the term structure
+	if bytes.Equal(bytes.TrimSpace(data), []byte("{}")) {
+		return tag, nil
+	}
+	return append(tag[:len(tag)-1], append([]byte(","), data[1:]...)...), nil
+}
+
+// MarshalIndent is like Marshal but indents the json
+func MarshalIndent(ts Structure, prefix, indent string) ([]byte, error) {
+	data, err := Marshal(ts)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = json.Indent(&buf, data, prefix, indent)
+	return buf.Bytes(), err
+}
+
+// baseOf returns the base curve of spread curves
+func baseOf(ts Structure) Structure {
+	switch s := ts.(type) {
+	case *IssuerSpread:
+		return s.Base
+	case *BasisSpread:
+		return s.Base
+	}
+	return nil
+}
+
+// setBase parses the base curve of spread curves
+func setBase(ts Structure, data json.RawMessage) error {
+	var base *Structure
+	switch s := ts.(type) {
+	case *IssuerSpread:
+		base = &s.Base
+	case *BasisSpread:
+		base = &s.Base
+	default:
+		if len(data) > 0 {
+			return fmt.Errorf("unexpected base curve")
+		}
+		return nil
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("base curve missing")
+	}
+	var err error
+	if *base, err = Parse(data); err != nil {
+		return fmt.Errorf("base curve: %v", err)
+	}
+	return nil
+}
+
+// Parse returns the term structure of the json written by Marshal; json
+// without type tag is an error
 func Parse(data []byte) (Structure, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if env.Type == "" {
+		return nil, fmt.Errorf("type tag of term structure missing (types: %s); convert files of older versions with curves-cli -convert", strings.Join(Types(), ", "))
+	}
+	newStructure, ok := types[env.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown term structure type %q (types: %s)", env.Type, strings.Join(Types(), ", "))
+	}
+	ts := newStructure()
+	if err := json.Unmarshal(data, ts); err != nil {
+		return nil, err
+	}
+	if err := setBase(ts, env.Base); err != nil {
+		return nil, fmt.Errorf("%s: %v", env.Type, err)
+	}
+	return initialize(ts)
+}
+
+// ParseLegacy is like Parse but matches json without type tag, as written by
+// older versions, by its keys.
+//
+// Deprecated: the keys are ambiguous; convert the files with Marshal and use
+// Parse.
+func ParseLegacy(data []byte) (Structure, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if env.Type != "" {
+		return Parse(data)
+	}
+	return parseKeys(data)
+}
+
+// parseKeys returns the registered term structure with the most matching keys
+func parseKeys(data []byte) (Structure, error) {
 	// unmarshal data into map[string]interface{}
 	anonymous := make(map[string]interface{})
 	err := json.Unmarshal(data, &anonymous)
 	if err != nil {
 		return nil, err
 	}
+	// choose the registered term structure with the most matching keys
+	var match Structure
 	for term, keys := range registered {
 		for _, key := range keys {
 			if _, ok := anonymous[key]; !ok {
 				goto nextTerm
 			}
 		}
-		err = json.Unmarshal(data, term)
+		if match == nil || len(keys) > len(registered[match]) {
+			match = term
+		}
+	nextTerm:
+	}
+	if match != nil {
+		// use a new instance so that parsed structures do not share state
+		ts := reflect.New(reflect.TypeOf(match).Elem()).Interface().(Structure)
+		err = json.Unmarshal(data, ts)
 		if err != nil {
 			return nil, err
 		}
-		if toInit, ok := term.(Initer); ok {
-			if err := toInit.Init(); err != nil {
-				return term, err
-			}
-		}
-		return term, nil
-	nextTerm:
+		return initialize(ts)
 	}
 	return nil, fmt.Errorf("parsing into yield curve failed")
 
 }
+
+// Tagged wraps a term structure for fields of other types so that it is
+// marshalled with Marshal and unmarshalled with Parse (null for nil)
+type Tagged struct {
+	Structure
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (t Tagged) MarshalJSON() ([]byte, error) {
+	if t.Structure == nil {
+		return []byte("null"), nil
+	}
+	return Marshal(t.Structure)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (t *Tagged) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		t.Structure = nil
+		return nil
+	}
+	ts, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	t.Structure = ts
+	return nil
+}
+
+// initialize calls Init of the term structure, if any
+func initialize(ts Structure) (Structure, error) {
+	if toInit, ok := ts.(Initer); ok {
+		if err := toInit.Init(); err != nil {
+			return ts, err
+		}
+	}
+	return ts, nil
+}
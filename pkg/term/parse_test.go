@@ -1,13 +1,14 @@
 package term_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
 	"github.com/konimarti/fixedincome/pkg/term"
 )
 
-func TestParse(t *testing.T) {
+func TestParseLegacy(t *testing.T) {
 
 	testData := []struct {
 		Data []byte
@@ -25,10 +26,33 @@ func TestParse(t *testing.T) {
 			Data: []byte(" { \"r\": 0.0, \"spread\": 0.0 } "),
 			Type: &term.Flat{},
 		},
+		{
+			Data: []byte(" { \"b0\": 2.5, \"b1\": -1.5, \"b2\": 1.0, \"t1\": 2.0, \"spread\": 0.0 } "),
+			Type: &term.NelsonSiegel{},
+		},
+		{
+			Data: []byte(" { \"maturities\": [1, 5, 10], \"rates\": [0.5, 1.0, 1.5], \"ufr\": 3.45, \"alpha\": 0.1, \"spread\": 0.0 } "),
+			Type: &term.SmithWilson{},
+		},
+		{
+			Data: []byte(" { \"maturities\": [1, 5, 10], \"rates\": [0.5, 1.0, 1.5], \"positive\": true, \"spread\": 0.0 } "),
+			Type: &term.MonotoneConvex{},
+		},
+		{
+			Data: []byte(" { \"maturities\": [1, 5], \"discountfactors\": [0.99, 0.95], \"slopes\": \"monotone\", \"spread\": 0.0 } "),
+			Type: &term.Hermite{},
+		},
+		{
+			Data: []byte(" { \"knots\": [0, 5, 10], \"coefficients\": [1, 1, 1, 1, 1], \"spread\": 0.0 } "),
+			Type: &term.BSpline{},
+		},
 	}
 
 	for i, test := range testData {
-		ts, err := term.Parse(test.Data)
+		if _, err := term.Parse(test.Data); err == nil {
+			t.Errorf("test %d: expected error without type tag", i+1)
+		}
+		ts, err := term.ParseLegacy(test.Data)
 		if err != nil {
 			t.Errorf("test %d: %v", i+1, err)
 		}
@@ -37,3 +61,88 @@ func TestParse(t *testing.T) {
 		}
 	}
 }
+
+func TestMarshal(t *testing.T) {
+	structures := []term.Structure{
+		&term.NelsonSiegelSvensson{B0: -0.43381, B1: -0.308942, B2: 4.83643, B3: -4.10991, T1: 4.65211, T2: 3.33637, Spread: 17.0},
+		&term.NelsonSiegel{B0: 2.5, B1: -1.5, B2: 1.0, T1: 2.0},
+		&term.Flat{R: 1.0},
+		&term.MonotoneConvex{Maturities: []float64{1, 5, 10}, Rates: []float64{0.5, 1.0, 1.5}, Positive: true},
+		&term.Hermite{Maturities: []float64{1, 5}, DiscountFactors: []float64{0.99, 0.95}, Slopes: "monotone"},
+		&term.SmithWilson{Maturities: []float64{1, 5, 10}, Rates: []float64{0.5, 1.0, 1.5}, UFR: 3.45, Alpha: 0.1},
+		&term.BSpline{Knots: []float64{0, 5, 10}, Coefficients: []float64{1, 1, 1, 1, 1}},
+		&term.IssuerSpread{Base: &term.Flat{R: 1.0}, Shape: term.LinearShape, Parameters: []float64{50, 5}},
+		&term.BasisSpread{Base: &term.NelsonSiegel{B0: 2.5, B1: -1.5, B2: 1.0, T1: 2.0}, Maturities: []float64{1, 10}, Spreads: []float64{-20, -10}},
+	}
+	for _, ts := range structures {
+		if init, ok := ts.(term.Initer); ok {
+			if err := init.Init(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		data, err := term.Marshal(ts)
+		if err != nil {
+			t.Fatalf("%T: %v", ts, err)
+		}
+		parsed, err := term.Parse(data)
+		if err != nil {
+			t.Fatalf("%T: %v", ts, err)
+		}
+		if legacy, err := term.ParseLegacy(data); err != nil || reflect.TypeOf(legacy) != reflect.TypeOf(ts) {
+			t.Errorf("%T: got %T and error %v with ParseLegacy", ts, legacy, err)
+		}
+		if reflect.TypeOf(parsed) != reflect.TypeOf(ts) {
+			t.Errorf("got %T, expected %T", parsed, ts)
+			continue
+		}
+		for _, m := range []float64{0.5, 3.0, 12.0} {
+			if parsed.Rate(m) != ts.Rate(m) {
+				t.Errorf("%T: got rate %v at %v, expected %v", ts, parsed.Rate(m), m, ts.Rate(m))
+			}
+		}
+	}
+
+	// the type tag is the first key
+	data, err := term.Marshal(&term.Flat{R: 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"type":"flat","r":1,"spread":0}`; string(data) != expected {
+		t.Errorf("got %s, expected %s", data, expected)
+	}
+
+	invalid := []string{
+		`{"type": "cubic", "r": 1.0}`,
+		`{"type": "issuer", "shape": 0, "parameters": [50]}`,
+		`{"type": "flat", "r": 1.0, "base": {"type": "flat", "r": 1.0}}`,
+		`{"type": 1}`,
+	}
+	for _, data := range invalid {
+		if _, err := term.Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", data)
+		}
+	}
+}
+
+func TestTagged(t *testing.T) {
+	type holder struct {
+		Curve term.Tagged `json:"curve"`
+	}
+	data, err := json.Marshal(holder{term.Tagged{&term.Flat{R: 1.0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h holder
+	if err := json.Unmarshal(data, &h); err != nil {
+		t.Fatal(err)
+	}
+	if flat, ok := h.Curve.Structure.(*term.Flat); !ok || flat.R != 1.0 {
+		t.Errorf("got %s as %+v", data, h.Curve.Structure)
+	}
+	if data, _ = json.Marshal(holder{}); string(data) != `{"curve":null}` {
+		t.Errorf("got %s for nil curve", data)
+	}
+	if err := json.Unmarshal(data, &h); err != nil || h.Curve.Structure != nil {
+		t.Errorf("got %v and error %v for null", h.Curve.Structure, err)
+	}
+}
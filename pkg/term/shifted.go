@@ -0,0 +1,26 @@
+package term
+
+import "math"
+
+// Shifted represents a base term structure shifted in parallel by a spread in
+// bps; the spread of the base term structure itself is left untouched
+type Shifted struct {
+	Base   Structure
+	Spread float64
+}
+
+// SetSpread sets the shift in bps on top of the base term structure
+func (s *Shifted) SetSpread(spread float64) Structure {
+	s.Spread = spread
+	return s
+}
+
+// Rate returns the continuously compounded spot rate in percent
+func (s *Shifted) Rate(t float64) float64 {
+	return s.Base.Rate(t) + s.Spread*0.01
+}
+
+// Z returns the discount factor for the given maturity t
+func (s *Shifted) Z(t float64) float64 {
+	return s.Base.Z(t) * math.Exp(-s.Spread*0.0001*t)
+}
@@ -0,0 +1,24 @@
+package term_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestShifted(t *testing.T) {
+	base := term.Flat{R: 1.0, Spread: 20.0}
+	s := term.Shifted{Base: &base}
+	s.SetSpread(30.0)
+
+	if got, expected := s.Rate(3.0), 1.5; math.Abs(got-expected) > 1e-12 {
+		t.Errorf("wrong rate; got: %v, expected: %v", got, expected)
+	}
+	if got, expected := s.Z(3.0), math.Exp(-0.015*3.0); math.Abs(got-expected) > 1e-12 {
+		t.Errorf("wrong discount factor; got: %v, expected: %v", got, expected)
+	}
+	if base.Spread != 20.0 {
+		t.Errorf("spread of base term structure changed; got: %v", base.Spread)
+	}
+}
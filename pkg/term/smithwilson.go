@@ -0,0 +1,107 @@
+package term
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SmithWilson represents the Smith-Wilson term structure which fits the
+// observed zero rates exactly and extrapolates the forward rates beyond the
+// last liquid point towards the ultimate forward rate (e.g. as required under
+// Solvency II and the Swiss Solvency Test). Call Init before use.
+type SmithWilson struct {
+	// Maturities of the liquid zero rates in years
+	Maturities []float64 `json:"maturities"`
+	// Rates are the continuously compounded zero rates in percent
+	Rates []float64 `json:"rates"`
+	// UFR is the ultimate forward rate in percent (annually compounded as
+	// published by the regulators, e.g. 3.45)
+	UFR float64 `json:"ufr"`
+	// Alpha is the speed of convergence towards the ultimate forward rate
+	Alpha  float64 `json:"alpha"`
+	Spread float64 `json:"spread"`
+	zeta   []float64
+}
+
+// SetSpread sets the spread in bps
+func (sw *SmithWilson) SetSpread(spread float64) Structure {
+	sw.Spread = spread
+	return sw
+}
+
+// omega returns the continuously compounded ultimate forward rate
+func (sw *SmithWilson) omega() float64 {
+	return math.Log(1.0 + sw.UFR/100.0)
+}
+
+// wilson returns the Wilson function W(t, u)
+func (sw *SmithWilson) wilson(t, u float64) float64 {
+	a := sw.Alpha
+	lo, hi := math.Min(t, u), math.Max(t, u)
+	return math.Exp(-sw.omega()*(t+u)) * (a*lo - 0.5*math.Exp(-a*hi)*(math.Exp(a*lo)-math.Exp(-a*lo)))
+}
+
+// Init solves for the weights of the Wilson functions
+func (sw *SmithWilson) Init() error {
+	n := len(sw.Maturities)
+	if n == 0 || n != len(sw.Rates) {
+		return fmt.Errorf("number of maturities and rates do not match")
+	}
+	if sw.Alpha <= 0.0 {
+		return fmt.Errorf("alpha must be positive")
+	}
+	w := mat.NewDense(n, n, nil)
+	b := mat.NewVecDense(n, nil)
+	for i, u := range sw.Maturities {
+		for j, v := range sw.Maturities {
+			w.Set(i, j, sw.wilson(u, v))
+		}
+		b.SetVec(i, math.Exp(-sw.Rates[i]*0.01*u)-math.Exp(-sw.omega()*u))
+	}
+	var zeta mat.VecDense
+	if err := zeta.SolveVec(w, b); err != nil {
+		return err
+	}
+	sw.zeta = make([]float64, n)
+	for i := range sw.zeta {
+		sw.zeta[i] = zeta.AtVec(i)
+	}
+	return nil
+}
+
+// price returns the discount factor without spread
+func (sw *SmithWilson) price(t float64) float64 {
+	if sw.zeta == nil {
+		panic("term structure is not properly initialized")
+	}
+	p := math.Exp(-sw.omega() * t)
+	for j, u := range sw.Maturities {
+		p += sw.zeta[j] * sw.wilson(t, u)
+	}
+	return p
+}
+
+// Rate returns the continuously compounded spot rate in percent
+func (sw *SmithWilson) Rate(t float64) float64 {
+	if t == 0.0 {
+		t = 1e-7
+	}
+	return -math.Log(sw.price(t))/t*100.0 + sw.Spread*0.01
+}
+
+// Z returns the discount factor for the given maturity t
+func (sw *SmithWilson) Z(t float64) float64 {
+	return math.Exp(-sw.Rate(t) * 0.01 * t)
+}
+
+// Forward returns the continuously compounded instantaneous forward rate in
+// percent at maturity t
+func (sw *SmithWilson) Forward(t float64) float64 {
+	h := 1e-4
+	if t < h {
+		t = h
+	}
+	return -(math.Log(sw.Z(t+h)) - math.Log(sw.Z(t-h))) / (2.0 * h) * 100.0
+}
@@ -0,0 +1,47 @@
+package term_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestSmithWilson(t *testing.T) {
+	sw := term.SmithWilson{
+		Maturities: []float64{1, 2, 3, 5, 7, 10, 15, 20},
+		Rates:      []float64{0.2, 0.4, 0.6, 0.9, 1.1, 1.3, 1.5, 1.6},
+		UFR:        3.45,
+		Alpha:      0.1,
+	}
+	if err := sw.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// observed rates are matched exactly
+	for i, m := range sw.Maturities {
+		if math.Abs(sw.Rate(m)-sw.Rates[i]) > 1e-9 {
+			t.Errorf("maturity %v: got %v, expected %v", m, sw.Rate(m), sw.Rates[i])
+		}
+	}
+
+	// forward rates converge to the ultimate forward rate
+	ufr := math.Log(1.0+0.0345) * 100.0
+	if f := sw.Forward(120.0); math.Abs(f-ufr) > 0.01 {
+		t.Errorf("got forward rate %v at 120 years, expected %v", f, ufr)
+	}
+	if math.Abs(sw.Forward(60.0)-ufr) < math.Abs(sw.Forward(120.0)-ufr) {
+		t.Errorf("forward rates do not converge")
+	}
+
+	// spread
+	sw.SetSpread(50.0)
+	if math.Abs(sw.Rate(5.0)-1.4) > 1e-9 {
+		t.Errorf("got rate %v with spread, expected 1.4", sw.Rate(5.0))
+	}
+
+	invalid := term.SmithWilson{Maturities: []float64{1, 2}, Rates: []float64{1.0}, UFR: 3.45, Alpha: 0.1}
+	if err := invalid.Init(); err == nil {
+		t.Errorf("expected error for wrong number of rates")
+	}
+}
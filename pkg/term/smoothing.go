@@ -0,0 +1,163 @@
+package term
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Instrument is a set of cash flows with an observed (dirty) price which is
+// used to fit a term structure
+type Instrument struct {
+	// Times of the cash flows in years
+	Times []float64
+	// Amounts of the cash flows
+	Amounts []float64
+	// Price is the observed dirty price
+	Price float64
+	// Weight of the squared pricing error (defaults to 1.0)
+	Weight float64
+}
+
+// VRP returns the variable roughness penalty of Waggoner (1997) which allows
+// for more curvature of the forward rates at the short end
+func VRP(t float64) float64 {
+	switch {
+	case t <= 1.0:
+		return 0.1
+	case t <= 10.0:
+		return 100.0
+	default:
+		return 100000.0
+	}
+}
+
+var (
+	// MaxIterations is the maximum number of Gauss-Newton iterations of the
+	// fit
+	MaxIterations = 100
+	// Tolerance is the largest change of the coefficients at convergence
+	Tolerance = 1e-10
+)
+
+// subintervals per knot interval for the integration of the roughness penalty
+const subintervals = 16
+
+// roughness returns the matrix of the integrated products of the second
+// derivatives of the B-splines weighted with lambda
+func (b *BSpline) roughness(lambda func(float64) float64) [][]float64 {
+	nc := len(b.Coefficients)
+	omega := make([][]float64, nc)
+	for k := range omega {
+		omega[k] = make([]float64, nc)
+	}
+	for i := 1; i < len(b.Knots); i += 1 {
+		h := (b.Knots[i] - b.Knots[i-1]) / subintervals
+		for s := 0; s < subintervals; s += 1 {
+			lo := b.Knots[i-1] + float64(s)*h
+			for _, g := range gauss {
+				x := lo + g[0]*h
+				w := g[1] * h * lambda(x)
+				d2 := basis(b.u, degree, 2, x)
+				for k := range d2 {
+					for l := range d2 {
+						omega[k][l] += w * d2[k] * d2[l]
+					}
+				}
+			}
+		}
+	}
+	return omega
+}
+
+// FitSmoothing fits the instantaneous forward rate curve as a cubic B-spline
+// with the given knots to the prices of the instruments by minimizing the
+// weighted squared pricing errors plus the roughness penalty, i.e. the
+// integral of lambda(t) times the squared second derivative of the forward
+// rates (Fisher, Nychka and Zervos (1995) with a constant lambda, Waggoner
+// (1997) with VRP)
+func FitSmoothing(instruments []Instrument, knots []float64, lambda func(float64) float64) (*BSpline, error) {
+	if len(instruments) == 0 {
+		return nil, fmt.Errorf("no instruments given")
+	}
+	if lambda == nil {
+		lambda = VRP
+	}
+	b := &BSpline{
+		Knots:        knots,
+		Coefficients: make([]float64, len(knots)+degree-1),
+	}
+	if err := b.Init(); err != nil {
+		return nil, err
+	}
+	nc := len(b.Coefficients)
+	omega := b.roughness(lambda)
+
+	// the integrated forward rates are linear in the coefficients
+	integrals := make([][][]float64, len(instruments))
+	for i, inst := range instruments {
+		if len(inst.Times) != len(inst.Amounts) {
+			return nil, fmt.Errorf("instrument %d: number of times and amounts do not match", i)
+		}
+		integrals[i] = make([][]float64, len(inst.Times))
+		for j, t := range inst.Times {
+			integrals[i][j] = b.integrals(t)
+		}
+	}
+
+	// Gauss-Newton iterations on the penalized least-squares problem
+	c := b.Coefficients
+	for iter := 0; iter < MaxIterations; iter += 1 {
+		lhs := mat.NewSymDense(nc, nil)
+		rhs := mat.NewVecDense(nc, nil)
+		for k := 0; k < nc; k += 1 {
+			for l := k; l < nc; l += 1 {
+				lhs.SetSym(k, l, omega[k][l])
+			}
+			rhs.SetVec(k, -dot(omega[k], c))
+		}
+		for i, inst := range instruments {
+			value := 0.0
+			jac := make([]float64, nc)
+			for j, a := range inst.Amounts {
+				cf := a * math.Exp(-0.01*dot(c, integrals[i][j]))
+				value += cf
+				for k, v := range integrals[i][j] {
+					jac[k] -= 0.01 * cf * v
+				}
+			}
+			w := inst.Weight
+			if w == 0.0 {
+				w = 1.0
+			}
+			for k := 0; k < nc; k += 1 {
+				for l := k; l < nc; l += 1 {
+					lhs.SetSym(k, l, lhs.At(k, l)+w*jac[k]*jac[l])
+				}
+				rhs.SetVec(k, rhs.AtVec(k)-w*jac[k]*(value-inst.Price))
+			}
+		}
+		var step mat.VecDense
+		if err := step.SolveVec(lhs, rhs); err != nil {
+			return nil, err
+		}
+		change := 0.0
+		for k := range c {
+			c[k] += step.AtVec(k)
+			change = math.Max(change, math.Abs(step.AtVec(k)))
+		}
+		if change < Tolerance {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("fit did not converge after %d iterations", MaxIterations)
+}
+
+func dot(x, y []float64) float64 {
+	sum := 0.0
+	for i := range x {
+		sum += x[i] * y[i]
+	}
+	return sum
+}
@@ -0,0 +1,64 @@
+package term_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestFitSmoothing(t *testing.T) {
+	// bonds priced off a Nelson-Siegel-Svensson curve
+	nss := term.NelsonSiegelSvensson{B0: 2.0, B1: -1.5, B2: 1.0, B3: -0.5, T1: 2.0, T2: 8.0}
+	instruments := []term.Instrument{}
+	maturities := []float64{}
+	for _, m := range []float64{0.5, 1, 2, 3, 4, 5, 6, 7, 8, 10, 12, 15, 20, 25, 30} {
+		inst := term.Instrument{}
+		for x := m; x > 0.0; x -= 1.0 {
+			inst.Times = append(inst.Times, x)
+			inst.Amounts = append(inst.Amounts, 2.0)
+		}
+		inst.Amounts[0] += 100.0
+		for i, x := range inst.Times {
+			inst.Price += inst.Amounts[i] * nss.Z(x)
+		}
+		instruments = append(instruments, inst)
+		maturities = append(maturities, m)
+	}
+
+	knots, err := term.Knots(maturities, 5, term.EqualCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	curve, err := term.FitSmoothing(instruments, knots, func(float64) float64 { return 0.01 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []float64{1, 2, 5, 10, 20, 30} {
+		if math.Abs(curve.Rate(m)-nss.Rate(m)) > 0.05 {
+			t.Errorf("maturity %v: got rate %v, expected %v", m, curve.Rate(m), nss.Rate(m))
+		}
+	}
+
+	// a large roughness penalty flattens the forward curve
+	smooth, err := term.FitSmoothing(instruments, knots, func(float64) float64 { return 1e6 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	roughness := func(b *term.BSpline) float64 {
+		sum := 0.0
+		for x := 0.5; x < 30.0; x += 0.5 {
+			d := b.Forward(x+0.5) - 2.0*b.Forward(x) + b.Forward(x-0.5)
+			sum += d * d
+		}
+		return sum
+	}
+	if roughness(smooth) >= roughness(curve) {
+		t.Errorf("roughness penalty does not smooth the forward curve")
+	}
+
+	// default penalty
+	if _, err := term.FitSmoothing(instruments, knots, nil); err != nil {
+		t.Errorf("VRP: %v", err)
+	}
+}
@@ -6,6 +6,21 @@ type Security interface {
 	PresentValue(ts term.Structure) float64
 }
 
+// Valuer is implemented by securities whose valuation can fail, e.g. for
+// missing fixings; their PresentValue returns NaN in that case
+type Valuer interface {
+	Value(ts term.Structure) (float64, error)
+}
+
+// Value returns the present value of the security and the error of its
+// valuation if it implements Valuer
+func Value(s Security, ts term.Structure) (float64, error) {
+	if v, ok := s.(Valuer); ok {
+		return v.Value(ts)
+	}
+	return s.PresentValue(ts), nil
+}
+
 type TermSecurity interface {
 	Security
 	Duration(ts term.Structure) float64
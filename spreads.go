@@ -0,0 +1,158 @@
+package fixedincome
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/khezen/rootfinding"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/instrument/swap"
+	"github.com/konimarti/fixedincome/pkg/rate"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+// SpreadAnalysis contains the spread measures (in bps) of a bond for a given
+// "dirty" price side by side
+type SpreadAnalysis struct {
+	// Yield is the continuously compounded yield to maturity in percent
+	Yield float64
+	// GSpread is the spread of the yield over the government curve
+	GSpread float64
+	// ISpread is the spread of the yield over the swap rate
+	ISpread float64
+	// ZSpread is the static spread over the swap curve
+	ZSpread float64
+	// ParPar is the par-par asset-swap spread
+	ParPar float64
+	// MarketValue is the market-value asset-swap spread
+	MarketValue float64
+}
+
+// GSpread calculates the spread (in bps) of the yield to maturity over the
+// continuously compounded government rate interpolated at the bond's maturity
+func GSpread(investment float64, b *bond.Straight, govt term.Structure) (float64, error) {
+	yield, err := Irr(investment, b)
+	if err != nil {
+		return 0.0, err
+	}
+	return (yield - govt.Rate(b.Last())) * 100.0, nil
+}
+
+// ISpread calculates the spread (in bps) of the yield to maturity over the
+// swap rate for the bond's payment dates (converted to continuous compounding)
+func ISpread(investment float64, b *bond.Straight, swapCurve term.Structure) (float64, error) {
+	yield, err := Irr(investment, b)
+	if err != nil {
+		return 0.0, err
+	}
+	maturities := b.M()
+	if len(maturities) == 0 {
+		return 0.0, fmt.Errorf("bond has no outstanding cash flows")
+	}
+	sort.Float64s(maturities)
+	swapRate, err := swap.InterestRate(maturities, b.Compounding(), swapCurve)
+	if err != nil {
+		return 0.0, err
+	}
+	return (yield - rate.Continuous(swapRate, b.Compounding())) * 100.0, nil
+}
+
+// floatingAnnuity returns the value of a floating leg paying 1 per year on
+// the bond's payment dates
+func floatingAnnuity(b *bond.Straight, ts term.Structure) float64 {
+	annuity := 0.0
+	n := float64(b.Compounding())
+	for _, m := range b.M() {
+		annuity += ts.Z(m) / n
+	}
+	return annuity
+}
+
+// AssetSwapSpread calculates the par-par asset-swap spread (in bps), i.e. the
+// spread over the floating rate that an investor receives when buying the
+// bond at par and swapping its fixed coupons into floating payments
+func AssetSwapSpread(investment float64, b *bond.Straight, swapCurve term.Structure) (float64, error) {
+	annuity := floatingAnnuity(b, swapCurve)
+	if annuity == 0.0 || b.Redemption == 0.0 {
+		return 0.0, fmt.Errorf("annuity of floating leg is zero")
+	}
+	return (b.PresentValue(swapCurve) - investment) / (b.Redemption * annuity) * 10000.0, nil
+}
+
+// MarketValueAssetSwapSpread calculates the market-value asset-swap spread (in
+// bps) where the notional of the floating leg equals the market value of the bond
+func MarketValueAssetSwapSpread(investment float64, b *bond.Straight, swapCurve term.Structure) (float64, error) {
+	annuity := floatingAnnuity(b, swapCurve)
+	if annuity == 0.0 || investment == 0.0 {
+		return 0.0, fmt.Errorf("annuity of floating leg is zero")
+	}
+	return (b.PresentValue(swapCurve) - investment) / (investment * annuity) * 10000.0, nil
+}
+
+// DiscountMargin calculates the discount margin (in bps) of a floating-rate
+// bond paying the index plus the quoted margin (in bps). The next coupon is
+// known, while the later coupons are projected with the forward rates of the
+// term structure. The discount margin is the spread over the term structure
+// that discounts the projected cash flows to the "dirty" price.
+func DiscountMargin(investment float64, f *bond.Floating, margin float64, ts term.Structure) (float64, error) {
+	maturities := f.M()
+	sort.Float64s(maturities)
+	if len(maturities) == 0 {
+		return 0.0, fmt.Errorf("bond has no outstanding cash flows")
+	}
+
+	// projected cash flows
+	n := float64(f.Compounding())
+	cashflows := make([]float64, len(maturities))
+	for i, m := range maturities {
+		coupon := f.Rate
+		if i > 0 {
+			prev := maturities[i-1]
+			forward := (ts.Z(prev)/ts.Z(m) - 1.0) * n * 100.0
+			coupon = forward + margin*0.01
+		}
+		cashflows[i] = coupon / n
+	}
+	cashflows[len(cashflows)-1] += f.Redemption
+
+	shifted := &term.Shifted{Base: ts}
+	fn := func(dm float64) float64 {
+		shifted.SetSpread(dm)
+		value := 0.0
+		for i, m := range maturities {
+			value += cashflows[i] * shifted.Z(m)
+		}
+		return value - investment
+	}
+
+	return rootfinding.Brent(fn, -10000.0, 10000.0, Precision)
+}
+
+// Spreads calculates the spread measures of a straight bond for the given
+// "dirty" price versus the government and swap curves. The z-spread is the
+// spread on top of the swap curve; the curves are not modified.
+func Spreads(investment float64, b *bond.Straight, govt, swapCurve term.Structure) (SpreadAnalysis, error) {
+	var (
+		s   SpreadAnalysis
+		err error
+	)
+	if s.Yield, err = Irr(investment, b); err != nil {
+		return s, err
+	}
+	if s.GSpread, err = GSpread(investment, b, govt); err != nil {
+		return s, err
+	}
+	if s.ISpread, err = ISpread(investment, b, swapCurve); err != nil {
+		return s, err
+	}
+	if s.ParPar, err = AssetSwapSpread(investment, b, swapCurve); err != nil {
+		return s, err
+	}
+	if s.MarketValue, err = MarketValueAssetSwapSpread(investment, b, swapCurve); err != nil {
+		return s, err
+	}
+	if s.ZSpread, err = Spread(investment, b, &term.Shifted{Base: swapCurve}); err != nil {
+		return s, err
+	}
+	return s, nil
+}
@@ -0,0 +1,115 @@
+package fixedincome_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/konimarti/fixedincome"
+	"github.com/konimarti/fixedincome/pkg/instrument/bond"
+	"github.com/konimarti/fixedincome/pkg/maturity"
+	"github.com/konimarti/fixedincome/pkg/term"
+)
+
+func TestSpreads(t *testing.T) {
+	b := &bond.Straight{
+		Schedule: maturity.Schedule{
+			Settlement: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+			Maturity:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			Frequency:  1,
+		},
+		Redemption: 100.0,
+		Coupon:     2.0,
+	}
+	govt := &term.Flat{R: 1.0}
+	swapCurve := &term.Flat{R: 1.5}
+
+	// bond priced on the swap curve
+	price := b.PresentValue(swapCurve)
+	s, err := fixedincome.Spreads(price, b, govt, swapCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(s.Yield-1.5) > 1e-4 {
+		t.Errorf("got yield %v, expected 1.5", s.Yield)
+	}
+	if math.Abs(s.GSpread-50.0) > 0.01 {
+		t.Errorf("got g-spread %v, expected 50.0", s.GSpread)
+	}
+	for name, value := range map[string]float64{
+		"i-spread":   s.ISpread,
+		"z-spread":   s.ZSpread,
+		"par-par":    s.ParPar,
+		"market asw": s.MarketValue,
+	} {
+		if math.Abs(value) > 0.01 {
+			t.Errorf("got %s %v, expected 0.0", name, value)
+		}
+	}
+
+	// cheaper bond
+	price -= 2.0
+	s, err = fixedincome.Spreads(price, b, govt, swapCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ISpread <= 0.0 || s.ZSpread <= 0.0 || s.ParPar <= 0.0 {
+		t.Errorf("expected positive spreads, got %+v", s)
+	}
+	if math.Abs(s.MarketValue-s.ParPar*b.Redemption/price) > 1e-6 {
+		t.Errorf("got market value asw %v, expected %v", s.MarketValue, s.ParPar*b.Redemption/price)
+	}
+	if math.Abs(s.ZSpread-s.ParPar) > 5.0 {
+		t.Errorf("z-spread %v and par-par spread %v are too far apart", s.ZSpread, s.ParPar)
+	}
+	if swapCurve.Spread != 0.0 {
+		t.Errorf("spread of swap curve changed: %v", swapCurve.Spread)
+	}
+
+	// spread of the swap curve is kept
+	swapCurve.SetSpread(25.0)
+	if _, err = fixedincome.Spreads(price, b, govt, swapCurve); err != nil {
+		t.Fatal(err)
+	}
+	if swapCurve.Spread != 25.0 {
+		t.Errorf("spread of swap curve changed: %v", swapCurve.Spread)
+	}
+}
+
+func TestDiscountMargin(t *testing.T) {
+	f := &bond.Floating{
+		Schedule: maturity.Schedule{
+			Settlement: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+			Maturity:   time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			Frequency:  4,
+		},
+		Rate:       1.2,
+		Redemption: 100.0,
+	}
+	ts := &term.NelsonSiegelSvensson{B0: 2.0, B1: -1.0, B2: 0.5, B3: 0.1, T1: 2.0, T2: 5.0}
+
+	testData := []struct {
+		Price    float64
+		Margin   float64
+		Expected float64
+		Epsilon  float64
+	}{
+		{Price: f.PresentValue(ts), Margin: 0.0, Expected: 0.0, Epsilon: 1e-4},
+		// the known next coupon does not include the margin
+		{Price: f.PresentValue(ts), Margin: 25.0, Expected: 23.5, Epsilon: 0.5},
+		{Price: f.PresentValue(ts) - 1.0, Margin: 0.0, Expected: 33.0, Epsilon: 3.0},
+	}
+
+	for nr, test := range testData {
+		dm, err := fixedincome.DiscountMargin(test.Price, f, test.Margin, ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(dm-test.Expected) > test.Epsilon {
+			t.Errorf("test nr %d, got %v, expected %v", nr, dm, test.Expected)
+		}
+		if ts.Spread != 0.0 {
+			t.Errorf("test nr %d, spread of term structure changed: %v", nr, ts.Spread)
+		}
+	}
+}